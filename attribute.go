@@ -13,6 +13,12 @@ import (
 type Attribute struct {
 	Name  string
 	Value string
+
+	// Line is the one-based line number at which the attribute starts in the input. It is zero when unknown.
+	Line int
+
+	// Raw is the original text of the attribute. It is only set in lossless mode.
+	Raw *RawAttribute
 }
 
 // NewAttribute creates an Attribute from name and value byte slices, normalizing the key to lowercase and cleaning the
@@ -92,6 +98,12 @@ func newAttribute(name []byte, value []byte) Attribute {
 
 // parseAttributes parses the given buffer into a slice of Attributes.
 func parseAttributes(buf []byte) ([]Attribute, error) {
+	return parseAttributesAt(buf, nil)
+}
+
+// parseAttributesAt parses the given buffer into a slice of Attributes. The lines slice holds the input line number
+// of every line in the buffer, and is used to locate attributes and errors. When nil, lines are numbered from one.
+func parseAttributesAt(buf []byte, lines []int) ([]Attribute, error) {
 	if len(buf) == 0 {
 		return nil, errors.New("parseAttributes: object cannot be null")
 	}
@@ -99,23 +111,62 @@ func parseAttributes(buf []byte) ([]Attribute, error) {
 	// Pre-allocate attributes slice - typical RPSL objects have 5-15 attributes.
 	attributes := make([]Attribute, 0, 16)
 	pos := 0
+	line := 0
 
 	for pos < len(buf) {
 		key, newPos, err := parseKey(buf, pos)
 		if err != nil {
-			return nil, err
+			return nil, newParseError(buf, lineNumber(lines, line), pos, newPos, key, err)
 		}
 
-		pos = newPos
-		value, newPos := parseValue(buf, pos)
-		pos = newPos
+		value, newPos := parseValue(buf, newPos)
+
+		attribute := newAttribute(key, value)
+		attribute.Line = lineNumber(lines, line)
+		attributes = append(attributes, attribute)
 
-		attributes = append(attributes, newAttribute(key, value))
+		line += bytes.Count(buf[pos:newPos], []byte{'\n'})
+		pos = newPos
 	}
 
 	return attributes, nil
 }
 
+// lineNumber returns the input line number of the line at the given index of an object buffer.
+func lineNumber(lines []int, index int) int {
+	if index < len(lines) {
+		return lines[index]
+	}
+
+	return index + 1
+}
+
+// maxSnippetLen is the maximum length of the snippet recorded in a ParseError.
+const maxSnippetLen = 80
+
+// newParseError creates a ParseError for an error found at pos, on the line starting at start.
+func newParseError(buf []byte, line int, start int, pos int, key []byte, err error) *ParseError {
+	end := bytes.IndexByte(buf[start:], '\n')
+	if end < 0 {
+		end = len(buf)
+	} else {
+		end += start
+	}
+
+	snippet := bytes.TrimRight(buf[start:end], "\r")
+	if len(snippet) > maxSnippetLen {
+		snippet = snippet[:maxSnippetLen]
+	}
+
+	return &ParseError{
+		Line:      line,
+		Column:    pos - start + 1,
+		Attribute: string(key),
+		Snippet:   string(snippet),
+		Err:       err,
+	}
+}
+
 // isValidKeyChar returns true if c is allowed in a key.
 func isValidKeyChar(c byte) bool {
 	return (c >= 'a' && c <= 'z') ||
@@ -130,7 +181,7 @@ func isLineContinuationChar(c byte) bool {
 }
 
 // parseKey extracts a key ending at the first ':' and returns the key, the position after the colon, and an error if
-// any.
+// any. On error, the key read so far and the position of the offending character are returned.
 func parseKey(buf []byte, pos int) ([]byte, int, error) {
 	start := pos
 
@@ -138,20 +189,20 @@ func parseKey(buf []byte, pos int) ([]byte, int, error) {
 		c := buf[pos]
 		if c == ':' {
 			if pos == start {
-				return nil, 0, fmt.Errorf("parseKey: zero-sized key at pos %d", pos)
+				return nil, pos, errors.New("parseKey: zero-sized key")
 			}
 
 			return buf[start:pos], pos + 1, nil
 		}
 
 		if !isValidKeyChar(c) {
-			return nil, 0, fmt.Errorf("parseKey: illegal character '%c' at pos %d", c, pos)
+			return buf[start:pos], pos, fmt.Errorf("parseKey: illegal character '%c'", c)
 		}
 
 		pos++
 	}
 
-	return nil, 0, fmt.Errorf("parseKey: no key found starting at pos %d", start)
+	return buf[start:pos], start, errors.New("parseKey: no key found")
 }
 
 // parseValue extracts a value until a newline that is not followed by a continuation char.
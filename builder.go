@@ -0,0 +1,64 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+// Builder constructs an Object attribute by attribute, and validates it against the template of its class. The
+// attributes are placed as by Object.Add, so that the source attribute stays last.
+//
+// Example:
+//
+//	obj, err := NewBuilder("mntner", "EXAMPLE-MNT").
+//	    Add("admin-c", "JD1-TEST").
+//	    Add("upd-to", "noc@example.net").
+//	    Add("auth", "SSO noc@example.net").
+//	    Add("source", "RIPE").
+//	    Add("mnt-by", "EXAMPLE-MNT").
+//	    Build()
+type Builder struct {
+	registry *Registry
+	obj      Object
+}
+
+// NewBuilder returns a Builder of an object of the class, whose class attribute has the value key, validated against
+// the templates of the DefaultRegistry.
+func NewBuilder(class string, key string) *Builder {
+	return NewBuilderWithRegistry(DefaultRegistry, class, key)
+}
+
+// NewBuilderWithRegistry returns a Builder of an object of the class, whose class attribute has the value key,
+// validated against the templates of the registry.
+func NewBuilderWithRegistry(registry *Registry, class string, key string) *Builder {
+	b := &Builder{registry: registry}
+	b.obj.Add(class, key)
+	return b
+}
+
+// Add adds an attribute for every value.
+func (b *Builder) Add(key string, values ...string) *Builder {
+	for _, value := range values {
+		b.obj.Add(key, value)
+	}
+
+	return b
+}
+
+// Set sets the value of an attribute, replacing the values already added.
+func (b *Builder) Set(key string, value string) *Builder {
+	b.obj.Set(key, value)
+	return b
+}
+
+// Remove removes the attributes with a given key.
+func (b *Builder) Remove(key string) *Builder {
+	b.obj.RemoveAll(key)
+	return b
+}
+
+// Build returns a copy of the object built so far, and the ValidationErrors reported by the template of its class,
+// if any. The object is returned even if it is invalid.
+func (b *Builder) Build() (*Object, error) {
+	obj := &Object{Attributes: make([]Attribute, len(b.obj.Attributes))}
+	copy(obj.Attributes, b.obj.Attributes)
+	return obj, b.registry.Validate(obj)
+}
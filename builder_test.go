@@ -0,0 +1,58 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder("mntner", "EXAMPLE-MNT").
+		Add("admin-c", "JD1-TEST").
+		Add("upd-to", "noc@example.net").
+		Add("auth", "SSO noc@example.net").
+		Add("source", "TEST").
+		Add("mnt-by", "EXAMPLE-MNT", "OTHER-MNT")
+
+	obj, err := b.Build()
+	if err != nil {
+		t.Fatalf(`Build => %v`, err)
+	}
+
+	expected := "mntner:EXAMPLE-MNT\nadmin-c:JD1-TEST\nupd-to:noc@example.net\nauth:SSO noc@example.net\n" +
+		"mnt-by:EXAMPLE-MNT\nmnt-by:OTHER-MNT\nsource:TEST"
+	if obj.String() != expected {
+		t.Errorf(`Build => %q, want %q`, obj.String(), expected)
+	}
+
+	// The built object is a copy.
+	b.Set("mnt-by", "EXAMPLE-MNT")
+	if obj.String() != expected {
+		t.Errorf(`Build => the object changed with the Builder`)
+	}
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		errors  int
+	}{
+		{"MissingAttribute", NewBuilder("mntner", "EXAMPLE-MNT").Add("source", "TEST"), 4},
+		{"UnknownAttribute", NewBuilder("route", "192.0.2.0/24").Add("origin", "AS64496").Add("mnt-by", "A-MNT").Add("source", "TEST").Add("colour", "blue"), 1},
+		{"InvalidValue", NewBuilder("route", "192.0.2.0/24").Add("origin", "64496").Add("mnt-by", "A-MNT").Add("source", "TEST"), 1},
+		{"Removed", NewBuilder("route", "192.0.2.0/24").Add("origin", "AS64496").Add("mnt-by", "A-MNT").Add("source", "TEST").Remove("origin"), 1},
+		{"UnknownClass", NewBuilder("unknown", "value"), 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.builder.Build()
+
+			var violations ValidationErrors
+			if !errors.As(err, &violations) || len(violations) != tc.errors {
+				t.Errorf(`Build => %v, want %d violations`, err, tc.errors)
+			}
+		})
+	}
+}
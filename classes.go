@@ -0,0 +1,763 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"net/netip"
+	"time"
+)
+
+// AutNum is an aut-num object, describing an autonomous system and its routing policy.
+type AutNum struct {
+	ASN           uint32
+	ASName        string
+	Descr         []string
+	MemberOf      []string
+	ImportVia     []string
+	Import        []string
+	MPImport      []string
+	ExportVia     []string
+	Export        []string
+	MPExport      []string
+	Default       []string
+	MPDefault     []string
+	Remarks       []string
+	Org           string
+	SponsoringOrg string
+	AdminC        []string
+	TechC         []string
+	AbuseC        string
+	Status        string
+	Notify        []string
+	MntBy         []string
+	Created       time.Time
+	LastModified  time.Time
+	Source        string
+}
+
+// NewAutNum converts an aut-num Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed.
+func NewAutNum(o *Object) (*AutNum, error) {
+	d := newDecoder(o, "aut-num")
+	a := &AutNum{
+		ASN:           d.asn("aut-num"),
+		ASName:        d.value("as-name"),
+		Descr:         d.values("descr"),
+		MemberOf:      d.list("member-of"),
+		ImportVia:     d.values("import-via"),
+		Import:        d.values("import"),
+		MPImport:      d.values("mp-import"),
+		ExportVia:     d.values("export-via"),
+		Export:        d.values("export"),
+		MPExport:      d.values("mp-export"),
+		Default:       d.values("default"),
+		MPDefault:     d.values("mp-default"),
+		Remarks:       d.values("remarks"),
+		Org:           d.value("org"),
+		SponsoringOrg: d.value("sponsoring-org"),
+		AdminC:        d.values("admin-c"),
+		TechC:         d.values("tech-c"),
+		AbuseC:        d.value("abuse-c"),
+		Status:        d.value("status"),
+		Notify:        d.values("notify"),
+		MntBy:         d.list("mnt-by"),
+		Created:       d.timestamp("created"),
+		LastModified:  d.timestamp("last-modified"),
+		Source:        d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Object converts the AutNum to an Object.
+func (a *AutNum) Object() *Object {
+	var e encoder
+	e.addASN("aut-num", a.ASN)
+	e.add("as-name", a.ASName)
+	e.addAll("descr", a.Descr)
+	e.addList("member-of", a.MemberOf)
+	e.addAll("import-via", a.ImportVia)
+	e.addAll("import", a.Import)
+	e.addAll("mp-import", a.MPImport)
+	e.addAll("export-via", a.ExportVia)
+	e.addAll("export", a.Export)
+	e.addAll("mp-export", a.MPExport)
+	e.addAll("default", a.Default)
+	e.addAll("mp-default", a.MPDefault)
+	e.addAll("remarks", a.Remarks)
+	e.add("org", a.Org)
+	e.add("sponsoring-org", a.SponsoringOrg)
+	e.addAll("admin-c", a.AdminC)
+	e.addAll("tech-c", a.TechC)
+	e.add("abuse-c", a.AbuseC)
+	e.add("status", a.Status)
+	e.addAll("notify", a.Notify)
+	e.addAll("mnt-by", a.MntBy)
+	e.addTime("created", a.Created)
+	e.addTime("last-modified", a.LastModified)
+	e.add("source", a.Source)
+	return &e.o
+}
+
+// Route is a route object, describing an IPv4 prefix originated by an autonomous system.
+type Route struct {
+	Prefix       netip.Prefix
+	Descr        []string
+	Origin       uint32
+	Pingable     []netip.Addr
+	PingHdl      []string
+	Holes        []netip.Prefix
+	Org          []string
+	MemberOf     []string
+	Inject       []string
+	AggrMtd      string
+	AggrBndry    string
+	ExportComps  string
+	Components   string
+	Remarks      []string
+	Notify       []string
+	MntLower     []string
+	MntRoutes    []string
+	MntBy        []string
+	Created      time.Time
+	LastModified time.Time
+	Source       string
+}
+
+// Route6 is a route6 object, describing an IPv6 prefix originated by an autonomous system.
+type Route6 Route
+
+// NewRoute converts a route Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed, such as a prefix of the wrong address family.
+func NewRoute(o *Object) (*Route, error) {
+	return newRoute(o, "route")
+}
+
+// NewRoute6 converts a route6 Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed, such as a prefix of the wrong address family.
+func NewRoute6(o *Object) (*Route6, error) {
+	r, err := newRoute(o, "route6")
+	return (*Route6)(r), err
+}
+
+// newRoute converts a route or route6 Object.
+func newRoute(o *Object, class string) (*Route, error) {
+	d := newDecoder(o, class)
+	d.require("origin")
+	r := &Route{
+		Prefix:       d.prefix(class, class == "route6"),
+		Descr:        d.values("descr"),
+		Origin:       d.asn("origin"),
+		Pingable:     d.addrs("pingable"),
+		PingHdl:      d.values("ping-hdl"),
+		Holes:        d.prefixes("holes"),
+		Org:          d.values("org"),
+		MemberOf:     d.list("member-of"),
+		Inject:       d.values("inject"),
+		AggrMtd:      d.value("aggr-mtd"),
+		AggrBndry:    d.value("aggr-bndry"),
+		ExportComps:  d.value("export-comps"),
+		Components:   d.value("components"),
+		Remarks:      d.values("remarks"),
+		Notify:       d.values("notify"),
+		MntLower:     d.list("mnt-lower"),
+		MntRoutes:    d.values("mnt-routes"),
+		MntBy:        d.list("mnt-by"),
+		Created:      d.timestamp("created"),
+		LastModified: d.timestamp("last-modified"),
+		Source:       d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Object converts the Route to an Object.
+func (r *Route) Object() *Object {
+	return r.object("route")
+}
+
+// Object converts the Route6 to an Object.
+func (r *Route6) Object() *Object {
+	return (*Route)(r).object("route6")
+}
+
+// object converts the Route to an Object of the given class.
+func (r *Route) object(class string) *Object {
+	var e encoder
+	e.addPrefix(class, r.Prefix)
+	e.addAll("descr", r.Descr)
+	e.addASN("origin", r.Origin)
+	for _, addr := range r.Pingable {
+		e.add("pingable", addr.String())
+	}
+
+	e.addAll("ping-hdl", r.PingHdl)
+	holes := make([]string, len(r.Holes))
+	for i, hole := range r.Holes {
+		holes[i] = hole.String()
+	}
+
+	e.addList("holes", holes)
+
+	e.addAll("org", r.Org)
+	e.addList("member-of", r.MemberOf)
+	e.addAll("inject", r.Inject)
+	e.add("aggr-mtd", r.AggrMtd)
+	e.add("aggr-bndry", r.AggrBndry)
+	e.add("export-comps", r.ExportComps)
+	e.add("components", r.Components)
+	e.addAll("remarks", r.Remarks)
+	e.addAll("notify", r.Notify)
+	e.addAll("mnt-lower", r.MntLower)
+	e.addAll("mnt-routes", r.MntRoutes)
+	e.addAll("mnt-by", r.MntBy)
+	e.addTime("created", r.Created)
+	e.addTime("last-modified", r.LastModified)
+	e.add("source", r.Source)
+	return &e.o
+}
+
+// Inetnum is an inetnum object, describing a range of IPv4 addresses.
+type Inetnum struct {
+	// Start and End are the first and last addresses of the range.
+	Start, End    netip.Addr
+	Netname       string
+	Descr         []string
+	Country       []string
+	Geoloc        string
+	Language      []string
+	Org           string
+	SponsoringOrg string
+	AdminC        []string
+	TechC         []string
+	AbuseC        string
+	Status        string
+	Remarks       []string
+	Notify        []string
+	MntBy         []string
+	MntLower      []string
+	MntDomains    []string
+	MntRoutes     []string
+	MntIrt        []string
+	Created       time.Time
+	LastModified  time.Time
+	Source        string
+}
+
+// NewInetnum converts an inetnum Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed, such as an IPv6 range.
+func NewInetnum(o *Object) (*Inetnum, error) {
+	d := newDecoder(o, "inetnum")
+	i := &Inetnum{
+		Netname:       d.value("netname"),
+		Descr:         d.values("descr"),
+		Country:       d.values("country"),
+		Geoloc:        d.value("geoloc"),
+		Language:      d.values("language"),
+		Org:           d.value("org"),
+		SponsoringOrg: d.value("sponsoring-org"),
+		AdminC:        d.values("admin-c"),
+		TechC:         d.values("tech-c"),
+		AbuseC:        d.value("abuse-c"),
+		Status:        d.value("status"),
+		Remarks:       d.values("remarks"),
+		Notify:        d.values("notify"),
+		MntBy:         d.list("mnt-by"),
+		MntLower:      d.list("mnt-lower"),
+		MntDomains:    d.list("mnt-domains"),
+		MntRoutes:     d.values("mnt-routes"),
+		MntIrt:        d.list("mnt-irt"),
+		Created:       d.timestamp("created"),
+		LastModified:  d.timestamp("last-modified"),
+		Source:        d.value("source"),
+	}
+
+	d.first("inetnum", func(value string) (err error) {
+		if i.Start, i.End, err = ParseRange(value); err != nil {
+			return err
+		}
+
+		return checkFamily(value, i.Start, false)
+	})
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Object converts the Inetnum to an Object.
+func (i *Inetnum) Object() *Object {
+	var e encoder
+	if i.Start.IsValid() && i.End.IsValid() {
+		e.add("inetnum", i.Start.String()+" - "+i.End.String())
+	}
+
+	e.add("netname", i.Netname)
+	e.addAll("descr", i.Descr)
+	e.addAll("country", i.Country)
+	e.add("geoloc", i.Geoloc)
+	e.addAll("language", i.Language)
+	e.add("org", i.Org)
+	e.add("sponsoring-org", i.SponsoringOrg)
+	e.addAll("admin-c", i.AdminC)
+	e.addAll("tech-c", i.TechC)
+	e.add("abuse-c", i.AbuseC)
+	e.add("status", i.Status)
+	e.addAll("remarks", i.Remarks)
+	e.addAll("notify", i.Notify)
+	e.addAll("mnt-by", i.MntBy)
+	e.addAll("mnt-lower", i.MntLower)
+	e.addAll("mnt-domains", i.MntDomains)
+	e.addAll("mnt-routes", i.MntRoutes)
+	e.addAll("mnt-irt", i.MntIrt)
+	e.addTime("created", i.Created)
+	e.addTime("last-modified", i.LastModified)
+	e.add("source", i.Source)
+	return &e.o
+}
+
+// Inet6num is an inet6num object, describing an IPv6 prefix.
+type Inet6num struct {
+	Prefix         netip.Prefix
+	Netname        string
+	Descr          []string
+	Country        []string
+	Geoloc         string
+	Language       []string
+	Org            string
+	SponsoringOrg  string
+	AdminC         []string
+	TechC          []string
+	AbuseC         string
+	Status         string
+	AssignmentSize int
+	Remarks        []string
+	Notify         []string
+	MntBy          []string
+	MntLower       []string
+	MntRoutes      []string
+	MntDomains     []string
+	MntIrt         []string
+	Created        time.Time
+	LastModified   time.Time
+	Source         string
+}
+
+// NewInet6num converts an inet6num Object. It returns ValidationErrors if the Object is of another class, or if a
+// value cannot be parsed.
+func NewInet6num(o *Object) (*Inet6num, error) {
+	d := newDecoder(o, "inet6num")
+	i := &Inet6num{
+		Prefix:         d.prefix("inet6num", true),
+		Netname:        d.value("netname"),
+		Descr:          d.values("descr"),
+		Country:        d.values("country"),
+		Geoloc:         d.value("geoloc"),
+		Language:       d.values("language"),
+		Org:            d.value("org"),
+		SponsoringOrg:  d.value("sponsoring-org"),
+		AdminC:         d.values("admin-c"),
+		TechC:          d.values("tech-c"),
+		AbuseC:         d.value("abuse-c"),
+		Status:         d.value("status"),
+		AssignmentSize: d.number("assignment-size"),
+		Remarks:        d.values("remarks"),
+		Notify:         d.values("notify"),
+		MntBy:          d.list("mnt-by"),
+		MntLower:       d.list("mnt-lower"),
+		MntRoutes:      d.values("mnt-routes"),
+		MntDomains:     d.list("mnt-domains"),
+		MntIrt:         d.list("mnt-irt"),
+		Created:        d.timestamp("created"),
+		LastModified:   d.timestamp("last-modified"),
+		Source:         d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Object converts the Inet6num to an Object.
+func (i *Inet6num) Object() *Object {
+	var e encoder
+	e.addPrefix("inet6num", i.Prefix)
+	e.add("netname", i.Netname)
+	e.addAll("descr", i.Descr)
+	e.addAll("country", i.Country)
+	e.add("geoloc", i.Geoloc)
+	e.addAll("language", i.Language)
+	e.add("org", i.Org)
+	e.add("sponsoring-org", i.SponsoringOrg)
+	e.addAll("admin-c", i.AdminC)
+	e.addAll("tech-c", i.TechC)
+	e.add("abuse-c", i.AbuseC)
+	e.add("status", i.Status)
+	e.addInt("assignment-size", i.AssignmentSize)
+	e.addAll("remarks", i.Remarks)
+	e.addAll("notify", i.Notify)
+	e.addAll("mnt-by", i.MntBy)
+	e.addAll("mnt-lower", i.MntLower)
+	e.addAll("mnt-routes", i.MntRoutes)
+	e.addAll("mnt-domains", i.MntDomains)
+	e.addAll("mnt-irt", i.MntIrt)
+	e.addTime("created", i.Created)
+	e.addTime("last-modified", i.LastModified)
+	e.add("source", i.Source)
+	return &e.o
+}
+
+// Mntner is a mntner object, describing a maintainer authorized to update objects.
+type Mntner struct {
+	Name         string
+	Descr        []string
+	Org          []string
+	AdminC       []string
+	TechC        []string
+	UpdTo        []string
+	MntNfy       []string
+	Auth         []string
+	Remarks      []string
+	Notify       []string
+	MntBy        []string
+	Created      time.Time
+	LastModified time.Time
+	Source       string
+}
+
+// NewMntner converts a mntner Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed.
+func NewMntner(o *Object) (*Mntner, error) {
+	d := newDecoder(o, "mntner")
+	m := &Mntner{
+		Name:         d.primary(),
+		Descr:        d.values("descr"),
+		Org:          d.values("org"),
+		AdminC:       d.values("admin-c"),
+		TechC:        d.values("tech-c"),
+		UpdTo:        d.values("upd-to"),
+		MntNfy:       d.values("mnt-nfy"),
+		Auth:         d.values("auth"),
+		Remarks:      d.values("remarks"),
+		Notify:       d.values("notify"),
+		MntBy:        d.list("mnt-by"),
+		Created:      d.timestamp("created"),
+		LastModified: d.timestamp("last-modified"),
+		Source:       d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Object converts the Mntner to an Object.
+func (m *Mntner) Object() *Object {
+	var e encoder
+	e.add("mntner", m.Name)
+	e.addAll("descr", m.Descr)
+	e.addAll("org", m.Org)
+	e.addAll("admin-c", m.AdminC)
+	e.addAll("tech-c", m.TechC)
+	e.addAll("upd-to", m.UpdTo)
+	e.addAll("mnt-nfy", m.MntNfy)
+	e.addAll("auth", m.Auth)
+	e.addAll("remarks", m.Remarks)
+	e.addAll("notify", m.Notify)
+	e.addAll("mnt-by", m.MntBy)
+	e.addTime("created", m.Created)
+	e.addTime("last-modified", m.LastModified)
+	e.add("source", m.Source)
+	return &e.o
+}
+
+// Person is a person object, describing a contact.
+type Person struct {
+	Name         string
+	Address      []string
+	Phone        []string
+	FaxNo        []string
+	Email        []string
+	Org          []string
+	NicHdl       string
+	Remarks      []string
+	Notify       []string
+	MntBy        []string
+	Created      time.Time
+	LastModified time.Time
+	Source       string
+}
+
+// NewPerson converts a person Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed.
+func NewPerson(o *Object) (*Person, error) {
+	d := newDecoder(o, "person")
+	d.require("nic-hdl")
+	p := &Person{
+		Name:         d.primary(),
+		Address:      d.values("address"),
+		Phone:        d.values("phone"),
+		FaxNo:        d.values("fax-no"),
+		Email:        d.values("e-mail"),
+		Org:          d.values("org"),
+		NicHdl:       d.value("nic-hdl"),
+		Remarks:      d.values("remarks"),
+		Notify:       d.values("notify"),
+		MntBy:        d.list("mnt-by"),
+		Created:      d.timestamp("created"),
+		LastModified: d.timestamp("last-modified"),
+		Source:       d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Object converts the Person to an Object.
+func (p *Person) Object() *Object {
+	var e encoder
+	e.add("person", p.Name)
+	e.addAll("address", p.Address)
+	e.addAll("phone", p.Phone)
+	e.addAll("fax-no", p.FaxNo)
+	e.addAll("e-mail", p.Email)
+	e.addAll("org", p.Org)
+	e.add("nic-hdl", p.NicHdl)
+	e.addAll("remarks", p.Remarks)
+	e.addAll("notify", p.Notify)
+	e.addAll("mnt-by", p.MntBy)
+	e.addTime("created", p.Created)
+	e.addTime("last-modified", p.LastModified)
+	e.add("source", p.Source)
+	return &e.o
+}
+
+// Role is a role object, describing a contact shared by several people.
+type Role struct {
+	Name         string
+	Address      []string
+	Phone        []string
+	FaxNo        []string
+	Email        []string
+	Org          []string
+	AdminC       []string
+	TechC        []string
+	NicHdl       string
+	Remarks      []string
+	Notify       []string
+	AbuseMailbox string
+	MntBy        []string
+	Created      time.Time
+	LastModified time.Time
+	Source       string
+}
+
+// NewRole converts a role Object. It returns ValidationErrors if the Object is of another class, or if a value cannot
+// be parsed.
+func NewRole(o *Object) (*Role, error) {
+	d := newDecoder(o, "role")
+	d.require("nic-hdl")
+	r := &Role{
+		Name:         d.primary(),
+		Address:      d.values("address"),
+		Phone:        d.values("phone"),
+		FaxNo:        d.values("fax-no"),
+		Email:        d.values("e-mail"),
+		Org:          d.values("org"),
+		AdminC:       d.values("admin-c"),
+		TechC:        d.values("tech-c"),
+		NicHdl:       d.value("nic-hdl"),
+		Remarks:      d.values("remarks"),
+		Notify:       d.values("notify"),
+		AbuseMailbox: d.value("abuse-mailbox"),
+		MntBy:        d.list("mnt-by"),
+		Created:      d.timestamp("created"),
+		LastModified: d.timestamp("last-modified"),
+		Source:       d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Object converts the Role to an Object.
+func (r *Role) Object() *Object {
+	var e encoder
+	e.add("role", r.Name)
+	e.addAll("address", r.Address)
+	e.addAll("phone", r.Phone)
+	e.addAll("fax-no", r.FaxNo)
+	e.addAll("e-mail", r.Email)
+	e.addAll("org", r.Org)
+	e.addAll("admin-c", r.AdminC)
+	e.addAll("tech-c", r.TechC)
+	e.add("nic-hdl", r.NicHdl)
+	e.addAll("remarks", r.Remarks)
+	e.addAll("notify", r.Notify)
+	e.add("abuse-mailbox", r.AbuseMailbox)
+	e.addAll("mnt-by", r.MntBy)
+	e.addTime("created", r.Created)
+	e.addTime("last-modified", r.LastModified)
+	e.add("source", r.Source)
+	return &e.o
+}
+
+// AsSet is an as-set object, describing a set of autonomous systems.
+type AsSet struct {
+	Name  string
+	Descr []string
+	// Members are the AS numbers and as-set names of the set.
+	Members      []string
+	MbrsByRef    []string
+	Remarks      []string
+	Org          []string
+	TechC        []string
+	AdminC       []string
+	Notify       []string
+	MntBy        []string
+	MntLower     []string
+	Created      time.Time
+	LastModified time.Time
+	Source       string
+}
+
+// NewAsSet converts an as-set Object. It returns ValidationErrors if the Object is of another class, or if a value
+// cannot be parsed.
+func NewAsSet(o *Object) (*AsSet, error) {
+	d := newDecoder(o, "as-set")
+	s := &AsSet{
+		Name:         d.primary(),
+		Descr:        d.values("descr"),
+		Members:      d.list("members"),
+		MbrsByRef:    d.list("mbrs-by-ref"),
+		Remarks:      d.values("remarks"),
+		Org:          d.values("org"),
+		TechC:        d.values("tech-c"),
+		AdminC:       d.values("admin-c"),
+		Notify:       d.values("notify"),
+		MntBy:        d.list("mnt-by"),
+		MntLower:     d.list("mnt-lower"),
+		Created:      d.timestamp("created"),
+		LastModified: d.timestamp("last-modified"),
+		Source:       d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Object converts the AsSet to an Object.
+func (s *AsSet) Object() *Object {
+	var e encoder
+	e.add("as-set", s.Name)
+	e.addAll("descr", s.Descr)
+	e.addList("members", s.Members)
+	e.addList("mbrs-by-ref", s.MbrsByRef)
+	e.addAll("remarks", s.Remarks)
+	e.addAll("org", s.Org)
+	e.addAll("tech-c", s.TechC)
+	e.addAll("admin-c", s.AdminC)
+	e.addAll("notify", s.Notify)
+	e.addAll("mnt-by", s.MntBy)
+	e.addAll("mnt-lower", s.MntLower)
+	e.addTime("created", s.Created)
+	e.addTime("last-modified", s.LastModified)
+	e.add("source", s.Source)
+	return &e.o
+}
+
+// RouteSet is a route-set object, describing a set of prefixes.
+type RouteSet struct {
+	Name  string
+	Descr []string
+	// Members are the IPv4 prefixes, route-set names, as-set names and AS numbers of the set, optionally followed by
+	// a range operator.
+	Members []string
+	// MPMembers are the members of the set that can also be IPv6 prefixes.
+	MPMembers    []string
+	MbrsByRef    []string
+	Remarks      []string
+	Org          []string
+	TechC        []string
+	AdminC       []string
+	Notify       []string
+	MntBy        []string
+	MntLower     []string
+	Created      time.Time
+	LastModified time.Time
+	Source       string
+}
+
+// NewRouteSet converts a route-set Object. It returns ValidationErrors if the Object is of another class, or if a
+// value cannot be parsed.
+func NewRouteSet(o *Object) (*RouteSet, error) {
+	d := newDecoder(o, "route-set")
+	s := &RouteSet{
+		Name:         d.primary(),
+		Descr:        d.values("descr"),
+		Members:      d.list("members"),
+		MPMembers:    d.list("mp-members"),
+		MbrsByRef:    d.list("mbrs-by-ref"),
+		Remarks:      d.values("remarks"),
+		Org:          d.values("org"),
+		TechC:        d.values("tech-c"),
+		AdminC:       d.values("admin-c"),
+		Notify:       d.values("notify"),
+		MntBy:        d.list("mnt-by"),
+		MntLower:     d.list("mnt-lower"),
+		Created:      d.timestamp("created"),
+		LastModified: d.timestamp("last-modified"),
+		Source:       d.value("source"),
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Object converts the RouteSet to an Object.
+func (s *RouteSet) Object() *Object {
+	var e encoder
+	e.add("route-set", s.Name)
+	e.addAll("descr", s.Descr)
+	e.addList("members", s.Members)
+	e.addList("mp-members", s.MPMembers)
+	e.addList("mbrs-by-ref", s.MbrsByRef)
+	e.addAll("remarks", s.Remarks)
+	e.addAll("org", s.Org)
+	e.addAll("tech-c", s.TechC)
+	e.addAll("admin-c", s.AdminC)
+	e.addAll("notify", s.Notify)
+	e.addAll("mnt-by", s.MntBy)
+	e.addAll("mnt-lower", s.MntLower)
+	e.addTime("created", s.Created)
+	e.addTime("last-modified", s.LastModified)
+	e.add("source", s.Source)
+	return &e.o
+}
@@ -0,0 +1,154 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewRoute(t *testing.T) {
+	obj, err := Parse("route:         192.0.2.0/24\n" +
+		"descr:         Example\n" +
+		"origin:        AS64496\n" +
+		"holes:         192.0.2.128/25, 192.0.2.64/26\n" +
+		"member-of:     RS-FOO, AS64496:RS-BAR\n" +
+		"mnt-by:        FOO-MNT\n" +
+		"created:       2024-01-02T03:04:05Z\n" +
+		"source:        TEST")
+	if err != nil {
+		t.Fatalf(`Parse => %v`, err)
+	}
+
+	route, err := NewRoute(obj)
+	if err != nil {
+		t.Fatalf(`NewRoute => %v`, err)
+	}
+
+	want := &Route{
+		Prefix:   netip.MustParsePrefix("192.0.2.0/24"),
+		Descr:    []string{"Example"},
+		Origin:   64496,
+		Holes:    []netip.Prefix{netip.MustParsePrefix("192.0.2.128/25"), netip.MustParsePrefix("192.0.2.64/26")},
+		MemberOf: []string{"RS-FOO", "AS64496:RS-BAR"},
+		MntBy:    []string{"FOO-MNT"},
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Source:   "TEST",
+	}
+
+	if !reflect.DeepEqual(route, want) {
+		t.Errorf(`NewRoute => %+v, want %+v`, route, want)
+	}
+
+	if got := route.Object().String(); got != obj.String() {
+		t.Errorf(`Route.Object => %q, want %q`, got, obj.String())
+	}
+}
+
+func TestNewInetnum(t *testing.T) {
+	tests := []struct {
+		value      string
+		start, end string
+	}{
+		{"192.0.2.0 - 192.0.2.255", "192.0.2.0", "192.0.2.255"},
+		{"192.0.2.0-192.0.2.127", "192.0.2.0", "192.0.2.127"},
+		{"198.51.100.0/23", "198.51.100.0", "198.51.101.255"},
+	}
+
+	for _, tt := range tests {
+		obj := &Object{Attributes: []Attribute{{Name: "inetnum", Value: tt.value}, {Name: "netname", Value: "EXAMPLE"}}}
+		inetnum, err := NewInetnum(obj)
+		if err != nil {
+			t.Errorf(`NewInetnum(%q) => %v`, tt.value, err)
+			continue
+		}
+
+		if inetnum.Start.String() != tt.start || inetnum.End.String() != tt.end {
+			t.Errorf(`NewInetnum(%q) => %v - %v, want %v - %v`, tt.value, inetnum.Start, inetnum.End, tt.start, tt.end)
+		}
+	}
+}
+
+func TestNewAutNumRoundTrip(t *testing.T) {
+	obj, err := Parse("aut-num:       AS64496\n" +
+		"as-name:       EXAMPLE\n" +
+		"member-of:     AS-FOO, AS-BAR\n" +
+		"import:        from AS64497 accept ANY\n" +
+		"export:        to AS64497 announce AS64496\n" +
+		"admin-c:       FOO1-TEST\n" +
+		"tech-c:        FOO1-TEST\n" +
+		"mnt-by:        FOO-MNT\n" +
+		"source:        TEST")
+	if err != nil {
+		t.Fatalf(`Parse => %v`, err)
+	}
+
+	autnum, err := NewAutNum(obj)
+	if err != nil {
+		t.Fatalf(`NewAutNum => %v`, err)
+	}
+
+	if autnum.ASN != 64496 || len(autnum.MemberOf) != 2 || len(autnum.Import) != 1 {
+		t.Errorf(`NewAutNum => %+v`, autnum)
+	}
+
+	if got := autnum.Object().String(); got != obj.String() {
+		t.Errorf(`AutNum.Object => %q, want %q`, got, obj.String())
+	}
+}
+
+func TestLastAddr(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"192.0.2.0/24", "192.0.2.255"},
+		{"192.0.2.1/32", "192.0.2.1"},
+		{"0.0.0.0/0", "255.255.255.255"},
+		{"2001:db8::/33", "2001:db8:7fff:ffff:ffff:ffff:ffff:ffff"},
+		{"::/0", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+	}
+
+	for _, tt := range tests {
+		if got := LastAddr(netip.MustParsePrefix(tt.prefix)); got.String() != tt.want {
+			t.Errorf(`LastAddr(%s) => %v, want %s`, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		object string
+		conv   func(*Object) error
+		errs   int
+	}{
+		{"wrong class", "route: 192.0.2.0/24\norigin: AS64496", func(o *Object) error { _, err := NewAutNum(o); return err }, 1},
+		{"bad prefix", "route: 192.0.2.1/24\norigin: AS64496", func(o *Object) error { _, err := NewRoute(o); return err }, 1},
+		{"missing origin", "route6: 2001:db8::/32", func(o *Object) error { _, err := NewRoute6(o); return err }, 1},
+		{"bad values", "route: 192.0.2.0/24\norigin: ASX\ncreated: yesterday", func(o *Object) error { _, err := NewRoute(o); return err }, 2},
+		{"route family", "route: 2001:db8::/32\norigin: AS64496", func(o *Object) error { _, err := NewRoute(o); return err }, 1},
+		{"route6 family", "route6: 192.0.2.0/24\norigin: AS64496", func(o *Object) error { _, err := NewRoute6(o); return err }, 1},
+		{"inetnum family", "inetnum: 2001:db8::/32", func(o *Object) error { _, err := NewInetnum(o); return err }, 1},
+		{"inetnum range family", "inetnum: 2001:db8:: - 2001:db8::ff", func(o *Object) error { _, err := NewInetnum(o); return err }, 1},
+		{"inet6num family", "inet6num: 192.0.2.0/24", func(o *Object) error { _, err := NewInet6num(o); return err }, 1},
+		{"bad range", "inetnum: 192.0.2.255 - 192.0.2.0", func(o *Object) error { _, err := NewInetnum(o); return err }, 1},
+		{"bad size", "inet6num: 2001:db8::/32\nassignment-size: -1", func(o *Object) error { _, err := NewInet6num(o); return err }, 1},
+	}
+
+	for _, tt := range tests {
+		obj, err := Parse(tt.object)
+		if err != nil {
+			t.Fatalf(`%s: Parse => %v`, tt.name, err)
+		}
+
+		var errs ValidationErrors
+		if err := tt.conv(obj); !errors.As(err, &errs) || len(errs) != tt.errs {
+			t.Errorf(`%s: => %v, want %d errors`, tt.name, err, tt.errs)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decoder reads the typed values of the attributes of an Object, collecting the values that cannot be parsed as
+// ValidationErrors.
+type decoder struct {
+	o     *Object
+	class string
+	errs  ValidationErrors
+}
+
+// newDecoder returns a decoder for an Object of the given class, reporting an error if the Object is of another class.
+func newDecoder(o *Object, class string) *decoder {
+	d := &decoder{o: o, class: class}
+	if err := o.EnsureClass(class); err != nil {
+		d.errs = append(d.errs, &ValidationError{Class: class, Err: err})
+	}
+
+	return d
+}
+
+// err returns the errors found while decoding, or nil if every value could be parsed.
+func (d *decoder) err() error {
+	if len(d.errs) == 0 {
+		return nil
+	}
+
+	return d.errs
+}
+
+// fail records an error for an attribute.
+func (d *decoder) fail(attr *Attribute, err error) {
+	d.errs = append(d.errs, &ValidationError{
+		Class:     d.class,
+		Attribute: attr.Name,
+		Line:      attr.Line,
+		Err:       fmt.Errorf("attribute '%s' has an invalid value: %w", attr.Name, err),
+	})
+}
+
+// each calls fn with every attribute with the given name, recording the errors it returns.
+func (d *decoder) each(name string, fn func(value string) error) {
+	for i := range d.o.Attributes {
+		if attr := &d.o.Attributes[i]; attr.Name == name {
+			if err := fn(attr.Value); err != nil {
+				d.fail(attr, err)
+			}
+		}
+	}
+}
+
+// value returns the first value of an attribute, or an empty string if it is missing.
+func (d *decoder) value(name string) string {
+	if value := d.o.GetFirst(name); value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+// values returns every value of an attribute.
+func (d *decoder) values(name string) []string {
+	values := d.o.GetAll(name)
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values
+}
+
+// list returns the items of the comma-separated lists of every value of an attribute.
+func (d *decoder) list(name string) []string {
+	var items []string
+	d.each(name, func(value string) error {
+		items = append(items, splitList(value)...)
+		return nil
+	})
+
+	return items
+}
+
+// splitList returns the non-empty items of a comma-separated list.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// primary returns the value of the first attribute, whose name is the class.
+func (d *decoder) primary() string {
+	if len(d.o.Attributes) == 0 || d.o.Attributes[0].Name != d.class {
+		return ""
+	}
+
+	return d.o.Attributes[0].Value
+}
+
+// asn returns the first value of an attribute parsed as an AS number.
+func (d *decoder) asn(name string) uint32 {
+	var asn uint32
+	d.first(name, func(value string) (err error) {
+		asn, err = ParseASN(value)
+		return err
+	})
+
+	return asn
+}
+
+// prefix returns the first value of an attribute parsed as a prefix without host bits set, of the IPv6 family if ipv6
+// is set and of the IPv4 family otherwise.
+func (d *decoder) prefix(name string, ipv6 bool) netip.Prefix {
+	var prefix netip.Prefix
+	d.first(name, func(value string) (err error) {
+		if prefix, err = parsePrefix(value); err != nil {
+			return err
+		}
+
+		return checkFamily(value, prefix.Addr(), ipv6)
+	})
+
+	return prefix
+}
+
+// prefixes returns the items of the comma-separated lists of every value of an attribute parsed as prefixes.
+func (d *decoder) prefixes(name string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	d.each(name, func(value string) error {
+		for _, item := range strings.Split(value, ",") {
+			prefix, err := parsePrefix(strings.TrimSpace(item))
+			if err != nil {
+				return err
+			}
+
+			prefixes = append(prefixes, prefix)
+		}
+
+		return nil
+	})
+
+	return prefixes
+}
+
+// addrs returns every value of an attribute parsed as an IP address.
+func (d *decoder) addrs(name string) []netip.Addr {
+	var addrs []netip.Addr
+	d.each(name, func(value string) error {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return fmt.Errorf("invalid address '%s'", value)
+		}
+
+		addrs = append(addrs, addr)
+		return nil
+	})
+
+	return addrs
+}
+
+// number returns the first value of an attribute parsed as a non-negative integer, or zero if it is missing.
+func (d *decoder) number(name string) int {
+	var n int
+	d.first(name, func(value string) error {
+		i, err := strconv.ParseUint(value, 10, 31)
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+
+		n = int(i)
+		return nil
+	})
+
+	return n
+}
+
+// timestamp returns the first value of an attribute parsed as an RFC 3339 timestamp, or the zero time if it is
+// missing.
+func (d *decoder) timestamp(name string) time.Time {
+	var t time.Time
+	d.first(name, func(value string) (err error) {
+		if t, err = time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("invalid timestamp '%s'", value)
+		}
+
+		return nil
+	})
+
+	return t
+}
+
+// first calls fn with the first value of an attribute, if any, recording the error it returns.
+func (d *decoder) first(name string, fn func(value string) error) {
+	for i := range d.o.Attributes {
+		if attr := &d.o.Attributes[i]; attr.Name == name {
+			if err := fn(attr.Value); err != nil {
+				d.fail(attr, err)
+			}
+
+			return
+		}
+	}
+}
+
+// require records an error if the attribute is missing.
+func (d *decoder) require(name string) {
+	if !d.o.Exists(name) {
+		d.errs = append(d.errs, &ValidationError{
+			Class:     d.class,
+			Attribute: name,
+			Err:       fmt.Errorf("attribute '%s' is mandatory but found none", name),
+		})
+	}
+}
+
+// parsePrefix parses a prefix without host bits set.
+func parsePrefix(value string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid prefix '%s'", value)
+	}
+
+	if prefix.Masked() != prefix {
+		return netip.Prefix{}, fmt.Errorf("invalid prefix '%s': host bits set, expected %s", value, prefix.Masked())
+	}
+
+	return prefix, nil
+}
+
+// checkFamily returns an error if the address of value is not of the IPv6 family while ipv6 is set, or of the IPv4
+// family while it is not.
+func checkFamily(value string, addr netip.Addr, ipv6 bool) error {
+	switch {
+	case ipv6 && !addr.Is6():
+		return fmt.Errorf("'%s' is not an IPv6 address", value)
+	case !ipv6 && !addr.Is4():
+		return fmt.Errorf("'%s' is not an IPv4 address", value)
+	}
+
+	return nil
+}
+
+// LastAddr returns the last address of a prefix, such as 192.0.2.255 for "192.0.2.0/24".
+func LastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(addr)*8; i++ {
+		addr[i/8] |= 1 << (7 - i%8)
+	}
+
+	last, _ := netip.AddrFromSlice(addr)
+	return last
+}
+
+// encoder builds an Object from typed values. Empty values are omitted.
+type encoder struct {
+	o Object
+}
+
+// add appends an attribute, unless its value is empty.
+func (e *encoder) add(name string, value string) {
+	if value != "" {
+		e.o.Attributes = append(e.o.Attributes, Attribute{Name: name, Value: value})
+	}
+}
+
+// addAll appends an attribute for every value.
+func (e *encoder) addAll(name string, values []string) {
+	for _, value := range values {
+		e.add(name, value)
+	}
+}
+
+// addList appends a single attribute holding the comma-separated list of values.
+func (e *encoder) addList(name string, values []string) {
+	e.add(name, strings.Join(values, ", "))
+}
+
+// addASN appends an attribute holding an AS number.
+func (e *encoder) addASN(name string, asn uint32) {
+	e.add(name, "AS"+strconv.FormatUint(uint64(asn), 10))
+}
+
+// addPrefix appends an attribute holding a prefix, unless it is invalid.
+func (e *encoder) addPrefix(name string, prefix netip.Prefix) {
+	if prefix.IsValid() {
+		e.add(name, prefix.String())
+	}
+}
+
+// addInt appends an attribute holding a number, unless it is zero.
+func (e *encoder) addInt(name string, n int) {
+	if n != 0 {
+		e.add(name, strconv.Itoa(n))
+	}
+}
+
+// addTime appends an attribute holding an RFC 3339 timestamp, unless it is the zero time.
+func (e *encoder) addTime(name string, t time.Time) {
+	if !t.IsZero() {
+		e.add(name, t.UTC().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind uint8
+
+const (
+	// Added attributes are only in the new object.
+	Added ChangeKind = iota + 1
+	// Removed attributes are only in the old object.
+	Removed
+	// Modified attributes have a new value at the same place.
+	Modified
+	// Moved attributes have the same value at another place, such as a reordered mnt-by.
+	Moved
+)
+
+// String returns a string representation of the ChangeKind.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	case Moved:
+		return "moved"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", uint8(k))
+	}
+}
+
+// Change is a difference between two objects.
+type Change struct {
+	Kind ChangeKind
+	Name string
+	// OldValue and NewValue are the values in the old and new objects, empty when the attribute is absent.
+	OldValue, NewValue string
+	// OldIndex and NewIndex are the positions of the attribute in the old and new objects, -1 when it is absent.
+	OldIndex, NewIndex int
+}
+
+// Conflict is a part of an object changed differently by the two sides of a three-way merge.
+type Conflict struct {
+	// Base, Local and Upstream are the attributes of the part in the three objects.
+	Base, Local, Upstream []Attribute
+}
+
+// edit is a step of the edit script turning the attributes of an object into those of another one.
+type edit struct {
+	op       byte // ' ' to keep, '-' to remove and '+' to add an attribute.
+	old, new int  // Positions of the attribute in the old and new objects, -1 when absent.
+}
+
+// Diff returns the changes turning the object a into the object b, in the order of the attributes. Attributes are
+// compared by name and value, ignoring differences in whitespace, and the order of the attributes matters: a
+// reordered mnt-by or members attribute is reported as Moved.
+func Diff(a *Object, b *Object) []Change {
+	script := editScript(a.Attributes, b.Attributes)
+
+	// partners pairs the removals and additions of the script, in both directions: first those of the same attribute,
+	// which is moved, then those of the same name in a block of edits, whose value is modified.
+	partners := make(map[int]int)
+	pair := func(from int, to int, same func(x *Attribute, y *Attribute) bool) {
+		for i := from; i < to; i++ {
+			if _, ok := partners[i]; ok || script[i].op != '-' {
+				continue
+			}
+
+			for j := from; j < to; j++ {
+				if _, ok := partners[j]; !ok && script[j].op == '+' && same(&a.Attributes[script[i].old], &b.Attributes[script[j].new]) {
+					partners[i], partners[j] = j, i
+					break
+				}
+			}
+		}
+	}
+
+	pair(0, len(script), sameAttribute)
+	for i := 0; i < len(script); i++ {
+		j := i
+		for j < len(script) && script[j].op != ' ' {
+			j++
+		}
+
+		pair(i, j, func(x *Attribute, y *Attribute) bool { return x.Name == y.Name })
+		i = j
+	}
+
+	var changes []Change
+	for i, e := range script {
+		j, paired := partners[i]
+		switch {
+		case e.op == '-' && !paired:
+			attr := &a.Attributes[e.old]
+			changes = append(changes, Change{Kind: Removed, Name: attr.Name, OldValue: attr.Value, OldIndex: e.old, NewIndex: -1})
+		case e.op == '-' && !sameAttribute(&a.Attributes[e.old], &b.Attributes[script[j].new]):
+			old, attr := &a.Attributes[e.old], &b.Attributes[script[j].new]
+			changes = append(changes, Change{Kind: Modified, Name: attr.Name, OldValue: old.Value, NewValue: attr.Value, OldIndex: e.old, NewIndex: script[j].new})
+		case e.op == '+' && !paired:
+			attr := &b.Attributes[e.new]
+			changes = append(changes, Change{Kind: Added, Name: attr.Name, NewValue: attr.Value, OldIndex: -1, NewIndex: e.new})
+		case e.op == '+' && sameAttribute(&a.Attributes[script[j].old], &b.Attributes[e.new]):
+			// Moved attributes are reported at their new place.
+			old, attr := &a.Attributes[script[j].old], &b.Attributes[e.new]
+			changes = append(changes, Change{Kind: Moved, Name: attr.Name, OldValue: old.Value, NewValue: attr.Value, OldIndex: script[j].old, NewIndex: e.new})
+		}
+	}
+
+	return changes
+}
+
+// UnifiedDiff returns the differences between the objects a and b in the unified diff format, one line per attribute
+// with context lines around the changes, as shown by the RIPE database for "--diff-versions". It returns an empty
+// string when the objects are the same.
+//
+// Example:
+//
+//	@@ -1,4 +1,4 @@
+//	 mntner:         EXAMPLE-MNT
+//	-descr:          Old description
+//	+descr:          New description
+//	 mnt-by:         EXAMPLE-MNT
+func UnifiedDiff(a *Object, b *Object, context int) string {
+	script := editScript(a.Attributes, b.Attributes)
+	f := Formatter{}
+	line := func(prefix byte, attr *Attribute) string {
+		var buf bytes.Buffer
+		buf.WriteByte(prefix)
+		f.appendAttribute(&buf, &Attribute{Name: attr.Name, Value: attr.Value})
+		return buf.String()
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(script); {
+		if script[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// A hunk extends over the changes separated by at most twice the context.
+		start := max(0, i-context)
+		end := i
+		for end < len(script) {
+			if script[end].op != ' ' {
+				end++
+				continue
+			}
+
+			next := end
+			for next < len(script) && script[next].op == ' ' {
+				next++
+			}
+
+			if next == len(script) || next-end > 2*context {
+				break
+			}
+
+			end = next
+		}
+
+		end = min(len(script), end+context)
+
+		var oldStart, newStart, oldCount, newCount int
+		var body strings.Builder
+		for _, e := range script[start:end] {
+			if e.op != '+' {
+				if oldCount == 0 {
+					oldStart = e.old + 1
+				}
+
+				oldCount++
+			}
+
+			if e.op != '-' {
+				if newCount == 0 {
+					newStart = e.new + 1
+				}
+
+				newCount++
+			}
+
+			switch e.op {
+			case '-':
+				body.WriteString(line('-', &a.Attributes[e.old]))
+			case '+':
+				body.WriteString(line('+', &b.Attributes[e.new]))
+			default:
+				body.WriteString(line(' ', &b.Attributes[e.new]))
+			}
+		}
+
+		// An empty range starts after the line preceding it, as in diff.
+		if oldCount == 0 {
+			oldStart = position(script[:start], true)
+		}
+
+		if newCount == 0 {
+			newStart = position(script[:start], false)
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		out.WriteString(body.String())
+		i = end
+	}
+
+	return out.String()
+}
+
+// position returns the number of attributes of the old or new object in the edit script.
+func position(script []edit, old bool) int {
+	n := 0
+	for _, e := range script {
+		if old && e.op != '+' || !old && e.op != '-' {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Merge merges the changes made to the object base in the objects local and upstream, such as local edits and the
+// changes received from a mirror. Parts changed on one side only take this change; parts changed identically on both
+// sides take it once. Attributes added at the same place on both sides are all kept, unless they are single-valued
+// attributes of the class with different values.
+//
+// Other parts changed on both sides are conflicts, reported attribute by attribute when the parts have the same
+// attribute names. The merged object keeps the local version of these parts and the conflicts are returned, to be
+// resolved by the caller.
+func Merge(base *Object, local *Object, upstream *Object) (*Object, []Conflict) {
+	toLocal := matches(base.Attributes, local.Attributes)
+	toUpstream := matches(base.Attributes, upstream.Attributes)
+	t := LookupTemplate(base.Class())
+
+	merged := &Object{}
+	var conflicts []Conflict
+	b, l, u := 0, 0, 0
+	for {
+		// The next attribute unchanged on both sides ends the current part.
+		next := b
+		for next < len(base.Attributes) && (toLocal[next] < 0 || toUpstream[next] < 0) {
+			next++
+		}
+
+		endLocal, endUpstream := len(local.Attributes), len(upstream.Attributes)
+		if next < len(base.Attributes) {
+			endLocal, endUpstream = toLocal[next], toUpstream[next]
+		}
+
+		baseAttrs, localAttrs, upstreamAttrs := base.Attributes[b:next], local.Attributes[l:endLocal], upstream.Attributes[u:endUpstream]
+		switch {
+		case sameAttributes(localAttrs, baseAttrs):
+			merged.Attributes = append(merged.Attributes, upstreamAttrs...)
+		case sameAttributes(upstreamAttrs, baseAttrs), sameAttributes(localAttrs, upstreamAttrs):
+			merged.Attributes = append(merged.Attributes, localAttrs...)
+		case len(baseAttrs) == 0 && compatible(t, localAttrs, upstreamAttrs):
+			merged.Attributes = append(merged.Attributes, localAttrs...)
+			for i := range upstreamAttrs {
+				if !slices.ContainsFunc(localAttrs, func(attr Attribute) bool { return sameAttribute(&attr, &upstreamAttrs[i]) }) {
+					merged.Attributes = append(merged.Attributes, upstreamAttrs[i])
+				}
+			}
+		case aligned(baseAttrs, localAttrs, upstreamAttrs):
+			// Adjacent attributes changed on different sides are merged one by one.
+			for i := range baseAttrs {
+				part, conflict := mergeAttribute(&baseAttrs[i], &localAttrs[i], &upstreamAttrs[i])
+				merged.Attributes = append(merged.Attributes, part)
+				if conflict != nil {
+					conflicts = append(conflicts, *conflict)
+				}
+			}
+		default:
+			merged.Attributes = append(merged.Attributes, localAttrs...)
+			conflicts = append(conflicts, Conflict{
+				Base:     slices.Clone(baseAttrs),
+				Local:    slices.Clone(localAttrs),
+				Upstream: slices.Clone(upstreamAttrs),
+			})
+		}
+
+		if next == len(base.Attributes) {
+			return merged, conflicts
+		}
+
+		merged.Attributes = append(merged.Attributes, local.Attributes[endLocal])
+		b, l, u = next+1, endLocal+1, endUpstream+1
+	}
+}
+
+// aligned returns true if the three parts of a merge have the same attribute names in the same order.
+func aligned(base []Attribute, local []Attribute, upstream []Attribute) bool {
+	if len(base) != len(local) || len(base) != len(upstream) {
+		return false
+	}
+
+	for i := range base {
+		if base[i].Name != local[i].Name || base[i].Name != upstream[i].Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeAttribute merges an attribute changed on either side of a merge, and returns a conflict if both sides changed
+// it differently.
+func mergeAttribute(base *Attribute, local *Attribute, upstream *Attribute) (Attribute, *Conflict) {
+	switch {
+	case sameAttribute(local, base):
+		return *upstream, nil
+	case sameAttribute(upstream, base), sameAttribute(local, upstream):
+		return *local, nil
+	default:
+		return *local, &Conflict{Base: []Attribute{*base}, Local: []Attribute{*local}, Upstream: []Attribute{*upstream}}
+	}
+}
+
+// compatible returns true if the attributes added on both sides of a merge can all be kept: no single-valued
+// attribute of the class, or attribute unknown to it, is added with different values.
+func compatible(t *Template, local []Attribute, upstream []Attribute) bool {
+	for i := range local {
+		for j := range upstream {
+			if local[i].Name != upstream[j].Name || sameAttribute(&local[i], &upstream[j]) {
+				continue
+			}
+
+			if t == nil || t.Attribute(local[i].Name) == nil || t.Attribute(local[i].Name).Cardinality == Single {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// editScript returns the shortest edit script turning the attributes a into the attributes b.
+func editScript(a []Attribute, b []Attribute) []edit {
+	match := matches(a, b)
+	script := make([]edit, 0, max(len(a), len(b)))
+	j := 0
+	for i := range a {
+		if match[i] < 0 {
+			script = append(script, edit{op: '-', old: i, new: -1})
+			continue
+		}
+
+		for ; j < match[i]; j++ {
+			script = append(script, edit{op: '+', old: -1, new: j})
+		}
+
+		script = append(script, edit{op: ' ', old: i, new: j})
+		j++
+	}
+
+	for ; j < len(b); j++ {
+		script = append(script, edit{op: '+', old: -1, new: j})
+	}
+
+	return script
+}
+
+// matches returns, for every attribute of a, the position of the same attribute in b in a longest common subsequence
+// of the attributes, or -1 if it is not part of it.
+func matches(a []Attribute, b []Attribute) []int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if sameAttribute(&a[i], &b[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	match := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && sameAttribute(&a[i], &b[j]):
+			match[i] = j
+			i++
+			j++
+		case j < len(b) && lengths[i][j+1] >= lengths[i+1][j]:
+			j++
+		default:
+			match[i] = -1
+			i++
+		}
+	}
+
+	return match
+}
+
+// sameAttribute returns true if the attributes have the same name and value, ignoring differences in whitespace.
+func sameAttribute(a *Attribute, b *Attribute) bool {
+	return a.Name == b.Name && (a.Value == b.Value || strings.Join(strings.Fields(a.Value), " ") == strings.Join(strings.Fields(b.Value), " "))
+}
+
+// sameAttributes returns true if the attributes are the same, in the same order.
+func sameAttributes(a []Attribute, b []Attribute) bool {
+	return slices.EqualFunc(a, b, func(x Attribute, y Attribute) bool { return sameAttribute(&x, &y) })
+}
@@ -0,0 +1,199 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mustParse parses an object or fails the test.
+func mustParse(t *testing.T, raw string) *Object {
+	obj, err := Parse(raw)
+	if err != nil {
+		t.Fatalf(`Parse => %v`, err)
+	}
+
+	return obj
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Change
+	}{
+		{
+			name: "Same",
+			a:    "mntner: EXAMPLE-MNT\ndescr:  Example\nsource: TEST\n",
+			b:    "mntner:    EXAMPLE-MNT\ndescr:     Example\nsource:    TEST\n",
+			want: nil,
+		},
+		{
+			name: "Modified",
+			a:    "mntner: EXAMPLE-MNT\ndescr: Old\nsource: TEST\n",
+			b:    "mntner: EXAMPLE-MNT\ndescr: New\nsource: TEST\n",
+			want: []Change{{Kind: Modified, Name: "descr", OldValue: "Old", NewValue: "New", OldIndex: 1, NewIndex: 1}},
+		},
+		{
+			name: "AddedAndRemoved",
+			a:    "mntner: EXAMPLE-MNT\nremarks: Gone\nsource: TEST\n",
+			b:    "mntner: EXAMPLE-MNT\nsource: TEST\nmnt-by: EXAMPLE-MNT\n",
+			want: []Change{
+				{Kind: Removed, Name: "remarks", OldValue: "Gone", OldIndex: 1, NewIndex: -1},
+				{Kind: Added, Name: "mnt-by", NewValue: "EXAMPLE-MNT", OldIndex: -1, NewIndex: 2},
+			},
+		},
+		{
+			name: "Moved",
+			a:    "as-set: AS-EXAMPLE\nmembers: AS64496\nmembers: AS64497\nmnt-by: A-MNT\nmnt-by: B-MNT\n",
+			b:    "as-set: AS-EXAMPLE\nmembers: AS64497\nmembers: AS64496\nmnt-by: A-MNT\nmnt-by: B-MNT\n",
+			want: []Change{{Kind: Moved, Name: "members", OldValue: "AS64497", NewValue: "AS64497", OldIndex: 2, NewIndex: 1}},
+		},
+		{
+			name: "MultipleValues",
+			a:    "route: 192.0.2.0/24\norigin: AS64496\nmnt-by: A-MNT\nmnt-by: B-MNT\nsource: TEST\n",
+			b:    "route: 192.0.2.0/24\norigin: AS64496\nmnt-by: A-MNT\nmnt-by: C-MNT\nmnt-by: B-MNT\nsource: TEST\n",
+			want: []Change{{Kind: Added, Name: "mnt-by", NewValue: "C-MNT", OldIndex: -1, NewIndex: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(mustParse(t, tt.a), mustParse(t, tt.b))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf(`Diff => %+v, want %+v`, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := mustParse(t, "mntner: EXAMPLE-MNT\ndescr: Old\nadmin-c: JD1-TEST\nupd-to: noc@example.net\nauth: SSO noc@example.net\n"+
+		"mnt-by: EXAMPLE-MNT\nsource: TEST\n")
+	b := mustParse(t, "mntner: EXAMPLE-MNT\ndescr: New\nadmin-c: JD1-TEST\nupd-to: noc@example.net\nauth: SSO noc@example.net\n"+
+		"mnt-by: EXAMPLE-MNT\nsource: TEST\nremarks: Added\n")
+
+	tests := []struct {
+		context int
+		want    string
+	}{
+		{
+			context: 1,
+			want: "@@ -1,3 +1,3 @@\n" +
+				" mntner:         EXAMPLE-MNT\n" +
+				"-descr:          Old\n" +
+				"+descr:          New\n" +
+				" admin-c:        JD1-TEST\n" +
+				"@@ -7,1 +7,2 @@\n" +
+				" source:         TEST\n" +
+				"+remarks:        Added\n",
+		},
+		{
+			context: 0,
+			want: "@@ -2,1 +2,1 @@\n" +
+				"-descr:          Old\n" +
+				"+descr:          New\n" +
+				"@@ -7,0 +8,1 @@\n" +
+				"+remarks:        Added\n",
+		},
+		{
+			context: 3,
+			want: "@@ -1,7 +1,8 @@\n" +
+				" mntner:         EXAMPLE-MNT\n" +
+				"-descr:          Old\n" +
+				"+descr:          New\n" +
+				" admin-c:        JD1-TEST\n" +
+				" upd-to:         noc@example.net\n" +
+				" auth:           SSO noc@example.net\n" +
+				" mnt-by:         EXAMPLE-MNT\n" +
+				" source:         TEST\n" +
+				"+remarks:        Added\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := UnifiedDiff(a, b, tt.context); got != tt.want {
+			t.Errorf(`UnifiedDiff(%d) => %q, want %q`, tt.context, got, tt.want)
+		}
+	}
+
+	if got := UnifiedDiff(a, a, 3); got != "" {
+		t.Errorf(`UnifiedDiff(a, a) => %q, want ""`, got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\nsource: TEST\n"
+
+	tests := []struct {
+		name            string
+		local, upstream string
+		want            string
+		conflicts       int
+	}{
+		{
+			name:     "Unchanged",
+			local:    base,
+			upstream: base,
+			want:     base,
+		},
+		{
+			name:     "BothSides",
+			local:    "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Local\nmnt-by: A-MNT\nsource: TEST\n",
+			upstream: "aut-num: AS64496\nas-name: EXAMPLE-NET\ndescr: Base\nmnt-by: A-MNT\nsource: TEST\n",
+			want:     "aut-num: AS64496\nas-name: EXAMPLE-NET\ndescr: Local\nmnt-by: A-MNT\nsource: TEST\n",
+		},
+		{
+			name:     "SameChange",
+			local:    "aut-num: AS64496\nas-name: EXAMPLE\ndescr: New\nmnt-by: A-MNT\nsource: TEST\n",
+			upstream: "aut-num: AS64496\nas-name: EXAMPLE\ndescr: New\nmnt-by: A-MNT\nsource: TEST\n",
+			want:     "aut-num: AS64496\nas-name: EXAMPLE\ndescr: New\nmnt-by: A-MNT\nsource: TEST\n",
+		},
+		{
+			name:     "MultipleAdded",
+			local:    "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\nmnt-by: B-MNT\nsource: TEST\n",
+			upstream: "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\nmnt-by: C-MNT\nsource: TEST\n",
+			want:     "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\nmnt-by: B-MNT\nmnt-by: C-MNT\nsource: TEST\n",
+		},
+		{
+			name:      "SingleAdded",
+			local:     "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\norg: ORG-A\nsource: TEST\n",
+			upstream:  "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\norg: ORG-B\nsource: TEST\n",
+			want:      "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Base\nmnt-by: A-MNT\norg: ORG-A\nsource: TEST\n",
+			conflicts: 1,
+		},
+		{
+			name:      "Conflict",
+			local:     "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Local\nmnt-by: A-MNT\nsource: TEST\n",
+			upstream:  "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Upstream\nmnt-by: A-MNT\nsource: TEST\n",
+			want:      "aut-num: AS64496\nas-name: EXAMPLE\ndescr: Local\nmnt-by: A-MNT\nsource: TEST\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := Merge(mustParse(t, base), mustParse(t, tt.local), mustParse(t, tt.upstream))
+			if want := mustParse(t, tt.want); merged.String() != want.String() {
+				t.Errorf(`Merge => %q, want %q`, merged.String(), want.String())
+			}
+
+			if len(conflicts) != tt.conflicts {
+				t.Errorf(`Merge => %d conflicts, want %d`, len(conflicts), tt.conflicts)
+			}
+		})
+	}
+
+	_, conflicts := Merge(mustParse(t, base), mustParse(t, tests[5].local), mustParse(t, tests[5].upstream))
+	want := Conflict{
+		Base:     []Attribute{{Name: "descr", Value: "Base", Line: 3}},
+		Local:    []Attribute{{Name: "descr", Value: "Local", Line: 3}},
+		Upstream: []Attribute{{Name: "descr", Value: "Upstream", Line: 3}},
+	}
+
+	if !reflect.DeepEqual(conflicts, []Conflict{want}) {
+		t.Errorf(`Merge => %+v, want %+v`, conflicts, want)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes a syntax error in an RPSL input. It locates the error in the original input, independently of
+// how the input was split into objects, and can be retrieved with errors.As.
+type ParseError struct {
+	// Object is the zero-based index of the object in the input.
+	Object int
+	// Line is the one-based line number in the input. It is zero when unknown.
+	Line int
+	// Column is the one-based byte column in the line. It is zero when unknown.
+	Column int
+	// Attribute is the name of the offending attribute, or as much of it as could be read.
+	Attribute string
+	// Snippet is the content of the offending line.
+	Snippet string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns a string representation of the ParseError.
+func (e *ParseError) Error() string {
+	var str strings.Builder
+	fmt.Fprintf(&str, "object %d", e.Object)
+	if e.Line > 0 {
+		fmt.Fprintf(&str, ", line %d", e.Line)
+	}
+
+	if e.Column > 0 {
+		fmt.Fprintf(&str, ", column %d", e.Column)
+	}
+
+	if e.Attribute != "" {
+		fmt.Fprintf(&str, ", attribute '%s'", e.Attribute)
+	}
+
+	fmt.Fprintf(&str, ": %v", e.Err)
+	if e.Snippet != "" {
+		fmt.Fprintf(&str, " in %q", e.Snippet)
+	}
+
+	return str.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors is a report of the objects that were skipped while parsing in lenient mode. Each ParseError can be
+// retrieved with errors.As.
+type ParseErrors []*ParseError
+
+// Error returns a string representation of the ParseErrors.
+func (e ParseErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	var str strings.Builder
+	fmt.Fprintf(&str, "%d objects could not be parsed:", len(e))
+	for _, err := range e {
+		str.WriteString("\n\t")
+		str.WriteString(err.Error())
+	}
+
+	return str.String()
+}
+
+// Unwrap returns the errors of the skipped objects.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
@@ -0,0 +1,96 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorLocation(t *testing.T) {
+	raw := "% Header comment\n" +
+		"\n" +
+		"person:  John Doe\n" +
+		"source:  TEST\n" +
+		"\n" +
+		"person:  Jane Smith\n" +
+		"% Comment inside the object\n" +
+		"address: 123 Main St\n" +
+		"  Springfield\n" +
+		"bad key: value\n" +
+		"source:  TEST\n"
+
+	_, err := ParseMany(raw)
+	if err == nil {
+		t.Fatalf(`ParseMany() => nil error, want error`)
+	}
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf(`errors.As(%v) => false, want true`, err)
+	}
+
+	if perr.Object != 1 {
+		t.Errorf(`perr.Object => %v, want %v`, perr.Object, 1)
+	}
+
+	if perr.Line != 10 {
+		t.Errorf(`perr.Line => %v, want %v`, perr.Line, 10)
+	}
+
+	if perr.Column != 4 {
+		t.Errorf(`perr.Column => %v, want %v`, perr.Column, 4)
+	}
+
+	if perr.Attribute != "bad" {
+		t.Errorf(`perr.Attribute => %v, want %v`, perr.Attribute, "bad")
+	}
+
+	if perr.Snippet != "bad key: value" {
+		t.Errorf(`perr.Snippet => %v, want %v`, perr.Snippet, "bad key: value")
+	}
+
+	if !strings.Contains(err.Error(), "line 10, column 4") {
+		t.Errorf(`err.Error() => %v, want line and column`, err.Error())
+	}
+}
+
+func TestParseErrorZeroSizedKey(t *testing.T) {
+	_, err := Parse("person: John Doe\n: value")
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf(`errors.As(%v) => false, want true`, err)
+	}
+
+	if perr.Line != 2 || perr.Column != 1 {
+		t.Errorf(`perr => line %v, column %v, want line %v, column %v`, perr.Line, perr.Column, 2, 1)
+	}
+
+	if !strings.Contains(perr.Err.Error(), "zero-sized key") {
+		t.Errorf(`perr.Err => %v, want zero-sized key`, perr.Err)
+	}
+}
+
+func TestAttributeLine(t *testing.T) {
+	raw := "\n" +
+		"person:  John Doe\n" +
+		"address: 123 Main St\n" +
+		"+        Springfield\n" +
+		"# Comment inside the object\n" +
+		"source:  TEST\n"
+
+	obj, err := Parse(raw)
+	if err != nil {
+		t.Fatalf(`Parse() => %v`, err)
+	}
+
+	expected := []int{2, 3, 6}
+	for i, attr := range obj.Attributes {
+		if attr.Line != expected[i] {
+			t.Errorf(`obj.Attributes[%d].Line => %v, want %v`, i, attr.Line, expected[i])
+		}
+	}
+}
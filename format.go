@@ -0,0 +1,176 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Formatter writes objects in the layout of the RIPE database, with values aligned on a column and long values
+// wrapped onto continuation lines. The zero value aligns values at column 16 and does not wrap them.
+//
+// Example:
+//
+//	f := Formatter{Width: 72, Continuation: '+'}
+//	if err := f.WriteObjects(os.Stdout, objs); err != nil {
+//	    log.Fatalf("Failed to write RPSL objects: %v", err)
+//	}
+type Formatter struct {
+	// Column is the zero-based column at which values are aligned. Defaults to 16.
+	Column int
+	// Width is the maximum length of a line. Longer values are wrapped at whitespace onto continuation lines, words
+	// longer than a line are never split. Zero disables wrapping.
+	Width int
+	// Continuation is the character starting continuation lines, one of '+', ' ' or '\t'. Defaults to ' '. With '+'
+	// and ' ', continuation lines are aligned on Column; with '\t', values follow the tab.
+	Continuation byte
+	// Comments emits the end-of-line comments of the attributes parsed in lossless mode.
+	Comments bool
+}
+
+// Format returns the formatted text of the Object, each line terminated by a newline.
+func (f *Formatter) Format(o *Object) string {
+	var buf bytes.Buffer
+	f.appendObject(&buf, o)
+	return buf.String()
+}
+
+// WriteObject writes the formatted text of the Object to w.
+func (f *Formatter) WriteObject(w io.Writer, o *Object) error {
+	var buf bytes.Buffer
+	f.appendObject(&buf, o)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteObjects writes the formatted text of the objects to w, separated by empty lines.
+func (f *Formatter) WriteObjects(w io.Writer, objs []Object) error {
+	var buf bytes.Buffer
+	for i := range objs {
+		buf.Reset()
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+
+		f.appendObject(&buf, &objs[i])
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendObject appends the formatted text of the Object to buf.
+func (f *Formatter) appendObject(buf *bytes.Buffer, o *Object) {
+	for i := range o.Attributes {
+		f.appendAttribute(buf, &o.Attributes[i])
+	}
+}
+
+// appendAttribute appends the formatted text of the Attribute to buf.
+func (f *Formatter) appendAttribute(buf *bytes.Buffer, a *Attribute) {
+	column := f.Column
+	if column <= 0 {
+		column = valueColumn
+	}
+
+	buf.WriteString(a.Name)
+	buf.WriteByte(':')
+
+	comment := ""
+	if f.Comments {
+		comment = a.Comment()
+	}
+
+	if a.Value == "" {
+		if comment != "" {
+			buf.WriteString(" # ")
+			buf.WriteString(comment)
+		}
+
+		buf.WriteByte('\n')
+		return
+	}
+
+	pad(buf, len(a.Name)+1, column)
+
+	// Continuation lines are padded up to the column, or start after a tab stop.
+	next := column
+	if f.Continuation == '\t' {
+		next = 8
+	}
+
+	lines := f.wrap(a.Value, max(column, len(a.Name)+2), next)
+	buf.WriteString(lines[0])
+	if comment != "" {
+		buf.WriteString(" # ")
+		buf.WriteString(comment)
+	}
+
+	buf.WriteByte('\n')
+
+	for _, line := range lines[1:] {
+		switch f.Continuation {
+		case '+':
+			buf.WriteByte('+')
+			pad(buf, 1, column)
+		case '\t':
+			buf.WriteByte('\t')
+		default:
+			pad(buf, 0, column)
+		}
+
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+}
+
+// wrap splits value into lines fitting in the width, the first line starting at column first and the others at column
+// next.
+func (f *Formatter) wrap(value string, first int, next int) []string {
+	if f.Width <= 0 || first+len(value) <= f.Width {
+		return []string{value}
+	}
+
+	lines := make([]string, 0, 2)
+	line := ""
+	start := first
+	for _, word := range strings.Fields(value) {
+		if line != "" && start+len(line)+1+len(word) > f.Width {
+			lines = append(lines, line)
+			line = ""
+			start = next
+		}
+
+		if line != "" {
+			line += " "
+		}
+
+		line += word
+	}
+
+	return append(lines, line)
+}
+
+// pad appends spaces to buf to move from column from to column to, appending at least one space.
+func pad(buf *bytes.Buffer, from int, to int) {
+	for i := from; i < to || i == from; i++ {
+		buf.WriteByte(' ')
+	}
+}
+
+// joinComments joins the non-empty comments with a space.
+func joinComments(comments []string) string {
+	nonEmpty := make([]string, 0, len(comments))
+	for _, c := range comments {
+		if c != "" {
+			nonEmpty = append(nonEmpty, c)
+		}
+	}
+
+	return strings.Join(nonEmpty, " ")
+}
@@ -0,0 +1,130 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	obj := Object{
+		Attributes: []Attribute{
+			{Name: "person", Value: "John Doe"},
+			{Name: "remarks", Value: ""},
+			{Name: "abuse-mailbox-long", Value: "abuse@example.net"},
+			{Name: "source", Value: "TEST"},
+		},
+	}
+
+	expected := "person:         John Doe\n" +
+		"remarks:\n" +
+		"abuse-mailbox-long: abuse@example.net\n" +
+		"source:         TEST\n"
+
+	f := Formatter{}
+	if got := f.Format(&obj); got != expected {
+		t.Fatalf(`Format() => %q, want %q`, got, expected)
+	}
+}
+
+func TestFormatWrap(t *testing.T) {
+	obj := Object{
+		Attributes: []Attribute{
+			{Name: "descr", Value: "The quick brown fox jumps over the lazy dog"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		continuation byte
+		expected     string
+	}{
+		{
+			name:         "Space",
+			continuation: ' ',
+			expected: "descr:          The quick brown\n" +
+				"                fox jumps over\n" +
+				"                the lazy dog\n",
+		},
+		{
+			name:         "Plus",
+			continuation: '+',
+			expected: "descr:          The quick brown\n" +
+				"+               fox jumps over\n" +
+				"+               the lazy dog\n",
+		},
+		{
+			name:         "Tab",
+			continuation: '\t',
+			expected: "descr:          The quick brown\n" +
+				"\tfox jumps over the lazy\n" +
+				"\tdog\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := Formatter{Width: 32, Continuation: tc.continuation}
+			got := f.Format(&obj)
+			if got != tc.expected {
+				t.Fatalf(`Format() => %q, want %q`, got, tc.expected)
+			}
+
+			// Wrapped values parse back to the same value.
+			parsed, err := Parse(got)
+			if err != nil {
+				t.Fatalf(`Parse() => %v`, err)
+			}
+
+			if parsed.Attributes[0].Value != obj.Attributes[0].Value {
+				t.Fatalf(`Parse().Value => %q, want %q`, parsed.Attributes[0].Value, obj.Attributes[0].Value)
+			}
+		})
+	}
+}
+
+func TestFormatComments(t *testing.T) {
+	obj, err := ParseWithOptions("mntner:  DEV-MNT # Comment\nsource:  DEV", ParseOptions{Lossless: true})
+	if err != nil {
+		t.Fatalf(`ParseWithOptions() => %v`, err)
+	}
+
+	f := Formatter{Column: 10, Comments: true}
+	expected := "mntner:   DEV-MNT # Comment\n" +
+		"source:   DEV\n"
+
+	if got := f.Format(obj); got != expected {
+		t.Fatalf(`Format() => %q, want %q`, got, expected)
+	}
+}
+
+func TestWriteObjects(t *testing.T) {
+	objs, err := ParseMany("person: John Doe\nsource: TEST\n\n\n\nperson: Jane Smith\nsource: TEST")
+	if err != nil {
+		t.Fatalf(`ParseMany() => %v`, err)
+	}
+
+	var buf bytes.Buffer
+	f := Formatter{}
+	if err := f.WriteObjects(&buf, objs); err != nil {
+		t.Fatalf(`WriteObjects() => %v`, err)
+	}
+
+	expected := "person:         John Doe\n" +
+		"source:         TEST\n" +
+		"\n" +
+		"person:         Jane Smith\n" +
+		"source:         TEST\n"
+
+	if buf.String() != expected {
+		t.Fatalf(`WriteObjects() => %q, want %q`, buf.String(), expected)
+	}
+
+	parsed, err := ParseManyFromReader(strings.NewReader(buf.String()))
+	if err != nil || len(parsed) != 2 {
+		t.Fatalf(`ParseManyFromReader() => %v objects, %v`, len(parsed), err)
+	}
+}
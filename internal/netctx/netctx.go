@@ -0,0 +1,43 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package netctx ties the lifetime of network connections to contexts, for the clients of the rpsl-go packages.
+package netctx
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Watch interrupts the pending and future reads and writes on the connection once the context is done. The returned
+// function stops watching.
+//
+// The deadline of the context is not applied to the connection, since its timer may fire before the one of the
+// context: an interrupted read would then report an i/o timeout rather than context.DeadlineExceeded.
+func Watch(ctx context.Context, conn net.Conn) func() {
+	done := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(done)
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+
+	return func() {
+		if !stop() {
+			// The interruption has started: wait for it before clearing the deadline, so that it cannot be left on a
+			// connection that is reused.
+			<-done
+		}
+
+		_ = conn.SetDeadline(time.Time{})
+	}
+}
+
+// Err returns the error of the context if it is done, since it caused err.
+func Err(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
@@ -0,0 +1,41 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package netctx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	stop := Watch(ctx, client)
+	_, err := client.Read(make([]byte, 1))
+	stop()
+	if err = Err(ctx, err); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(`Read => %v, want %v`, err, context.DeadlineExceeded)
+	}
+
+	// The connection is usable again once the watch stopped, even when the context was canceled as it stopped.
+	for range 100 {
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := Watch(ctx, client)
+		cancel()
+		stop()
+
+		go func() { _, _ = server.Write([]byte{'x'}) }()
+		if _, err := client.Read(make([]byte, 1)); err != nil {
+			t.Fatalf(`Read after stop => %v, want nil`, err)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Unmarshal stores the attributes of an Object in the struct pointed to by v. Only the fields tagged with the name of
+// an attribute are set, with the following options:
+//
+//	Name     string     `rpsl:"mntner,mandatory"`  // the attribute must be present
+//	MntBy    []string   `rpsl:"mnt-by,multiple"`   // every attribute is stored
+//	Members  []string   `rpsl:"members,list"`      // every comma-separated item of every attribute is stored
+//	Origin   uint32     `rpsl:"origin,asn"`        // the value is an AS number such as "AS64496"
+//	Created  time.Time  `rpsl:"created"`
+//	Internal string     `rpsl:"-"`
+//
+// Fields can be strings, integers, types implementing encoding.TextUnmarshaler and encoding.TextMarshaler such as
+// netip.Prefix and time.Time, or slices of those. Anonymous struct fields are flattened. A non-slice field stores a
+// single attribute, and it is an error for the Object to have more than one.
+//
+// Values that cannot be parsed are reported as ValidationErrors, and the other fields are still set.
+func Unmarshal(o *Object, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("rpsl: Unmarshal requires a non-nil pointer to a struct, got %T", v)
+	}
+
+	fields, err := cachedFields(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	d := &decoder{o: o, class: o.Class()}
+	for _, f := range fields {
+		f.decode(d, rv.Elem().FieldByIndex(f.index))
+	}
+
+	return d.err()
+}
+
+// Marshal converts the struct v, or the struct it points to, to an Object. Attributes are written in the order of
+// the fields, and empty fields are omitted. See Unmarshal for the supported tags.
+func Marshal(v any) (*Object, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rpsl: Marshal requires a struct, got %T", v)
+	}
+
+	fields, err := cachedFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var e encoder
+	for _, f := range fields {
+		if err := f.encode(&e, rv.FieldByIndex(f.index)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e.o, nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// field is a struct field tagged with an attribute name.
+type field struct {
+	index     []int
+	name      string
+	mandatory bool
+	list      bool
+	asn       bool
+}
+
+// fieldCache maps struct types to their fields.
+var fieldCache sync.Map
+
+// cachedFields returns the tagged fields of a struct type.
+func cachedFields(t reflect.Type) ([]field, error) {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field), nil
+	}
+
+	fields, err := typeFields(t, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+// typeFields returns the tagged fields of a struct type, flattening anonymous struct fields.
+func typeFields(t reflect.Type, index []int) ([]field, error) {
+	var fields []field
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag, tagged := sf.Tag.Lookup("rpsl")
+		idx := append(index[:len(index):len(index)], i)
+		if !tagged && sf.Anonymous && sf.Type.Kind() == reflect.Struct && !isText(sf.Type) {
+			embedded, err := typeFields(sf.Type, idx)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, embedded...)
+			continue
+		}
+
+		if !tagged || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		f := field{index: idx, name: strings.ToLower(name)}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "", "multiple":
+			case "mandatory":
+				f.mandatory = true
+			case "list":
+				f.list = true
+			case "asn":
+				f.asn = true
+			default:
+				return nil, fmt.Errorf("rpsl: field %s has an unknown option '%s'", sf.Name, opt)
+			}
+		}
+
+		typ := sf.Type
+		if typ.Kind() == reflect.Slice && !isText(typ) {
+			typ = typ.Elem()
+		} else if f.list {
+			return nil, fmt.Errorf("rpsl: field %s has the option 'list' but is not a slice", sf.Name)
+		}
+
+		if !isScalar(typ) {
+			return nil, fmt.Errorf("rpsl: field %s has an unsupported type %s", sf.Name, sf.Type)
+		}
+
+		if f.asn && typ.Kind() != reflect.Uint32 && typ.Kind() != reflect.Uint64 && typ.Kind() != reflect.Uint {
+			return nil, fmt.Errorf("rpsl: field %s has the option 'asn' but is not an unsigned integer", sf.Name)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// isText returns true if the type can be converted from and to text.
+func isText(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType) &&
+		(t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType))
+}
+
+// isScalar returns true if a value of the type can be stored in a single attribute.
+func isScalar(t reflect.Type) bool {
+	if isText(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// decode stores the attributes of the field.
+func (f *field) decode(d *decoder, v reflect.Value) {
+	if f.mandatory {
+		d.require(f.name)
+	}
+
+	if v.Kind() != reflect.Slice || isText(v.Type()) {
+		if err := d.o.EnsureAtMostOne(f.name); err != nil {
+			d.errs = append(d.errs, &ValidationError{Class: d.class, Attribute: f.name, Err: err})
+		}
+
+		d.first(f.name, func(value string) error {
+			return f.decodeValue(v, value)
+		})
+
+		return
+	}
+
+	items := reflect.MakeSlice(v.Type(), 0, 0)
+	d.each(f.name, func(value string) error {
+		values := []string{value}
+		if f.list {
+			values = splitList(value)
+		}
+
+		for _, value := range values {
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := f.decodeValue(item, value); err != nil {
+				return err
+			}
+
+			items = reflect.Append(items, item)
+		}
+
+		return nil
+	})
+
+	if items.Len() > 0 {
+		v.Set(items)
+	}
+}
+
+// decodeValue parses a single value into v.
+func (f *field) decodeValue(v reflect.Value, value string) error {
+	if isText(v.Type()) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f.asn {
+			asn, err := ParseASN(value)
+			if err != nil {
+				return err
+			}
+
+			v.SetUint(uint64(asn))
+			return nil
+		}
+
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+
+		v.SetUint(n)
+	}
+
+	return nil
+}
+
+// encode appends the attributes of the field.
+func (f *field) encode(e *encoder, v reflect.Value) error {
+	n := len(e.o.Attributes)
+	if v.Kind() != reflect.Slice || isText(v.Type()) {
+		if err := f.encodeValue(e, v); err != nil {
+			return err
+		}
+	} else if f.list {
+		items := make([]string, 0, v.Len())
+		for i := range v.Len() {
+			var item encoder
+			if err := f.encodeValue(&item, v.Index(i)); err != nil {
+				return err
+			}
+
+			for _, attr := range item.o.Attributes {
+				items = append(items, attr.Value)
+			}
+		}
+
+		e.addList(f.name, items)
+	} else {
+		for i := range v.Len() {
+			if err := f.encodeValue(e, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if f.mandatory && len(e.o.Attributes) == n {
+		return fmt.Errorf("rpsl: attribute '%s' is mandatory but its field is empty", f.name)
+	}
+
+	return nil
+}
+
+// encodeValue appends an attribute holding a single value, unless it is the zero value.
+func (f *field) encodeValue(e *encoder, v reflect.Value) error {
+	if v.IsZero() {
+		return nil
+	}
+
+	if isText(v.Type()) {
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			ptr := reflect.New(v.Type())
+			ptr.Elem().Set(v)
+			m = ptr.Interface().(encoding.TextMarshaler)
+		}
+
+		text, err := m.MarshalText()
+		if err != nil {
+			return fmt.Errorf("rpsl: attribute '%s': %w", f.name, err)
+		}
+
+		e.add(f.name, string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if strings.ContainsAny(v.String(), "\r\n") {
+			return fmt.Errorf("rpsl: attribute '%s' has a value spanning multiple lines", f.name)
+		}
+
+		e.add(f.name, v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.add(f.name, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f.asn {
+			if v.Uint() > 1<<32-1 {
+				return fmt.Errorf("rpsl: attribute '%s' has an invalid AS number %d", f.name, v.Uint())
+			}
+
+			e.addASN(f.name, uint32(v.Uint()))
+			return nil
+		}
+
+		e.add(f.name, strconv.FormatUint(v.Uint(), 10))
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testCommon struct {
+	MntBy  []string `rpsl:"mnt-by,multiple"`
+	Source string   `rpsl:"source,mandatory"`
+}
+
+type testRoute struct {
+	Prefix   netip.Prefix   `rpsl:"route,mandatory"`
+	Descr    string         `rpsl:"descr"`
+	Origin   uint32         `rpsl:"origin,asn,mandatory"`
+	Holes    []netip.Prefix `rpsl:"holes,list"`
+	Size     int            `rpsl:"assignment-size"`
+	Created  time.Time      `rpsl:"created"`
+	Internal string         `rpsl:"-"`
+	testCommon
+}
+
+func TestUnmarshal(t *testing.T) {
+	obj, err := Parse("route:           192.0.2.0/24\n" +
+		"origin:          AS64496\n" +
+		"holes:           192.0.2.0/26, 192.0.2.64/26\n" +
+		"holes:           192.0.2.128/25\n" +
+		"mnt-by:          FOO-MNT\n" +
+		"mnt-by:          BAR-MNT\n" +
+		"assignment-size: 48\n" +
+		"created:         2024-01-02T03:04:05Z\n" +
+		"source:          TEST")
+	if err != nil {
+		t.Fatalf(`Parse => %v`, err)
+	}
+
+	var route testRoute
+	if err := Unmarshal(obj, &route); err != nil {
+		t.Fatalf(`Unmarshal => %v`, err)
+	}
+
+	want := testRoute{
+		Prefix: netip.MustParsePrefix("192.0.2.0/24"),
+		Origin: 64496,
+		Holes: []netip.Prefix{
+			netip.MustParsePrefix("192.0.2.0/26"),
+			netip.MustParsePrefix("192.0.2.64/26"),
+			netip.MustParsePrefix("192.0.2.128/25"),
+		},
+		Size:       48,
+		Created:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		testCommon: testCommon{MntBy: []string{"FOO-MNT", "BAR-MNT"}, Source: "TEST"},
+	}
+
+	if !reflect.DeepEqual(route, want) {
+		t.Errorf(`Unmarshal => %+v, want %+v`, route, want)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	route := testRoute{
+		Prefix:     netip.MustParsePrefix("192.0.2.0/24"),
+		Origin:     64496,
+		Holes:      []netip.Prefix{netip.MustParsePrefix("192.0.2.0/26"), netip.MustParsePrefix("192.0.2.64/26")},
+		Created:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Internal:   "ignored",
+		testCommon: testCommon{MntBy: []string{"FOO-MNT", "BAR-MNT"}, Source: "TEST"},
+	}
+
+	obj, err := Marshal(&route)
+	if err != nil {
+		t.Fatalf(`Marshal => %v`, err)
+	}
+
+	want := "route:192.0.2.0/24\n" +
+		"origin:AS64496\n" +
+		"holes:192.0.2.0/26, 192.0.2.64/26\n" +
+		"created:2024-01-02T03:04:05Z\n" +
+		"mnt-by:FOO-MNT\n" +
+		"mnt-by:BAR-MNT\n" +
+		"source:TEST"
+	if got := obj.String(); got != want {
+		t.Errorf(`Marshal => %q, want %q`, got, want)
+	}
+
+	route.Source = ""
+	if _, err := Marshal(route); err == nil {
+		t.Errorf(`Marshal => nil, want a mandatory attribute error`)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	obj, err := Parse("route:   192.0.2.0/24\n" +
+		"descr:   first\n" +
+		"descr:   second\n" +
+		"origin:  ASX\n" +
+		"assignment-size: many")
+	if err != nil {
+		t.Fatalf(`Parse => %v`, err)
+	}
+
+	var route testRoute
+	var errs ValidationErrors
+	if err := Unmarshal(obj, &route); !errors.As(err, &errs) || len(errs) != 4 {
+		t.Errorf(`Unmarshal => %v, want 4 errors`, err)
+	}
+
+	if route.Prefix != netip.MustParsePrefix("192.0.2.0/24") {
+		t.Errorf(`Unmarshal => prefix %v, want 192.0.2.0/24`, route.Prefix)
+	}
+
+	if err := Unmarshal(obj, route); err == nil {
+		t.Errorf(`Unmarshal(non-pointer) => nil, want an error`)
+	}
+
+	var unsupported struct {
+		Flag bool `rpsl:"flag"`
+	}
+
+	if err := Unmarshal(obj, &unsupported); err == nil {
+		t.Errorf(`Unmarshal(unsupported) => nil, want an error`)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package nrtm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go/internal/netctx"
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+// Store receives the operations of a mirror, in the order of their serials.
+type Store interface {
+	// Apply adds, replaces or deletes the object of an operation.
+	Apply(op *Operation) error
+}
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// Dial opens the connections to the server. When nil, a net.Dialer is used.
+	Dial func(ctx context.Context, network string, addr string) (net.Conn, error)
+	// Timeout bounds every mirror whose context has no deadline, except in keepalive mode. Zero means no timeout.
+	Timeout time.Duration
+	// Keepalive keeps the connection open (-k), so that new operations are applied as the server sends them, until
+	// the context is done or the server closes the connection.
+	Keepalive bool
+	// AllowGaps accepts serials increasing by more than one, as sent by some IRRd servers.
+	AllowGaps bool
+}
+
+// Client mirrors the sources of an NRTM server. It is safe for concurrent use.
+type Client struct {
+	addr string
+	opts ClientOptions
+}
+
+// NewClient returns a Client for the server at addr, in the "host:port" form.
+func NewClient(addr string) *Client {
+	return NewClientWithOptions(addr, ClientOptions{})
+}
+
+// NewClientWithOptions returns a Client for the server at addr, in the "host:port" form, with the given options.
+func NewClientWithOptions(addr string, opts ClientOptions) *Client {
+	if opts.Dial == nil {
+		var d net.Dialer
+		opts.Dial = d.DialContext
+	}
+
+	return &Client{addr: addr, opts: opts}
+}
+
+// Mirror requests the operations of source following serial, the serial of the last operation applied to the store,
+// and applies them to the store. It returns the serial of the last operation applied, also on error, from which the
+// next call resumes. Being up to date is not an error.
+func (c *Client) Mirror(ctx context.Context, source string, serial uint64, store Store) (uint64, error) {
+	if source == "" || strings.ContainsAny(source, " :\r\n") {
+		return serial, fmt.Errorf("nrtm: invalid source '%s'", source)
+	}
+
+	if _, ok := ctx.Deadline(); !ok && c.opts.Timeout > 0 && !c.opts.Keepalive {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	conn, err := c.opts.Dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return serial, err
+	}
+
+	defer conn.Close()
+	defer netctx.Watch(ctx, conn)()
+
+	query := fmt.Sprintf("-g %s:3:%d-LAST", source, serial+1)
+	if c.opts.Keepalive {
+		query = "-k " + query
+	}
+
+	if _, err := io.WriteString(conn, query+"\r\n"); err != nil {
+		return serial, netctx.Err(ctx, err)
+	}
+
+	stream := NewStreamWithOptions(conn, StreamOptions{
+		Serial:    serial,
+		Keepalive: c.opts.Keepalive,
+		AllowGaps: c.opts.AllowGaps,
+	})
+
+	for stream.Scan() {
+		op := stream.Operation()
+		if err := store.Apply(&op); err != nil {
+			return serial, fmt.Errorf("nrtm: applying serial %d: %w", op.Serial, err)
+		}
+
+		serial = op.Serial
+	}
+
+	if err := stream.Err(); err != nil {
+		if upToDate(err, serial) {
+			return serial, nil
+		}
+
+		return serial, netctx.Err(ctx, err)
+	}
+
+	return serial, ctx.Err()
+}
+
+// upToDate returns true if err reports that no serial follows serial, as in "invalid range: Not within 1-100".
+func upToDate(err error, serial uint64) bool {
+	var e *whois.Error
+	if !errors.As(err, &e) || !errors.Is(e, ErrInvalidRange) {
+		return false
+	}
+
+	fields := strings.Fields(e.Message)
+	if len(fields) == 0 {
+		return false
+	}
+
+	_, last, ok := strings.Cut(fields[len(fields)-1], "-")
+	n, err := strconv.ParseUint(last, 10, 64)
+	return ok && err == nil && serial >= n
+}
@@ -0,0 +1,100 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package nrtm
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testStore records the operations applied, failing at the serial fail.
+type testStore struct {
+	ops  []Operation
+	fail uint64
+}
+
+// Apply records the operation.
+func (s *testStore) Apply(op *Operation) error {
+	if op.Serial == s.fail {
+		return errors.New("store failure")
+	}
+
+	s.ops = append(s.ops, *op)
+	return nil
+}
+
+// newFakeServer starts a server streaming testStream, and returns its address.
+func newFakeServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf(`net.Listen => %v`, err)
+	}
+
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				var first uint64
+				if _, err := fmt.Sscanf(strings.TrimSpace(line), "-g TEST:3:%d-LAST", &first); err != nil {
+					io.WriteString(conn, "%ERROR:405: no flags passed\n")
+				} else if first != 100 {
+					io.WriteString(conn, "%ERROR:401: invalid range: Not within 100-102\n")
+				} else {
+					io.WriteString(conn, testStream)
+				}
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestMirror(t *testing.T) {
+	c := NewClientWithOptions(newFakeServer(t), ClientOptions{Timeout: 5 * time.Second})
+
+	store := &testStore{}
+	serial, err := c.Mirror(context.Background(), "TEST", 99, store)
+	if err != nil || serial != 102 {
+		t.Fatalf(`Mirror => %d, %v, want 102, <nil>`, serial, err)
+	}
+
+	want := []string{"ADD mntner", "DEL person", "ADD route"}
+	if got := summary(store.ops); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Mirror => %v, want %v`, got, want)
+	}
+
+	// Up to date.
+	if serial, err := c.Mirror(context.Background(), "TEST", 102, store); err != nil || serial != 102 {
+		t.Errorf(`Mirror => %d, %v, want 102, <nil>`, serial, err)
+	}
+
+	// History no longer available.
+	if _, err := c.Mirror(context.Background(), "TEST", 10, store); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf(`Mirror => %v, want %v`, err, ErrInvalidRange)
+	}
+
+	// Failures of the store stop the mirror at the last serial applied.
+	if serial, err := c.Mirror(context.Background(), "TEST", 99, &testStore{fail: 101}); err == nil || serial != 100 {
+		t.Errorf(`Mirror => %d, %v, want 100 and an error`, serial, err)
+	}
+}
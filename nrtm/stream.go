@@ -0,0 +1,413 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nrtm implements version 3 of the Near Real Time Mirroring protocol (NRTM) of the RIPE database and IRRd,
+// with a parser of NRTM streams and a client keeping a copy of a database current.
+//
+// Example:
+//
+//	c := nrtm.NewClient("whois.ripe.net:4444")
+//	serial, err := c.Mirror(ctx, "RIPE", lastSerial, store)
+//	if err != nil {
+//	    log.Fatalf("Failed to mirror: %v", err)
+//	}
+package nrtm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+// ErrInvalidRange is reported by the servers when the requested serials are not available.
+var ErrInvalidRange = &whois.Error{Code: 401, Message: "invalid range"}
+
+// ErrSerialGap is wrapped by the StreamError of an operation whose serial does not follow the previous one.
+var ErrSerialGap = errors.New("serial gap")
+
+// Kind is the kind of an Operation.
+type Kind uint8
+
+const (
+	// Add adds an object, or replaces the object with the same primary key.
+	Add Kind = iota + 1
+	// Delete deletes an object.
+	Delete
+)
+
+// String returns the keyword of the Kind in NRTM streams.
+func (k Kind) String() string {
+	switch k {
+	case Add:
+		return "ADD"
+	case Delete:
+		return "DEL"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+}
+
+// Operation is a change of an NRTM stream.
+type Operation struct {
+	Kind Kind
+	// Serial is the serial of the change in the source.
+	Serial uint64
+	// Object is the object added, or the object deleted.
+	Object rpsl.Object
+}
+
+// Header is the "%START" line of an NRTM stream, such as "%START Version: 3 RIPE 100-200".
+type Header struct {
+	Version int
+	Source  string
+	// First and Last are the serials of the stream. Last is zero for the keyword LAST.
+	First, Last uint64
+}
+
+// StreamError describes an invalid line of an NRTM stream, and can be retrieved with errors.As.
+type StreamError struct {
+	// Line is the one-based line number in the stream.
+	Line int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns a string representation of the StreamError.
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("nrtm: line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// StreamOptions controls how a Stream is parsed.
+type StreamOptions struct {
+	// Serial is the serial of the last operation already applied. When non-zero, the first operation must follow it.
+	Serial uint64
+	// Keepalive reads the stream of a persistent connection (-k), in which several blocks of operations follow each
+	// other until the connection is closed.
+	Keepalive bool
+	// AllowGaps accepts serials increasing by more than one, as sent by some IRRd servers.
+	AllowGaps bool
+}
+
+// Stream reads the operations of an NRTM stream one at a time, checking that their serials follow each other.
+//
+// Example:
+//
+//	stream := nrtm.NewStream(conn)
+//	for stream.Scan() {
+//	    op := stream.Operation()
+//	    fmt.Printf("%s %d %s\n", op.Kind, op.Serial, op.Object.Class())
+//	}
+//
+//	if err := stream.Err(); err != nil {
+//	    log.Fatalf("Failed to read NRTM stream: %v", err)
+//	}
+type Stream struct {
+	r      *bufio.Reader
+	opts   StreamOptions
+	line   int    // Number of lines read so far.
+	header Header // Header of the current block.
+	block  bool   // Whether a block was started and not ended.
+	serial uint64 // Serial of the last operation.
+	op     Operation
+	err    error
+	done   bool
+}
+
+// NewStream returns a Stream reading from r.
+func NewStream(r io.Reader) *Stream {
+	return NewStreamWithOptions(r, StreamOptions{})
+}
+
+// NewStreamWithOptions returns a Stream reading from r according to opts.
+func NewStreamWithOptions(r io.Reader, opts StreamOptions) *Stream {
+	return &Stream{r: bufio.NewReader(r), opts: opts, serial: opts.Serial}
+}
+
+// Scan advances the Stream to the next operation, which will then be available through the Operation method. It
+// returns false at the "%END" line, or at the end of the input in keepalive mode, and on errors. A stream ending
+// before its first "%START" line, as sent when there are no new operations, is not an error.
+func (s *Stream) Scan() bool {
+	if s.done {
+		return false
+	}
+
+	if err := s.next(); err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+
+		s.done = true
+		return false
+	}
+
+	return true
+}
+
+// Operation returns the most recent operation read by a call to Scan.
+func (s *Stream) Operation() Operation {
+	return s.op
+}
+
+// Header returns the "%START" line of the current block of operations.
+func (s *Stream) Header() Header {
+	return s.header
+}
+
+// Serial returns the serial of the last operation read, or the serial of the options if none was read.
+func (s *Stream) Serial() uint64 {
+	return s.serial
+}
+
+// Err returns the first error encountered by the Stream. Errors reported by the server are returned as *whois.Error,
+// and invalid lines as *StreamError.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// next reads the lines up to the next operation.
+func (s *Stream) next() error {
+	for {
+		line, err := s.readLine()
+		if errors.Is(err, io.EOF) && (!s.block || s.opts.Keepalive) {
+			return io.EOF
+		} else if err != nil {
+			return s.errorf("%w", unexpectedEOF(err))
+		}
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "%START"):
+			if err := s.start(line); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "%END"):
+			if !s.block {
+				return s.errorf("%%END without %%START")
+			}
+
+			if last := s.header.Last; last != 0 && s.serial != last && !s.opts.AllowGaps {
+				return s.errorf("%w: stream ended at serial %d, want %d", ErrSerialGap, s.serial, last)
+			}
+
+			s.block = false
+			if !s.opts.Keepalive {
+				return io.EOF
+			}
+		case strings.HasPrefix(line, "%ERROR:"):
+			return parseError(line)
+		case strings.HasPrefix(line, "%"):
+			continue
+		case strings.HasPrefix(line, "ADD") || strings.HasPrefix(line, "DEL"):
+			return s.operation(line)
+		default:
+			return s.errorf("unexpected line %q", line)
+		}
+	}
+}
+
+// start parses a "%START" line.
+func (s *Stream) start(line string) error {
+	fields := strings.Fields(line)
+	if s.block {
+		return s.errorf("%%START before %%END")
+	}
+
+	if len(fields) != 5 || fields[1] != "Version:" {
+		return s.errorf("invalid header %q", line)
+	}
+
+	version, err := strconv.Atoi(fields[2])
+	if err != nil || version != 1 && version != 3 {
+		return s.errorf("unsupported version '%s'", fields[2])
+	}
+
+	first, last, err := parseRange(fields[4])
+	if err != nil {
+		return s.errorf("%w", err)
+	}
+
+	if s.serial != 0 && !s.follows(first) {
+		return s.errorf("%w: stream starts at serial %d after serial %d", ErrSerialGap, first, s.serial)
+	}
+
+	s.header = Header{Version: version, Source: fields[3], First: first, Last: last}
+	s.block = true
+	return nil
+}
+
+// operation parses an operation line and the object following it.
+func (s *Stream) operation(line string) error {
+	if !s.block {
+		return s.errorf("operation outside of %%START and %%END")
+	}
+
+	fields := strings.Fields(line)
+	kind := Add
+	if fields[0] == "DEL" {
+		kind = Delete
+	} else if fields[0] != "ADD" {
+		return s.errorf("unexpected line %q", line)
+	}
+
+	var serial uint64
+	switch {
+	case len(fields) == 2:
+		n, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return s.errorf("invalid serial '%s'", fields[1])
+		}
+
+		serial = n
+	case len(fields) == 1 && s.header.Version == 1:
+		// Version 1 streams do not number the operations.
+		serial = max(s.serial+1, s.header.First)
+	default:
+		return s.errorf("invalid operation %q", line)
+	}
+
+	if s.serial == 0 && serial != s.header.First && !s.opts.AllowGaps || s.serial != 0 && !s.follows(serial) {
+		return s.errorf("%w: serial %d after serial %d", ErrSerialGap, serial, s.serial)
+	}
+
+	obj, err := s.object()
+	if err != nil {
+		return err
+	}
+
+	s.op = Operation{Kind: kind, Serial: serial, Object: *obj}
+	s.serial = serial
+	return nil
+}
+
+// follows returns true if serial may follow the last serial.
+func (s *Stream) follows(serial uint64) bool {
+	if s.opts.AllowGaps {
+		return serial > s.serial
+	}
+
+	return serial == s.serial+1
+}
+
+// object reads the object following an operation line, up to the next empty line.
+func (s *Stream) object() (*rpsl.Object, error) {
+	var text strings.Builder
+	start := 0
+	for {
+		line, err := s.readLine()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, s.errorf("%w", err)
+		}
+
+		if line != "" {
+			if start == 0 {
+				start = s.line
+			}
+
+			text.WriteString(line)
+			text.WriteByte('\n')
+		} else if start != 0 || err != nil {
+			break
+		}
+	}
+
+	if start == 0 {
+		return nil, s.errorf("missing object")
+	}
+
+	obj, err := rpsl.Parse(text.String())
+	if err != nil {
+		return nil, &StreamError{Line: start, Err: err}
+	}
+
+	return obj, nil
+}
+
+// readLine reads a line without its line ending.
+func (s *Stream) readLine() (string, error) {
+	line, err := s.r.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+
+	s.line++
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// errorf returns a StreamError for the last line read.
+func (s *Stream) errorf(format string, args ...any) error {
+	return &StreamError{Line: s.line, Err: fmt.Errorf(format, args...)}
+}
+
+// parseRange parses the serials of a header, such as "100-200" or "100-LAST".
+func parseRange(value string) (uint64, uint64, error) {
+	first, last, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid range '%s'", value)
+	}
+
+	f, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range '%s'", value)
+	}
+
+	if strings.EqualFold(last, "LAST") {
+		return f, 0, nil
+	}
+
+	l, err := strconv.ParseUint(last, 10, 64)
+	if err != nil || l < f {
+		return 0, 0, fmt.Errorf("invalid range '%s'", value)
+	}
+
+	return f, l, nil
+}
+
+// parseError parses a line of the form "%ERROR:401: invalid range: Not within 1-100".
+func parseError(line string) error {
+	code, msg, _ := strings.Cut(strings.TrimPrefix(line, "%ERROR:"), ":")
+	n, err := strconv.Atoi(strings.TrimSpace(code))
+	if err != nil {
+		return fmt.Errorf("nrtm: %s", strings.TrimPrefix(line, "%"))
+	}
+
+	return &whois.Error{Code: n, Message: strings.TrimSpace(msg)}
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, for streams cut short.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// Operations returns an iterator over the operations read from r. Iteration stops at the first error, which is
+// yielded together with a zero Operation.
+func Operations(r io.Reader, opts StreamOptions) iter.Seq2[Operation, error] {
+	return func(yield func(Operation, error) bool) {
+		stream := NewStreamWithOptions(r, opts)
+		for stream.Scan() {
+			if !yield(stream.Operation(), nil) {
+				return
+			}
+		}
+
+		if err := stream.Err(); err != nil {
+			yield(Operation{}, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package nrtm
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+const testStream = `%START Version: 3 TEST 100-102
+
+ADD 100
+
+mntner:         TEST-MNT
+source:         TEST
+
+DEL 101
+
+person:         John Doe
+nic-hdl:        JD1-TEST
+source:         TEST
+
+ADD 102
+
+route:          192.0.2.0/24
+origin:         AS64496
+source:         TEST
+
+%END TEST
+`
+
+// summary returns the kind and class of the operations.
+func summary(ops []Operation) []string {
+	var s []string
+	for _, op := range ops {
+		s = append(s, op.Kind.String()+" "+op.Object.Class())
+	}
+
+	return s
+}
+
+func TestStream(t *testing.T) {
+	stream := NewStream(strings.NewReader(testStream))
+	var ops []Operation
+	for stream.Scan() {
+		ops = append(ops, stream.Operation())
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf(`Err => %v`, err)
+	}
+
+	want := []string{"ADD mntner", "DEL person", "ADD route"}
+	if got := summary(ops); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Scan => %v, want %v`, got, want)
+	}
+
+	if got, want := stream.Header(), (Header{Version: 3, Source: "TEST", First: 100, Last: 102}); got != want {
+		t.Errorf(`Header => %+v, want %+v`, got, want)
+	}
+
+	if stream.Serial() != 102 {
+		t.Errorf(`Serial => %d, want 102`, stream.Serial())
+	}
+}
+
+func TestStreamKeepalive(t *testing.T) {
+	input := testStream + `
+%START Version: 3 TEST 103-103
+
+DEL 103
+
+route:          192.0.2.0/24
+origin:         AS64496
+source:         TEST
+
+%END TEST
+`
+
+	var ops []Operation
+	for op, err := range Operations(strings.NewReader(input), StreamOptions{Keepalive: true}) {
+		if err != nil {
+			t.Fatalf(`Operations => %v`, err)
+		}
+
+		ops = append(ops, op)
+	}
+
+	want := []string{"ADD mntner", "DEL person", "ADD route", "DEL route"}
+	if got := summary(ops); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Operations => %v, want %v`, got, want)
+	}
+}
+
+func TestStreamVersion1(t *testing.T) {
+	input := strings.NewReplacer("Version: 3", "Version: 1", "ADD 100", "ADD", "DEL 101", "DEL", "ADD 102", "ADD").Replace(testStream)
+	var serials []uint64
+	for op, err := range Operations(strings.NewReader(input), StreamOptions{}) {
+		if err != nil {
+			t.Fatalf(`Operations => %v`, err)
+		}
+
+		serials = append(serials, op.Serial)
+	}
+
+	if want := []uint64{100, 101, 102}; !reflect.DeepEqual(serials, want) {
+		t.Errorf(`Operations => %v, want %v`, serials, want)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  StreamOptions
+		want  error
+	}{
+		{strings.Replace(testStream, "DEL 101", "DEL 103", 1), StreamOptions{}, ErrSerialGap},
+		{testStream, StreamOptions{Serial: 90}, ErrSerialGap},
+		{strings.TrimSuffix(testStream, "%END TEST\n"), StreamOptions{}, io.ErrUnexpectedEOF},
+		{"%ERROR:401: invalid range: Not within 1-50\n", StreamOptions{}, ErrInvalidRange},
+		{"%START Version: 3 TEST 100-LAST\n\nUPD 100\n", StreamOptions{}, nil},
+	}
+
+	for _, tt := range tests {
+		stream := NewStreamWithOptions(strings.NewReader(tt.input), tt.opts)
+		for stream.Scan() {
+		}
+
+		err := stream.Err()
+		var streamErr *StreamError
+		var whoisErr *whois.Error
+		if tt.want == nil && !errors.As(err, &streamErr) || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf(`Err => %v, want %v`, err, tt.want)
+		}
+
+		if errors.Is(tt.want, ErrInvalidRange) && !errors.As(err, &whoisErr) {
+			t.Errorf(`Err => %T, want *whois.Error`, err)
+		}
+	}
+
+	// Gaps are accepted on request.
+	input := strings.NewReplacer("100-102", "100-107", "ADD 102", "ADD 107").Replace(testStream)
+	stream := NewStreamWithOptions(strings.NewReader(input), StreamOptions{AllowGaps: true})
+	for stream.Scan() {
+	}
+
+	if err := stream.Err(); err != nil || stream.Serial() != 107 {
+		t.Errorf(`Err => %v, serial %d, want <nil>, serial 107`, err, stream.Serial())
+	}
+
+	// No new operations.
+	stream = NewStream(strings.NewReader("% Warning: there are no newer updates available\n"))
+	if stream.Scan() || stream.Err() != nil {
+		t.Errorf(`Scan => %v, want false and no error`, stream.Err())
+	}
+}
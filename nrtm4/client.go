@@ -0,0 +1,430 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package nrtm4
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/nrtm"
+)
+
+// ErrResetRequired is returned by Sync when a snapshot must be applied to a store that already holds objects and does
+// not implement Resetter.
+var ErrResetRequired = errors.New("nrtm4: the store must be reset to apply a snapshot")
+
+// Resetter is implemented by the stores that can be emptied before a new snapshot is applied, when the session of the
+// server changed or the deltas following the state are no longer published.
+type Resetter interface {
+	// Reset deletes every object of the store.
+	Reset() error
+}
+
+// State is the position of a mirror in the files of a server. The zero value is the state of an empty store.
+type State struct {
+	SessionID string
+	Version   uint64
+}
+
+// MaxNotificationSize is the largest update notification file accepted by a Client, in bytes.
+const MaxNotificationSize = 16 << 20
+
+// DefaultMaxFileSize is the largest snapshot or delta file accepted by a Client, in bytes, unless
+// ClientOptions.MaxFileSize is set.
+const DefaultMaxFileSize = 4 << 30
+
+// maxRecordSize is the largest record of a snapshot or delta file, in bytes.
+const maxRecordSize = 16 << 20
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// HTTPClient fetches the files from a base URL. When nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// MaxFileSize is the largest snapshot or delta file accepted, in bytes, before decompression. Files are verified
+	// before they are applied: those downloaded from a base URL are first written to a temporary file. When zero,
+	// DefaultMaxFileSize is used.
+	MaxFileSize int64
+	// PublicKeys are the Ed25519 keys trusted to sign the update notification file, usually the current key of the
+	// server and, during a rotation, the next one.
+	PublicKeys []ed25519.PublicKey
+}
+
+// Client reads the NRTMv4 files of a source from a base URL or a local directory. It is safe for concurrent use.
+type Client struct {
+	base string
+	opts ClientOptions
+}
+
+// NewClient returns a Client reading the files at base, an HTTP(S) URL or a local directory, and verifying the update
+// notification file with key.
+func NewClient(base string, key ed25519.PublicKey) *Client {
+	return NewClientWithOptions(base, ClientOptions{PublicKeys: []ed25519.PublicKey{key}})
+}
+
+// NewClientWithOptions returns a Client reading the files at base, an HTTP(S) URL or a local directory, with the given
+// options.
+func NewClientWithOptions(base string, opts ClientOptions) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = DefaultMaxFileSize
+	}
+
+	return &Client{base: base, opts: opts}
+}
+
+// Notification fetches the update notification file, verifies its signature and checks its content.
+func (c *Client) Notification(ctx context.Context) (*Notification, error) {
+	data, err := c.fetch(ctx, NotificationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := verify(data, c.opts.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Notification{}
+	if err := json.Unmarshal(payload, n); err != nil {
+		return nil, fmt.Errorf("nrtm4: invalid update notification file: %w", err)
+	}
+
+	if err := n.check(); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// check returns an error if the update notification file is inconsistent.
+func (n *Notification) check() error {
+	switch {
+	case n.NRTMVersion != 4 || n.Type != "notification":
+		return fmt.Errorf("nrtm4: unsupported update notification file version %d, type '%s'", n.NRTMVersion, n.Type)
+	case n.SessionID == "" || n.Source == "":
+		return errors.New("nrtm4: update notification file without session or source")
+	case n.Snapshot.Version == 0 || n.Snapshot.Version > n.Version:
+		return fmt.Errorf("nrtm4: snapshot version %d inconsistent with version %d", n.Snapshot.Version, n.Version)
+	}
+
+	for i, delta := range n.Deltas {
+		if i > 0 && delta.Version != n.Deltas[i-1].Version+1 {
+			return fmt.Errorf("nrtm4: delta version %d does not follow version %d", delta.Version, n.Deltas[i-1].Version)
+		}
+	}
+
+	if len(n.Deltas) > 0 && n.Deltas[len(n.Deltas)-1].Version != n.Version {
+		return fmt.Errorf("nrtm4: last delta version %d is not version %d", n.Deltas[len(n.Deltas)-1].Version, n.Version)
+	}
+
+	// The deltas must lead from the snapshot to the version.
+	if _, ok := n.deltasAfter(n.Snapshot.Version); !ok {
+		return fmt.Errorf("nrtm4: no delta follows snapshot version %d", n.Snapshot.Version)
+	}
+
+	return nil
+}
+
+// Sync brings the store from state to the version of the update notification file. The deltas following state are
+// applied when they are published; otherwise the store is reset and the snapshot is applied, followed by the deltas.
+// It returns the new state, also on error, from which the next call resumes. If applying the snapshot fails, the
+// returned state is the zero State and the store must be emptied before the next call.
+func (c *Client) Sync(ctx context.Context, state State, store nrtm.Store) (State, error) {
+	n, err := c.Notification(ctx)
+	if err != nil {
+		return state, err
+	}
+
+	if state.SessionID == n.SessionID {
+		if state.Version > n.Version {
+			return state, fmt.Errorf("nrtm4: version %d of the store is ahead of version %d", state.Version, n.Version)
+		}
+
+		if state.Version == n.Version {
+			return state, nil
+		}
+
+		if deltas, ok := n.deltasAfter(state.Version); ok {
+			return c.applyDeltas(ctx, n, state, deltas, store)
+		}
+	}
+
+	if state.SessionID != "" {
+		r, ok := store.(Resetter)
+		if !ok {
+			return state, ErrResetRequired
+		}
+
+		if err := r.Reset(); err != nil {
+			return state, fmt.Errorf("nrtm4: resetting the store: %w", err)
+		}
+
+		state = State{}
+	}
+
+	if err := c.apply(ctx, n, n.Snapshot, "snapshot", store); err != nil {
+		return state, err
+	}
+
+	state = State{SessionID: n.SessionID, Version: n.Snapshot.Version}
+	deltas, ok := n.deltasAfter(state.Version)
+	if !ok {
+		return state, fmt.Errorf("nrtm4: no delta follows snapshot version %d", state.Version)
+	}
+
+	return c.applyDeltas(ctx, n, state, deltas, store)
+}
+
+// deltasAfter returns the deltas following version, and false if some of them are not published.
+func (n *Notification) deltasAfter(version uint64) ([]FileRef, bool) {
+	if version == n.Version {
+		return nil, true
+	}
+
+	for i, delta := range n.Deltas {
+		if delta.Version == version+1 {
+			return n.Deltas[i:], true
+		}
+	}
+
+	return nil, false
+}
+
+// applyDeltas applies the deltas in order, and returns the state after the last delta applied.
+func (c *Client) applyDeltas(ctx context.Context, n *Notification, state State, deltas []FileRef, store nrtm.Store) (State, error) {
+	for _, delta := range deltas {
+		if err := c.apply(ctx, n, delta, "delta", store); err != nil {
+			return state, err
+		}
+
+		state.Version = delta.Version
+	}
+
+	return state, nil
+}
+
+// apply fetches a snapshot or delta file, checks its hash and header, and applies its operations to the store. The
+// records are read one at a time, so that the whole file is never held in memory.
+func (c *Client) apply(ctx context.Context, n *Notification, ref FileRef, kind string, store nrtm.Store) error {
+	f, err := c.download(ctx, ref, kind)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	s, err := newSeqScanner(f)
+	if err != nil {
+		return err
+	}
+
+	header := false
+	for s.Scan() {
+		if !header {
+			var h fileHeader
+			if err := json.Unmarshal(s.Bytes(), &h); err != nil {
+				return fmt.Errorf("nrtm4: invalid %s header: %w", kind, err)
+			}
+
+			if h.NRTMVersion != 4 || h.Type != kind || h.Source != n.Source || h.SessionID != n.SessionID || h.Version != ref.Version {
+				return fmt.Errorf("nrtm4: %s header does not match the update notification file", kind)
+			}
+
+			header = true
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return fmt.Errorf("nrtm4: invalid %s record: %w", kind, err)
+		}
+
+		op, err := rec.operation(kind, ref.Version)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Apply(op); err != nil {
+			return fmt.Errorf("nrtm4: applying %s version %d: %w", kind, ref.Version, err)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("nrtm4: reading %s version %d: %w", kind, ref.Version, err)
+	}
+
+	if !header {
+		return fmt.Errorf("nrtm4: empty %s version %d", kind, ref.Version)
+	}
+
+	return nil
+}
+
+// tempFile is a temporary file, removed when closed.
+type tempFile struct {
+	*os.File
+}
+
+// Close closes and removes the file.
+func (f tempFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+	return err
+}
+
+// operation returns the operation of a record of a snapshot or delta file.
+func (rec *record) operation(kind string, version uint64) (*nrtm.Operation, error) {
+	switch {
+	case kind == "snapshot" && rec.Action == "", kind == "delta" && rec.Action == "add_modify":
+		obj, err := rpsl.Parse(rec.Object)
+		if err != nil {
+			return nil, fmt.Errorf("nrtm4: invalid object in %s version %d: %w", kind, version, err)
+		}
+
+		return &nrtm.Operation{Kind: nrtm.Add, Serial: version, Object: *obj}, nil
+	case kind == "delta" && rec.Action == "delete":
+		if rec.ObjectClass == "" || rec.PrimaryKey == "" {
+			return nil, fmt.Errorf("nrtm4: delete without class or primary key in delta version %d", version)
+		}
+
+		return &nrtm.Operation{Kind: nrtm.Delete, Serial: version, Object: keyObject(rec.ObjectClass, rec.PrimaryKey)}, nil
+	default:
+		return nil, fmt.Errorf("nrtm4: unexpected action '%s' in %s version %d", rec.Action, kind, version)
+	}
+}
+
+// fetch reads the update notification file.
+func (c *Client) fetch(ctx context.Context, ref string) ([]byte, error) {
+	r, err := c.open(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, MaxNotificationSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("nrtm4: %w", err)
+	}
+
+	if len(data) > MaxNotificationSize {
+		return nil, fmt.Errorf("nrtm4: %s exceeds %d bytes", ref, MaxNotificationSize)
+	}
+
+	return data, nil
+}
+
+// download opens a snapshot or delta file and checks its size and hash, returning it positioned at its start. The
+// files fetched from a base URL are written to a temporary file, removed when the returned file is closed.
+func (c *Client) download(ctx context.Context, ref FileRef, kind string) (_ io.ReadSeekCloser, err error) {
+	r, err := c.open(ctx, ref.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var f io.ReadSeekCloser
+	h := sha256.New()
+	w := io.Writer(h)
+	if local, ok := r.(*os.File); ok {
+		f = local
+	} else {
+		defer r.Close()
+		tmp, err := os.CreateTemp("", "nrtm4-*")
+		if err != nil {
+			return nil, fmt.Errorf("nrtm4: %w", err)
+		}
+
+		f = tempFile{tmp}
+		w = io.MultiWriter(tmp, h)
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+		}
+	}()
+
+	n, err := io.Copy(w, io.LimitReader(r, c.opts.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("nrtm4: %w", err)
+	}
+
+	if n > c.opts.MaxFileSize {
+		return nil, fmt.Errorf("nrtm4: %s version %d exceeds %d bytes", kind, ref.Version, c.opts.MaxFileSize)
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, ref.Hash) {
+		return nil, fmt.Errorf("nrtm4: hash mismatch, got %s, want %s in %s version %d", sum, ref.Hash, kind, ref.Version)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("nrtm4: %w", err)
+	}
+
+	return f, nil
+}
+
+// open opens a file referenced from the update notification file. From a local directory, only the last element of
+// the reference is used, and an *os.File is returned.
+func (c *Client) open(ctx context.Context, ref string) (io.ReadCloser, error) {
+	base, err := url.Parse(c.base)
+	if err != nil || base.Scheme != "http" && base.Scheme != "https" {
+		dir := c.base
+		if err == nil && base.Scheme == "file" {
+			dir = base.Path
+		}
+
+		name := ref
+		if u, err := url.Parse(ref); err == nil {
+			name = u.Path
+		}
+
+		f, err := os.Open(filepath.Join(dir, path.Base(name)))
+		if err != nil {
+			return nil, fmt.Errorf("nrtm4: %w", err)
+		}
+
+		return f, nil
+	}
+
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	u, err := base.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("nrtm4: invalid url '%s'", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("nrtm4: GET %s: %s", u, resp.Status)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,300 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package nrtm4
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/nrtm"
+)
+
+// GeneratorOptions configures a Generator.
+type GeneratorOptions struct {
+	// BaseURL prefixes the file names in the update notification file. When empty, the references are relative to the
+	// update notification file.
+	BaseURL string
+	// MaxDeltas is the number of deltas kept in the update notification file. The files of older deltas are removed.
+	// The deltas following the snapshot are always kept, since they bring it up to date: new snapshots must be
+	// published to bound their number. Zero keeps every delta.
+	MaxDeltas int
+	// NextSigningKey is the public key announced for a key rotation, if any.
+	NextSigningKey ed25519.PublicKey
+}
+
+// Generator publishes the NRTMv4 files of a source in a directory, to be served over HTTP. It is safe for concurrent
+// use.
+type Generator struct {
+	dir  string
+	key  ed25519.PrivateKey
+	opts GeneratorOptions
+
+	mu sync.Mutex
+	n  Notification
+}
+
+// NewGenerator returns a Generator publishing the files of source in dir, signed with key. If dir holds an update
+// notification file of the source signed with key, its session continues; otherwise a new session starts with the
+// first call to Snapshot.
+func NewGenerator(dir string, source string, key ed25519.PrivateKey) (*Generator, error) {
+	return NewGeneratorWithOptions(dir, source, key, GeneratorOptions{})
+}
+
+// NewGeneratorWithOptions returns a Generator publishing the files of source in dir, signed with key, with the given
+// options.
+func NewGeneratorWithOptions(dir string, source string, key ed25519.PrivateKey, opts GeneratorOptions) (*Generator, error) {
+	g := &Generator{dir: dir, key: key, opts: opts, n: Notification{Source: source}}
+
+	data, err := os.ReadFile(filepath.Join(dir, NotificationFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return g, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("nrtm4: %w", err)
+	}
+
+	payload, err := verify(data, []ed25519.PublicKey{key.Public().(ed25519.PublicKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	var n Notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return nil, fmt.Errorf("nrtm4: invalid update notification file: %w", err)
+	}
+
+	if n.Source == source {
+		g.n = n
+	}
+
+	return g, nil
+}
+
+// Notification returns the content of the last update notification file published.
+func (g *Generator) Notification() Notification {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.n
+}
+
+// Snapshot publishes a snapshot of the objects at the current version, or at version 1 of a new session.
+func (g *Generator) Snapshot(objs []rpsl.Object) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	n := g.n
+	if n.SessionID == "" {
+		n.SessionID = newSessionID()
+		n.Version = 1
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := writeSeq(zw, g.header(&n, "snapshot")); err != nil {
+		return err
+	}
+
+	for i := range objs {
+		if err := writeSeq(zw, record{Object: format(&objs[i])}); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	ref, err := g.writeFile(fmt.Sprintf("nrtm-snapshot.%d.%s.json.gz", n.Version, randomHex(8)), buf.Bytes(), n.Version)
+	if err != nil {
+		return err
+	}
+
+	old := n.Snapshot
+	n.Snapshot = ref
+	pruned := g.prune(&n)
+	if err := g.publish(n); err != nil {
+		return err
+	}
+
+	g.remove(pruned)
+	if old.URL != "" {
+		g.remove([]FileRef{old})
+	}
+
+	return nil
+}
+
+// Delta publishes the operations as a delta at the next version. A snapshot must have been published first.
+func (g *Generator) Delta(ops []nrtm.Operation) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.n.SessionID == "" {
+		return errors.New("nrtm4: no snapshot published")
+	}
+
+	n := g.n
+	n.Version++
+	n.Deltas = append([]FileRef(nil), n.Deltas...)
+
+	var buf bytes.Buffer
+	if err := writeSeq(&buf, g.header(&n, "delta")); err != nil {
+		return err
+	}
+
+	for i := range ops {
+		rec := record{Action: "add_modify", Object: format(&ops[i].Object)}
+		if ops[i].Kind == nrtm.Delete {
+			rec = record{Action: "delete", ObjectClass: ops[i].Object.Class(), PrimaryKey: ops[i].Object.PrimaryKey()}
+		}
+
+		if err := writeSeq(&buf, rec); err != nil {
+			return err
+		}
+	}
+
+	ref, err := g.writeFile(fmt.Sprintf("nrtm-delta.%d.%s.json", n.Version, randomHex(8)), buf.Bytes(), n.Version)
+	if err != nil {
+		return err
+	}
+
+	n.Deltas = append(n.Deltas, ref)
+	pruned := g.prune(&n)
+	if err := g.publish(n); err != nil {
+		return err
+	}
+
+	g.remove(pruned)
+	return nil
+}
+
+// prune removes the oldest deltas beyond MaxDeltas from the notification and returns them. The deltas following the
+// snapshot are kept.
+func (g *Generator) prune(n *Notification) []FileRef {
+	if g.opts.MaxDeltas <= 0 {
+		return nil
+	}
+
+	k := len(n.Deltas) - g.opts.MaxDeltas
+	if i := slices.IndexFunc(n.Deltas, func(ref FileRef) bool { return ref.Version > n.Snapshot.Version }); i >= 0 {
+		k = min(k, i)
+	}
+
+	if k <= 0 {
+		return nil
+	}
+
+	pruned := n.Deltas[:k]
+	n.Deltas = n.Deltas[k:]
+	return pruned
+}
+
+// remove removes the files of the snapshots or deltas, once they are no longer referenced.
+func (g *Generator) remove(refs []FileRef) {
+	for _, ref := range refs {
+		_ = os.Remove(filepath.Join(g.dir, filepath.Base(ref.URL)))
+	}
+}
+
+// header returns the header of a snapshot or delta file at the version of the notification.
+func (g *Generator) header(n *Notification, kind string) fileHeader {
+	return fileHeader{NRTMVersion: 4, Type: kind, Source: n.Source, SessionID: n.SessionID, Version: n.Version}
+}
+
+// writeFile writes a snapshot or delta file and returns its reference.
+func (g *Generator) writeFile(name string, data []byte, version uint64) (FileRef, error) {
+	if err := writeAtomic(filepath.Join(g.dir, name), data); err != nil {
+		return FileRef{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	return FileRef{Version: version, URL: g.opts.BaseURL + name, Hash: hex.EncodeToString(sum[:])}, nil
+}
+
+// publish signs and writes the update notification file, and makes it the current one.
+func (g *Generator) publish(n Notification) error {
+	n.NRTMVersion = 4
+	n.Type = "notification"
+	n.Timestamp = time.Now().UTC().Truncate(time.Second)
+	n.NextSigningKey = ""
+	if g.opts.NextSigningKey != nil {
+		n.NextSigningKey = encodeKey(g.opts.NextSigningKey)
+	}
+
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	if err := writeAtomic(filepath.Join(g.dir, NotificationFile), sign(payload, g.key)); err != nil {
+		return err
+	}
+
+	g.n = n
+	return nil
+}
+
+// writeAtomic writes a file through a temporary file, so that readers never see a partial file.
+func writeAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".nrtm4-*")
+	if err != nil {
+		return fmt.Errorf("nrtm4: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("nrtm4: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("nrtm4: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("nrtm4: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("nrtm4: %w", err)
+	}
+
+	return nil
+}
+
+// format returns the RPSL text of an object.
+func format(o *rpsl.Object) string {
+	f := rpsl.Formatter{}
+	return f.Format(o)
+}
+
+// newSessionID returns a random UUID (RFC 9562, version 4).
+func newSessionID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// randomHex returns n random bytes in hexadecimal, making the file names unpredictable.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
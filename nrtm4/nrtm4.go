@@ -0,0 +1,223 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nrtm4 implements version 4 of the Near Real Time Mirroring protocol (NRTMv4), in which a server publishes a
+// signed update notification file pointing to a snapshot file and to delta files. It provides a client applying these
+// files to an nrtm.Store, and a generator publishing them.
+//
+// Example:
+//
+//	c := nrtm4.NewClient("https://nrtm.example.net/EXAMPLE/", publicKey)
+//	state, err := c.Sync(ctx, state, store)
+//	if err != nil {
+//	    log.Fatalf("Failed to mirror: %v", err)
+//	}
+package nrtm4
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// NotificationFile is the name of the update notification file, relative to the base URL or directory of a source.
+const NotificationFile = "update-notification-file.jose"
+
+// recordSeparator starts every record of a JSON text sequence (RFC 7464).
+const recordSeparator = 0x1e
+
+// ErrSignature is returned when the signature of the update notification file cannot be verified.
+var ErrSignature = errors.New("nrtm4: invalid signature")
+
+// Notification is the content of the update notification file.
+type Notification struct {
+	NRTMVersion int       `json:"nrtm_version"`
+	Timestamp   time.Time `json:"timestamp"`
+	Type        string    `json:"type"`
+	Source      string    `json:"source"`
+	SessionID   string    `json:"session_id"`
+	// Version is the version of the last delta, or of the snapshot if there is no delta.
+	Version uint64 `json:"version"`
+	// NextSigningKey is the base64 public key that will sign the next notification files, during a key rotation.
+	NextSigningKey string    `json:"next_signing_key,omitempty"`
+	Snapshot       FileRef   `json:"snapshot"`
+	Deltas         []FileRef `json:"deltas,omitempty"`
+}
+
+// FileRef references a snapshot or delta file from the update notification file.
+type FileRef struct {
+	Version uint64 `json:"version"`
+	// URL is the location of the file, absolute or relative to the update notification file.
+	URL string `json:"url"`
+	// Hash is the hexadecimal SHA-256 digest of the file.
+	Hash string `json:"hash"`
+}
+
+// fileHeader is the first record of the snapshot and delta files.
+type fileHeader struct {
+	NRTMVersion int    `json:"nrtm_version"`
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+	SessionID   string `json:"session_id"`
+	Version     uint64 `json:"version"`
+}
+
+// record is a record of the snapshot and delta files, following the header.
+type record struct {
+	// Action is "add_modify" or "delete" in delta files, and empty in snapshot files.
+	Action      string `json:"action,omitempty"`
+	Object      string `json:"object,omitempty"`
+	ObjectClass string `json:"object_class,omitempty"`
+	PrimaryKey  string `json:"primary_key,omitempty"`
+}
+
+// jwsHeader is the protected header of the signed update notification file.
+type jwsHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+// sign returns the compact JSON Web Signature (RFC 7515) of the payload, with the EdDSA algorithm (RFC 8037).
+func sign(payload []byte, key ed25519.PrivateKey) []byte {
+	header, _ := json.Marshal(jwsHeader{Algorithm: "EdDSA"})
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key, []byte(input))
+	return []byte(input + "." + base64.RawURLEncoding.EncodeToString(signature))
+}
+
+// verify checks the compact JSON Web Signature of the update notification file with any of the keys, and returns its
+// payload.
+func verify(jws []byte, keys []ed25519.PublicKey) ([]byte, error) {
+	parts := strings.Split(strings.TrimSpace(string(jws)), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a compact JWS", ErrSignature)
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid header", ErrSignature)
+	}
+
+	var h jwsHeader
+	if err := json.Unmarshal(header, &h); err != nil || h.Algorithm != "EdDSA" {
+		return nil, fmt.Errorf("%w: unsupported algorithm", ErrSignature)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid encoding", ErrSignature)
+	}
+
+	input := []byte(parts[0] + "." + parts[1])
+	for _, key := range keys {
+		if ed25519.Verify(key, input, signature) {
+			return base64.RawURLEncoding.DecodeString(parts[1])
+		}
+	}
+
+	return nil, ErrSignature
+}
+
+// ParsePublicKey parses an Ed25519 public key encoded in base64, as published by the servers and in the
+// next_signing_key field of the update notification file.
+func ParsePublicKey(value string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("nrtm4: invalid public key '%s'", value)
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// encodeKey encodes an Ed25519 public key in base64, as parsed by ParsePublicKey.
+func encodeKey(key ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// newSeqScanner returns a scanner of the records of a JSON text sequence, decompressing it if it is gzipped.
+func newSeqScanner(r io.Reader) (*bufio.Scanner, error) {
+	br := bufio.NewReader(r)
+	src := io.Reader(br)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("nrtm4: %w", err)
+		}
+
+		src = zr
+	}
+
+	s := bufio.NewScanner(src)
+	s.Buffer(nil, maxRecordSize)
+	s.Split(splitSeq)
+	return s, nil
+}
+
+// splitSeq is a bufio.SplitFunc returning the non-empty records of a JSON text sequence, without their separator and
+// surrounding whitespace.
+func splitSeq(data []byte, atEOF bool) (int, []byte, error) {
+	start := 0
+	if len(data) > 0 && data[0] == recordSeparator {
+		start = 1
+	}
+
+	// The record ends at the next separator, or at the end of the input.
+	end := bytes.IndexByte(data[start:], recordSeparator)
+	switch {
+	case end >= 0:
+		end += start
+	case atEOF:
+		end = len(data)
+	default:
+		return 0, nil, nil
+	}
+
+	if rec := bytes.TrimSpace(data[start:end]); len(rec) > 0 {
+		return end, rec, nil
+	}
+
+	return end, nil, nil
+}
+
+// writeSeq writes a record of a JSON text sequence.
+func writeSeq(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(append([]byte{recordSeparator}, data...), '\n'))
+	return err
+}
+
+// keyObject returns an object holding the primary key attributes of a deleted object. The composite key of the route
+// and route6 classes is split into the prefix and the origin. When the key is not the class attribute, as for the
+// nic-hdl of person objects, the class attribute is left empty.
+func keyObject(class string, key string) rpsl.Object {
+	class = strings.ToLower(class)
+	if class == "route" || class == "route6" {
+		if i := strings.LastIndex(strings.ToUpper(key), "AS"); i > 0 {
+			return rpsl.Object{Attributes: []rpsl.Attribute{
+				{Name: class, Value: key[:i]},
+				{Name: "origin", Value: key[i:]},
+			}}
+		}
+	}
+
+	if t := rpsl.LookupTemplate(class); t != nil {
+		if keys := t.Keys(rpsl.PrimaryKey); len(keys) == 1 && keys[0] != class {
+			return rpsl.Object{Attributes: []rpsl.Attribute{{Name: class}, {Name: keys[0], Value: key}}}
+		}
+	}
+
+	return rpsl.Object{Attributes: []rpsl.Attribute{{Name: class, Value: key}}}
+}
@@ -0,0 +1,312 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package nrtm4
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/nrtm"
+)
+
+// testStore holds the objects by class and primary key.
+type testStore struct {
+	objs   map[string]rpsl.Object
+	resets int
+}
+
+// Apply adds or deletes the object of the operation.
+func (s *testStore) Apply(op *nrtm.Operation) error {
+	if s.objs == nil {
+		s.objs = make(map[string]rpsl.Object)
+	}
+
+	key := op.Object.Class() + ":" + op.Object.PrimaryKey()
+	if op.Kind == nrtm.Delete {
+		delete(s.objs, key)
+	} else {
+		s.objs[key] = op.Object
+	}
+
+	return nil
+}
+
+// Reset deletes every object.
+func (s *testStore) Reset() error {
+	s.objs = nil
+	s.resets++
+	return nil
+}
+
+// keys returns the sorted keys of the objects.
+func (s *testStore) keys() []string {
+	var keys []string
+	for key := range s.objs {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// mustParse parses an object or fails the test.
+func mustParse(t *testing.T, text string) rpsl.Object {
+	obj, err := rpsl.Parse(text)
+	if err != nil {
+		t.Fatalf(`Parse => %v`, err)
+	}
+
+	return *obj
+}
+
+func TestSync(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	g, err := NewGeneratorWithOptions(dir, "TEST", key, GeneratorOptions{MaxDeltas: 2})
+	if err != nil {
+		t.Fatalf(`NewGenerator => %v`, err)
+	}
+
+	route := mustParse(t, "route: 192.0.2.0/24\norigin: AS64496\nsource: TEST\n")
+	person := mustParse(t, "person: John Doe\nnic-hdl: JD1-TEST\nsource: TEST\n")
+	if err := g.Snapshot([]rpsl.Object{route, person}); err != nil {
+		t.Fatalf(`Snapshot => %v`, err)
+	}
+
+	c := NewClient(dir, pub)
+	store := &testStore{}
+	state, err := c.Sync(context.Background(), State{}, store)
+	if err != nil || state.Version != 1 {
+		t.Fatalf(`Sync => %+v, %v, want version 1`, state, err)
+	}
+
+	want := []string{"person:JD1-TEST", "route:192.0.2.0/24AS64496"}
+	if got := store.keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Sync => %v, want %v`, got, want)
+	}
+
+	// Deltas are applied in order.
+	mntner := mustParse(t, "mntner: TEST-MNT\nsource: TEST\n")
+	if err := g.Delta([]nrtm.Operation{{Kind: nrtm.Add, Object: mntner}}); err != nil {
+		t.Fatalf(`Delta => %v`, err)
+	}
+
+	if err := g.Delta([]nrtm.Operation{{Kind: nrtm.Delete, Object: route}, {Kind: nrtm.Delete, Object: person}}); err != nil {
+		t.Fatalf(`Delta => %v`, err)
+	}
+
+	state, err = c.Sync(context.Background(), state, store)
+	if err != nil || state.Version != 3 {
+		t.Fatalf(`Sync => %+v, %v, want version 3`, state, err)
+	}
+
+	if got := store.keys(); !reflect.DeepEqual(got, []string{"mntner:TEST-MNT"}) {
+		t.Errorf(`Sync => %v, want [mntner:TEST-MNT]`, got)
+	}
+
+	// The deltas following version 1 are no longer published, the store is reset and the snapshot applied.
+	if err := g.Delta([]nrtm.Operation{{Kind: nrtm.Add, Object: route}}); err != nil {
+		t.Fatalf(`Delta => %v`, err)
+	}
+
+	if err := g.Snapshot([]rpsl.Object{mntner, route}); err != nil {
+		t.Fatalf(`Snapshot => %v`, err)
+	}
+
+	state, err = c.Sync(context.Background(), State{SessionID: state.SessionID, Version: 1}, store)
+	if err != nil || state.Version != 4 || store.resets != 1 {
+		t.Fatalf(`Sync => %+v, %v, %d resets, want version 4 after a reset`, state, err, store.resets)
+	}
+
+	want = []string{"mntner:TEST-MNT", "route:192.0.2.0/24AS64496"}
+	if got := store.keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Sync => %v, want %v`, got, want)
+	}
+
+	// The session continues after a restart, and the files are served over HTTP.
+	g, err = NewGenerator(dir, "TEST", key)
+	if err != nil || g.Notification().Version != 4 || g.Notification().SessionID != state.SessionID {
+		t.Fatalf(`NewGenerator => %+v, %v, want the session at version 4`, g.Notification(), err)
+	}
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer srv.Close()
+	state, err = NewClient(srv.URL, pub).Sync(context.Background(), State{}, &testStore{})
+	if err != nil || state.Version != 4 {
+		t.Errorf(`Sync => %+v, %v, want version 4`, state, err)
+	}
+}
+
+func TestSyncPruned(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	g, err := NewGeneratorWithOptions(dir, "TEST", key, GeneratorOptions{MaxDeltas: 2})
+	if err != nil {
+		t.Fatalf(`NewGenerator => %v`, err)
+	}
+
+	if err := g.Snapshot(nil); err != nil {
+		t.Fatalf(`Snapshot => %v`, err)
+	}
+
+	var objs []rpsl.Object
+	for i := range 3 {
+		obj := mustParse(t, fmt.Sprintf("aut-num: AS%d\nsource: TEST\n", 64496+i))
+		objs = append(objs, obj)
+		if err := g.Delta([]nrtm.Operation{{Kind: nrtm.Add, Object: obj}}); err != nil {
+			t.Fatalf(`Delta => %v`, err)
+		}
+	}
+
+	// The deltas following the snapshot are kept beyond MaxDeltas.
+	if n := g.Notification(); len(n.Deltas) != 3 {
+		t.Errorf(`Notification => %d deltas, want 3`, len(n.Deltas))
+	}
+
+	store := &testStore{}
+	state, err := NewClient(dir, pub).Sync(context.Background(), State{}, store)
+	if err != nil || state.Version != 4 || len(store.keys()) != 3 {
+		t.Fatalf(`Sync => %+v, %v, %v, want version 4`, state, err, store.keys())
+	}
+
+	// A new snapshot lets the older deltas be pruned, and their files removed.
+	if err := g.Snapshot(objs); err != nil {
+		t.Fatalf(`Snapshot => %v`, err)
+	}
+
+	n := g.Notification()
+	if len(n.Deltas) != 2 || n.Deltas[0].Version != 3 {
+		t.Errorf(`Notification => %+v, want deltas 3 and 4`, n.Deltas)
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(dir, "nrtm-delta.*")); len(files) != 2 {
+		t.Errorf(`Glob => %v, want 2 delta files`, files)
+	}
+
+	state, err = NewClient(dir, pub).Sync(context.Background(), State{}, &testStore{})
+	if err != nil || state.Version != 4 {
+		t.Errorf(`Sync => %+v, %v, want version 4`, state, err)
+	}
+}
+
+func TestSyncErrors(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	other, _, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	g, err := NewGenerator(dir, "TEST", key)
+	if err != nil {
+		t.Fatalf(`NewGenerator => %v`, err)
+	}
+
+	if err := g.Snapshot([]rpsl.Object{mustParse(t, "mntner: TEST-MNT\nsource: TEST\n")}); err != nil {
+		t.Fatalf(`Snapshot => %v`, err)
+	}
+
+	if _, err := NewClient(dir, other).Sync(context.Background(), State{}, &testStore{}); !errors.Is(err, ErrSignature) {
+		t.Errorf(`Sync => %v, want %v`, err, ErrSignature)
+	}
+
+	// A store that cannot be reset cannot switch to a new session.
+	var store struct{ nrtm.Store }
+	store.Store = &testStore{}
+	if _, err := NewClient(dir, pub).Sync(context.Background(), State{SessionID: "old", Version: 7}, store); !errors.Is(err, ErrResetRequired) {
+		t.Errorf(`Sync => %v, want %v`, err, ErrResetRequired)
+	}
+
+	// Files larger than MaxFileSize are rejected.
+	c := NewClientWithOptions(dir, ClientOptions{PublicKeys: []ed25519.PublicKey{pub}, MaxFileSize: 16})
+	if _, err := c.Sync(context.Background(), State{}, &testStore{}); err == nil || !strings.Contains(err.Error(), "exceeds 16 bytes") {
+		t.Errorf(`Sync => %v, want a size error`, err)
+	}
+
+	// Tampered files are rejected.
+	snapshot := filepath.Join(dir, filepath.Base(g.Notification().Snapshot.URL))
+	if err := os.WriteFile(snapshot, []byte("tampered"), 0o644); err != nil {
+		t.Fatalf(`WriteFile => %v`, err)
+	}
+
+	if _, err := NewClient(dir, pub).Sync(context.Background(), State{}, &testStore{}); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Errorf(`Sync => %v, want a hash mismatch`, err)
+	}
+}
+
+func TestNotificationCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot uint64
+		deltas   []uint64
+		version  uint64
+		valid    bool
+	}{
+		{"SnapshotOnly", 3, nil, 3, true},
+		{"Deltas", 3, []uint64{4, 5}, 5, true},
+		{"OlderDeltas", 3, []uint64{2, 3, 4}, 4, true},
+		{"NoDelta", 3, nil, 5, false},
+		{"Gap", 3, []uint64{5, 6}, 6, false},
+		{"NotConsecutive", 3, []uint64{4, 6}, 6, false},
+		{"LastDelta", 3, []uint64{4, 5}, 6, false},
+		{"SnapshotAhead", 6, []uint64{4, 5}, 5, false},
+	}
+
+	for _, tt := range tests {
+		n := &Notification{NRTMVersion: 4, Type: "notification", Source: "TEST", SessionID: "session", Version: tt.version}
+		n.Snapshot.Version = tt.snapshot
+		for _, version := range tt.deltas {
+			n.Deltas = append(n.Deltas, FileRef{Version: version})
+		}
+
+		if err := n.check(); (err == nil) != tt.valid {
+			t.Errorf(`%s: Notification.check => %v, want valid %v`, tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestSplitSeq(t *testing.T) {
+	s := bufio.NewScanner(strings.NewReader("\x1e{\"a\":1}\n\x1e \n\x1e{\"b\":\n2}\n\x1e"))
+	s.Buffer(make([]byte, 4), 64)
+	s.Split(splitSeq)
+
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+
+	if want := []string{`{"a":1}`, "{\"b\":\n2}"}; s.Err() != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf(`splitSeq => %q, %v, want %q`, got, s.Err(), want)
+	}
+}
+
+func TestKeyObject(t *testing.T) {
+	tests := []struct {
+		class, key string
+		want       string
+	}{
+		{"route", "192.0.2.0/24AS64496", "route:192.0.2.0/24\norigin:AS64496"},
+		{"person", "JD1-TEST", "person:\nnic-hdl:JD1-TEST"},
+		{"mntner", "TEST-MNT", "mntner:TEST-MNT"},
+	}
+
+	for _, tt := range tests {
+		obj := keyObject(tt.class, tt.key)
+		if got := obj.String(); got != tt.want {
+			t.Errorf(`keyObject(%s, %s) => %q, want %q`, tt.class, tt.key, got, tt.want)
+		}
+
+		if got := obj.PrimaryKey(); got != tt.key {
+			t.Errorf(`primaryKey(keyObject(%s, %s)) => %s`, tt.class, tt.key, got)
+		}
+	}
+}
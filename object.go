@@ -4,16 +4,18 @@
 package rpsl
 
 import (
-	"bufio"
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 )
 
 type Object struct {
 	Attributes []Attribute
+
+	// Raw is the original text surrounding the attributes. It is only set in lossless mode.
+	Raw *RawObject
 }
 
 // Keys returns a slice of unique keys present in the Object. If a key appears multiple times in the Object, it will
@@ -33,6 +35,16 @@ func (o *Object) Keys() []string {
 	return keyList
 }
 
+// Class returns the class of the Object, which is the name of its first attribute. If the Object has no attributes, an
+// empty string will be returned.
+func (o *Object) Class() string {
+	if len(o.Attributes) == 0 {
+		return ""
+	}
+
+	return o.Attributes[0].Name
+}
+
 // Len returns the number of attributes in the Object.
 func (o *Object) Len() int {
 	return len(o.Attributes)
@@ -90,6 +102,29 @@ func (o *Object) Exists(key string) bool {
 	return false
 }
 
+// PrimaryKey returns the primary key of the Object, the concatenation of the values of the primary key attributes of
+// its class, such as "192.0.2.0/24AS64496" for a route. For classes without a template, the value of the class
+// attribute is returned.
+func (o *Object) PrimaryKey() string {
+	t := LookupTemplate(o.Class())
+	if t == nil {
+		if len(o.Attributes) == 0 {
+			return ""
+		}
+
+		return o.Attributes[0].Value
+	}
+
+	var key strings.Builder
+	for _, name := range t.Keys(PrimaryKey) {
+		if value := o.GetFirst(name); value != nil {
+			key.WriteString(*value)
+		}
+	}
+
+	return key.String()
+}
+
 // String returns a string representation of the Object.
 func (o *Object) String() string {
 	// Compute the exact capacity required.
@@ -181,67 +216,131 @@ func (o *Object) EnsureOne(key string) error {
 	return nil
 }
 
-func parseObjects(r io.Reader) ([]Object, error) {
-	// Start with a small capacity that will grow if needed.
-	objects := make([]Object, 0, 4)
-	currentObject := bytes.NewBuffer(make([]byte, 0, 512))
+// Set sets the value of the first attribute with a given key and removes the other ones. If the key is not present in
+// the Object, the attribute is added as by Add.
+func (o *Object) Set(key string, value string) {
+	key = strings.ToLower(key)
+	i := slices.IndexFunc(o.Attributes, func(attr Attribute) bool { return attr.Name == key })
+	if i < 0 {
+		o.Add(key, value)
+		return
+	}
 
-	// Pre-allocate a buffer for the scanner, but increase max size
-	scanner := bufio.NewScanner(r)
-	buf := make([]byte, 0, 512)      // Smaller initial allocation.
-	scanner.Buffer(buf, 4*1024*1024) // Allow large lines.
+	o.Attributes[i].Value = value
+	o.Attributes = slices.Concat(o.Attributes[:i+1], slices.DeleteFunc(o.Attributes[i+1:], func(attr Attribute) bool {
+		return attr.Name == key
+	}))
+}
 
-	// Process the file line by line.
-	for scanner.Scan() {
-		line := scanner.Bytes()
+// Add adds an attribute with a given key and value, following the order conventions of the RIPE database: after the
+// last attribute with the same key, such as a new mnt-by after the existing ones, or else before the source attribute
+// ending the Object.
+func (o *Object) Add(key string, value string) {
+	key = strings.ToLower(key)
+	attr := Attribute{Name: key, Value: value}
 
-		// Skip comment lines.
-		if len(line) > 0 && (line[0] == '%' || line[0] == '#') {
-			continue
-		}
+	if i := o.lastIndex(key); i >= 0 {
+		o.Attributes = slices.Insert(o.Attributes, i+1, attr)
+		return
+	}
 
-		// Handle empty lines.
-		if len(line) == 0 {
-			if currentObject.Len() > 0 {
-				attributes, err := parseAttributes(currentObject.Bytes())
-				if err != nil {
-					return nil, err
-				}
+	if n := len(o.Attributes); n >= 1 && o.Attributes[n-1].Name == "source" {
+		o.Attributes = slices.Insert(o.Attributes, n-1, attr)
+		return
+	}
 
-				if len(attributes) > 0 {
-					objects = append(objects, Object{Attributes: attributes})
-				}
+	o.Attributes = append(o.Attributes, attr)
+}
 
-				// Reset for the next object.
-				currentObject.Reset()
-			}
+// InsertAfter inserts attributes after the last attribute with a given key, or returns an error if the key is not
+// present in the Object.
+func (o *Object) InsertAfter(key string, attrs ...Attribute) error {
+	i := o.lastIndex(key)
+	if i < 0 {
+		return fmt.Errorf("attribute '%s' not found", strings.ToLower(key))
+	}
 
-			continue
-		}
+	attrs = slices.Clone(attrs)
+	for j := range attrs {
+		attrs[j].Name = strings.ToLower(attrs[j].Name)
+	}
 
-		// Add the line to the current object.
-		if currentObject.Len() > 0 {
-			currentObject.WriteByte('\n')
-		}
+	o.Attributes = slices.Insert(o.Attributes, i+1, attrs...)
+	return nil
+}
 
-		currentObject.Write(line)
-	}
+// RemoveAll removes every attribute with a given key and returns the number of attributes removed.
+func (o *Object) RemoveAll(key string) int {
+	key = strings.ToLower(key)
+	n := len(o.Attributes)
+	o.Attributes = slices.DeleteFunc(o.Attributes, func(attr Attribute) bool { return attr.Name == key })
+	return n - len(o.Attributes)
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+// RemoveValue removes the attributes with a given key and value, ignoring differences in whitespace, and returns the
+// number of attributes removed.
+func (o *Object) RemoveValue(key string, value string) int {
+	target := Attribute{Name: strings.ToLower(key), Value: value}
+	n := len(o.Attributes)
+	o.Attributes = slices.DeleteFunc(o.Attributes, func(attr Attribute) bool { return sameAttribute(&attr, &target) })
+	return n - len(o.Attributes)
+}
+
+// Rename renames every attribute with the key from to the key to, keeping their place, and returns the number of
+// attributes renamed.
+func (o *Object) Rename(from string, to string) int {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	n := 0
+	for i := range o.Attributes {
+		if o.Attributes[i].Name == from {
+			o.Attributes[i].Name = to
+			n++
+		}
 	}
 
-	// Don't forget the last object if there is one.
-	if currentObject.Len() > 0 {
-		attributes, err := parseAttributes(currentObject.Bytes())
-		if err != nil {
-			return nil, err
+	return n
+}
+
+// ReplaceValue replaces the value from of the attributes with a given key by the value to, ignoring differences in
+// whitespace, and returns the number of attributes changed.
+func (o *Object) ReplaceValue(key string, from string, to string) int {
+	target := Attribute{Name: strings.ToLower(key), Value: from}
+	n := 0
+	for i := range o.Attributes {
+		if sameAttribute(&o.Attributes[i], &target) {
+			o.Attributes[i].Value = to
+			n++
 		}
+	}
 
-		if len(attributes) > 0 {
-			objects = append(objects, Object{Attributes: attributes})
+	return n
+}
+
+// lastIndex returns the index of the last attribute with a given key, or -1 if the key is not present in the Object.
+func (o *Object) lastIndex(key string) int {
+	key = strings.ToLower(key)
+	for i := len(o.Attributes) - 1; i >= 0; i-- {
+		if o.Attributes[i].Name == key {
+			return i
 		}
 	}
 
+	return -1
+}
+
+// parseObjects parses every object read from r into a slice of Objects.
+func parseObjects(r io.Reader) ([]Object, error) {
+	// Start with a small capacity that will grow if needed.
+	objects := make([]Object, 0, 4)
+
+	scanner := NewScanner(r)
+	for scanner.Scan() {
+		objects = append(objects, scanner.Object())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
 	return objects, nil
 }
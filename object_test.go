@@ -316,6 +316,69 @@ func TestObjectString(t *testing.T) {
 	}
 }
 
+func TestObjectPrimaryKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   Object
+		expected string
+	}{
+		{
+			name:     "EmptyObject",
+			object:   Object{},
+			expected: "",
+		},
+		{
+			name: "ClassAttribute",
+			object: Object{
+				Attributes: []Attribute{
+					{Name: "mntner", Value: "EXAMPLE-MNT"},
+					{Name: "source", Value: "TEST"},
+				},
+			},
+			expected: "EXAMPLE-MNT",
+		},
+		{
+			name: "OtherAttribute",
+			object: Object{
+				Attributes: []Attribute{
+					{Name: "person", Value: "John Doe"},
+					{Name: "nic-hdl", Value: "JD1-TEST"},
+				},
+			},
+			expected: "JD1-TEST",
+		},
+		{
+			name: "CompositeKey",
+			object: Object{
+				Attributes: []Attribute{
+					{Name: "route", Value: "192.0.2.0/24"},
+					{Name: "descr", Value: "Example"},
+					{Name: "origin", Value: "AS64496"},
+				},
+			},
+			expected: "192.0.2.0/24AS64496",
+		},
+		{
+			name: "UnknownClass",
+			object: Object{
+				Attributes: []Attribute{
+					{Name: "unknown", Value: "value"},
+				},
+			},
+			expected: "value",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.object.PrimaryKey()
+			if result != tc.expected {
+				t.Errorf("Object.PrimaryKey() = %q, want %q", result, tc.expected)
+			}
+		})
+	}
+}
+
 func TestEnsureClass(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -631,3 +694,95 @@ func TestEnsureOne(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectMutations(t *testing.T) {
+	base := "route: 192.0.2.0/24\norigin: AS64496\nmnt-by: A-MNT\nmnt-by: B-MNT\nremarks: First\nsource: TEST"
+
+	tests := []struct {
+		name     string
+		mutate   func(o *Object) int
+		expected string
+		count    int
+	}{
+		{
+			name:     "SetExisting",
+			mutate:   func(o *Object) int { o.Set("MNT-BY", "C-MNT"); return 0 },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:C-MNT\nremarks:First\nsource:TEST",
+		},
+		{
+			name:     "SetMissing",
+			mutate:   func(o *Object) int { o.Set("descr", "Example"); return 0 },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:A-MNT\nmnt-by:B-MNT\nremarks:First\ndescr:Example\nsource:TEST",
+		},
+		{
+			name:     "AddAfterSameKey",
+			mutate:   func(o *Object) int { o.Add("mnt-by", "C-MNT"); return 0 },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:A-MNT\nmnt-by:B-MNT\nmnt-by:C-MNT\nremarks:First\nsource:TEST",
+		},
+		{
+			name:     "AddBeforeSource",
+			mutate:   func(o *Object) int { o.Add("member-of", "RS-EXAMPLE"); return 0 },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:A-MNT\nmnt-by:B-MNT\nremarks:First\nmember-of:RS-EXAMPLE\nsource:TEST",
+		},
+		{
+			name: "InsertAfter",
+			mutate: func(o *Object) int {
+				if err := o.InsertAfter("origin", Attribute{Name: "Descr", Value: "One"}, Attribute{Name: "descr", Value: "Two"}); err != nil {
+					return -1
+				}
+
+				return 0
+			},
+			expected: "route:192.0.2.0/24\norigin:AS64496\ndescr:One\ndescr:Two\nmnt-by:A-MNT\nmnt-by:B-MNT\nremarks:First\nsource:TEST",
+		},
+		{
+			name:     "RemoveAll",
+			mutate:   func(o *Object) int { return o.RemoveAll("mnt-by") },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nremarks:First\nsource:TEST",
+			count:    2,
+		},
+		{
+			name:     "RemoveValue",
+			mutate:   func(o *Object) int { return o.RemoveValue("mnt-by", " B-MNT ") },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:A-MNT\nremarks:First\nsource:TEST",
+			count:    1,
+		},
+		{
+			name:     "Rename",
+			mutate:   func(o *Object) int { return o.Rename("remarks", "descr") },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:A-MNT\nmnt-by:B-MNT\ndescr:First\nsource:TEST",
+			count:    1,
+		},
+		{
+			name:     "ReplaceValue",
+			mutate:   func(o *Object) int { return o.ReplaceValue("mnt-by", "A-MNT", "C-MNT") },
+			expected: "route:192.0.2.0/24\norigin:AS64496\nmnt-by:C-MNT\nmnt-by:B-MNT\nremarks:First\nsource:TEST",
+			count:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, err := Parse(base)
+			if err != nil {
+				t.Fatalf(`Parse => %v`, err)
+			}
+
+			count := tc.mutate(obj)
+			if result := obj.String(); result != tc.expected || count != tc.count {
+				t.Errorf("mutation => %q, %d, want %q, %d", result, count, tc.expected, tc.count)
+			}
+		})
+	}
+
+	single := Object{Attributes: []Attribute{{Name: "source", Value: "TEST"}}}
+	single.Add("mnt-by", "A-MNT")
+	if result, want := single.String(), "mnt-by:A-MNT\nsource:TEST"; result != want {
+		t.Errorf(`Add(mnt-by) => %q, want %q`, result, want)
+	}
+
+	obj := Object{Attributes: []Attribute{{Name: "mntner", Value: "EXAMPLE-MNT"}}}
+	if err := obj.InsertAfter("mnt-by", Attribute{Name: "remarks"}); err == nil {
+		t.Errorf(`InsertAfter(mnt-by) => nil, want an error`)
+	}
+}
@@ -0,0 +1,531 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// PathRegex is an AS path regular expression, such as "<^AS64496+ AS-FOO*$>", as defined by RFC 2622 section 5.4.
+type PathRegex struct {
+	// Source is the text of the expression, without the enclosing '<' and '>'.
+	Source string
+
+	root *pathNode
+}
+
+// pathKind is the kind of a pathNode.
+type pathKind uint8
+
+const (
+	pathAtom   pathKind = iota // A single AS matching the atom.
+	pathSeq                    // The children in sequence.
+	pathAlt                    // Any of the children.
+	pathRepeat                 // The child repeated min to max times.
+	pathStart                  // The start of the path.
+	pathEnd                    // The end of the path.
+)
+
+// pathNode is a node of a compiled AS path regular expression.
+type pathNode struct {
+	kind     pathKind
+	children []*pathNode
+
+	// Repetitions, max is -1 when unbounded. same requires every repetition to match the same AS.
+	min, max int
+	same     bool
+
+	// Atom, matching any AS if any is set, or else the AS numbers, AS ranges, as-set names and PeerAS. The match is
+	// inverted if negated is set.
+	any     bool
+	negated bool
+	asns    []uint32
+	ranges  [][2]uint32
+	sets    []string
+	peerAS  bool
+}
+
+// ParsePathRegex parses an AS path regular expression, with or without the enclosing '<' and '>'.
+func ParsePathRegex(value string) (*PathRegex, error) {
+	source := strings.TrimSpace(value)
+	if strings.HasPrefix(source, "<") && strings.HasSuffix(source, ">") {
+		source = strings.TrimSpace(source[1 : len(source)-1])
+	}
+
+	p := &pathParser{tokens: tokenizePath(source), source: source}
+	root, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected '%s'", p.tokens[p.pos])
+	}
+
+	return &PathRegex{Source: source, root: root}, nil
+}
+
+// String returns the text of the PathRegex, enclosed in '<' and '>'.
+func (r *PathRegex) String() string {
+	return "<" + r.Source + ">"
+}
+
+// Sets returns the names of the as-sets referenced by the PathRegex.
+func (r *PathRegex) Sets() []string {
+	var sets []string
+	var walk func(n *pathNode)
+	walk = func(n *pathNode) {
+		sets = append(sets, n.sets...)
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+
+	walk(r.root)
+	return sets
+}
+
+// Match returns true if the AS path matches the expression. The path starts with the AS of the peer and ends with the
+// origin AS. The members of the as-sets are looked up with resolver, which may be nil if the expression does not
+// reference any set.
+func (r *PathRegex) Match(path []uint32, peerAS uint32, resolver Resolver) (bool, error) {
+	m := &pathMatcher{path: path, peerAS: peerAS, sets: make(map[string]map[uint32]struct{})}
+	for _, name := range r.Sets() {
+		key := strings.ToUpper(name)
+		if _, ok := m.sets[key]; ok {
+			continue
+		}
+
+		if resolver == nil {
+			return false, fmt.Errorf("policy: cannot resolve '%s' without a resolver", name)
+		}
+
+		asns, err := resolver.ASNs(name)
+		if err != nil {
+			return false, err
+		}
+
+		members := make(map[uint32]struct{}, len(asns))
+		for _, asn := range asns {
+			members[asn] = struct{}{}
+		}
+
+		m.sets[key] = members
+	}
+
+	// Unanchored expressions can match anywhere in the path.
+	for start := 0; start <= len(path); start++ {
+		if m.match(r.root, start, func(int) bool { return true }) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// pathMatcher matches a path against compiled nodes by backtracking.
+type pathMatcher struct {
+	path   []uint32
+	peerAS uint32
+	sets   map[string]map[uint32]struct{}
+}
+
+// match matches n at position i of the path, and calls next with the position following every possible match until
+// it returns true.
+func (m *pathMatcher) match(n *pathNode, i int, next func(int) bool) bool {
+	switch n.kind {
+	case pathAtom:
+		return i < len(m.path) && m.matchAtom(n, m.path[i]) && next(i+1)
+	case pathStart:
+		return i == 0 && next(i)
+	case pathEnd:
+		return i == len(m.path) && next(i)
+	case pathAlt:
+		for _, child := range n.children {
+			if m.match(child, i, next) {
+				return true
+			}
+		}
+
+		return false
+	case pathSeq:
+		return m.matchSeq(n.children, i, next)
+	case pathRepeat:
+		if n.same {
+			return m.matchSame(n, i, next)
+		}
+
+		return m.matchRepeat(n, 0, i, next)
+	}
+
+	return false
+}
+
+// matchSeq matches nodes in sequence at position i.
+func (m *pathMatcher) matchSeq(nodes []*pathNode, i int, next func(int) bool) bool {
+	if len(nodes) == 0 {
+		return next(i)
+	}
+
+	return m.match(nodes[0], i, func(j int) bool {
+		return m.matchSeq(nodes[1:], j, next)
+	})
+}
+
+// matchRepeat matches the child of n at position i, after count repetitions. Repetitions are greedy, and those past
+// the minimum must consume part of the path.
+func (m *pathMatcher) matchRepeat(n *pathNode, count int, i int, next func(int) bool) bool {
+	if n.max < 0 || count < n.max {
+		more := m.match(n.children[0], i, func(j int) bool {
+			if j == i && count >= n.min {
+				return false
+			}
+
+			return m.matchRepeat(n, count+1, j, next)
+		})
+
+		if more {
+			return true
+		}
+	}
+
+	return count >= n.min && next(i)
+}
+
+// matchSame matches the repetitions of an atom at position i, all matching the same AS.
+func (m *pathMatcher) matchSame(n *pathNode, i int, next func(int) bool) bool {
+	atom := n.children[0]
+	end := i
+	for end < len(m.path) && (n.max < 0 || end-i < n.max) && m.matchAtom(atom, m.path[end]) && m.path[end] == m.path[i] {
+		end++
+	}
+
+	for ; end-i >= n.min; end-- {
+		if next(end) {
+			return true
+		}
+
+		if end == i {
+			break
+		}
+	}
+
+	return false
+}
+
+// matchAtom returns true if the AS matches the atom.
+func (m *pathMatcher) matchAtom(n *pathNode, asn uint32) bool {
+	matched := n.any || n.peerAS && asn == m.peerAS
+	for _, a := range n.asns {
+		matched = matched || a == asn
+	}
+
+	for _, r := range n.ranges {
+		matched = matched || asn >= r[0] && asn <= r[1]
+	}
+
+	for _, name := range n.sets {
+		_, ok := m.sets[strings.ToUpper(name)][asn]
+		matched = matched || ok
+	}
+
+	return matched != n.negated
+}
+
+// tokenizePath splits an AS path regular expression into words and operators.
+func tokenizePath(source string) []string {
+	tokens := make([]string, 0, 8)
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '~' || c == '{':
+			// Repetitions such as "~*", "~+", "{2}" or "~{1,3}" are single tokens.
+			end := i + 1
+			if c == '~' && end < len(source) && source[end] == '{' || c == '{' {
+				if close := strings.IndexByte(source[i:], '}'); close >= 0 {
+					end = i + close + 1
+				}
+			} else if end < len(source) {
+				end++
+			}
+
+			tokens = append(tokens, source[i:end])
+			i = end
+		case isPathWordChar(c):
+			end := i
+			for end < len(source) && isPathWordChar(source[end]) {
+				end++
+			}
+
+			tokens = append(tokens, source[i:end])
+			i = end
+		default:
+			tokens = append(tokens, source[i:i+1])
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// isPathWordChar returns true if c can be part of an AS number or a set name.
+func isPathWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == ':'
+}
+
+// pathParser parses the tokens of an AS path regular expression.
+type pathParser struct {
+	tokens []string
+	pos    int
+	source string
+}
+
+// peek returns the next token, or an empty string at the end of the expression.
+func (p *pathParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+// parseAlt parses sequences separated by '|'.
+func (p *pathParser) parseAlt() (*pathNode, error) {
+	seq, err := p.parseSeq()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek() != "|" {
+		return seq, nil
+	}
+
+	alt := &pathNode{kind: pathAlt, children: []*pathNode{seq}}
+	for p.peek() == "|" {
+		p.pos++
+		seq, err := p.parseSeq()
+		if err != nil {
+			return nil, err
+		}
+
+		alt.children = append(alt.children, seq)
+	}
+
+	return alt, nil
+}
+
+// parseSeq parses a sequence of repeated terms, up to a '|', a ')' or the end of the expression.
+func (p *pathParser) parseSeq() (*pathNode, error) {
+	seq := &pathNode{kind: pathSeq}
+	for token := p.peek(); token != "" && token != "|" && token != ")"; token = p.peek() {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		if term, err = p.parseRepeat(term); err != nil {
+			return nil, err
+		}
+
+		seq.children = append(seq.children, term)
+	}
+
+	return seq, nil
+}
+
+// parseTerm parses an anchor, an atom or a parenthesized expression.
+func (p *pathParser) parseTerm() (*pathNode, error) {
+	token := p.peek()
+	p.pos++
+	switch token {
+	case "^":
+		return &pathNode{kind: pathStart}, nil
+	case "$":
+		return &pathNode{kind: pathEnd}, nil
+	case ".":
+		return &pathNode{kind: pathAtom, any: true}, nil
+	case "(":
+		node, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, p.errorf("expected ')'")
+		}
+
+		p.pos++
+		return node, nil
+	case "[":
+		return p.parseBracket()
+	}
+
+	atom := &pathNode{kind: pathAtom}
+	if err := p.addOperand(atom, token); err != nil {
+		return nil, err
+	}
+
+	return atom, nil
+}
+
+// parseBracket parses the AS numbers, AS ranges and set names of a "[...]" atom, after its opening bracket.
+func (p *pathParser) parseBracket() (*pathNode, error) {
+	atom := &pathNode{kind: pathAtom}
+	if p.peek() == "^" {
+		atom.negated = true
+		p.pos++
+	}
+
+	for token := p.peek(); token != "]"; token = p.peek() {
+		p.pos++
+		switch {
+		case token == "":
+			return nil, p.errorf("expected ']'")
+		case token == ".":
+			atom.any = true
+		case p.peek() == "-":
+			// AS range written with spaces, such as "AS64496 - AS64511".
+			p.pos++
+			last := p.peek()
+			p.pos++
+			if err := p.addRange(atom, token, last); err != nil {
+				return nil, err
+			}
+		default:
+			// AS range written without spaces, such as "AS64496-AS64511".
+			if first, last, found := strings.Cut(token, "-"); found && isASN(first) && isASN(last) {
+				if err := p.addRange(atom, first, last); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			if err := p.addOperand(atom, token); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p.pos++
+	return atom, nil
+}
+
+// addOperand adds an AS number, an as-set name or PeerAS to an atom.
+func (p *pathParser) addOperand(atom *pathNode, token string) error {
+	switch {
+	case strings.EqualFold(token, "PeerAS"):
+		atom.peerAS = true
+	case strings.EqualFold(token, "AS-ANY"):
+		atom.any = true
+	case isASN(token):
+		asn, _ := rpsl.ParseASN(token)
+		atom.asns = append(atom.asns, asn)
+	case rpsl.CheckASSetName(token) == nil:
+		atom.sets = append(atom.sets, token)
+	default:
+		p.pos--
+		return p.errorf("unexpected '%s'", token)
+	}
+
+	return nil
+}
+
+// addRange adds a range of AS numbers to an atom.
+func (p *pathParser) addRange(atom *pathNode, first string, last string) error {
+	start, err := rpsl.ParseASN(first)
+	if err != nil {
+		return p.errorf("invalid AS range '%s - %s'", first, last)
+	}
+
+	end, err := rpsl.ParseASN(last)
+	if err != nil || end < start {
+		return p.errorf("invalid AS range '%s - %s'", first, last)
+	}
+
+	atom.ranges = append(atom.ranges, [2]uint32{start, end})
+	return nil
+}
+
+// parseRepeat parses the optional repetition operators following a term.
+func (p *pathParser) parseRepeat(term *pathNode) (*pathNode, error) {
+	for {
+		token := p.peek()
+		same := strings.HasPrefix(token, "~")
+		low, high := 0, 0
+		switch strings.TrimPrefix(token, "~") {
+		case "*":
+			low, high = 0, -1
+		case "+":
+			low, high = 1, -1
+		case "?":
+			if same {
+				return nil, p.errorf("unexpected '%s'", token)
+			}
+
+			low, high = 0, 1
+		default:
+			if !strings.HasPrefix(token, "{") && !strings.HasPrefix(token, "~{") {
+				return term, nil
+			}
+
+			var err error
+			if low, high, err = parseBounds(strings.TrimPrefix(token, "~")); err != nil {
+				return nil, p.errorf("%v", err)
+			}
+		}
+
+		if same && term.kind != pathAtom {
+			return nil, p.errorf("'%s' only applies to a single AS", token)
+		}
+
+		p.pos++
+		term = &pathNode{kind: pathRepeat, children: []*pathNode{term}, min: low, max: high, same: same}
+	}
+}
+
+// parseBounds parses the bounds of a "{m}", "{m,}" or "{m,n}" repetition.
+func parseBounds(token string) (int, int, error) {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(token, "{"), "}")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid repetition '%s'", token)
+	}
+
+	first, last, found := strings.Cut(inner, ",")
+	low, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || low < 0 {
+		return 0, 0, fmt.Errorf("invalid repetition '%s'", token)
+	}
+
+	if !found {
+		return low, low, nil
+	}
+
+	if strings.TrimSpace(last) == "" {
+		return low, -1, nil
+	}
+
+	high, err := strconv.Atoi(strings.TrimSpace(last))
+	if err != nil || high < low {
+		return 0, 0, fmt.Errorf("invalid repetition '%s'", token)
+	}
+
+	return low, high, nil
+}
+
+// errorf returns an error located at the current token.
+func (p *pathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("policy: %s in AS path regular expression '%s'", fmt.Sprintf(format, args...), p.source)
+}
+
+// isASN returns true if value is an AS number.
+func isASN(value string) bool {
+	return rpsl.CheckASNumber(value) == nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+)
+
+func TestPathRegexMatch(t *testing.T) {
+	tests := []struct {
+		regex string
+		path  []uint32
+		want  bool
+	}{
+		{"<AS64496>", []uint32{64511, 64496, 64510}, true},
+		{"<^AS64496>", []uint32{64511, 64496}, false},
+		{"<AS64496$>", []uint32{64511, 64496}, true},
+		{"<^$>", []uint32{}, true},
+		{"<^.*$>", []uint32{64511, 64496}, true},
+		{"<^AS64511 .? AS64496$>", []uint32{64511, 64496}, true},
+		{"<^AS64511 .? AS64496$>", []uint32{64511, 64500, 64501, 64496}, false},
+		{"<^AS64511+ AS64496$>", []uint32{64511, 64511, 64511, 64496}, true},
+		{"<^AS64511{2} AS64496$>", []uint32{64511, 64511, 64511, 64496}, false},
+		{"<^AS64511{1,} AS64496$>", []uint32{64511, 64511, 64511, 64496}, true},
+		{"<^(AS64511 | AS64512) AS64496$>", []uint32{64512, 64496}, true},
+		{"<^[AS64500-AS64520]* AS64496$>", []uint32{64501, 64519, 64496}, true},
+		{"<^[AS64500 - AS64520]* AS64496$>", []uint32{64499, 64496}, false},
+		{"<^[^AS64511] AS64496$>", []uint32{64511, 64496}, false},
+		{"<^[^AS64511] AS64496$>", []uint32{64512, 64496}, true},
+		{"<^.~+$>", []uint32{64496, 64496, 64496}, true},
+		{"<^.~+$>", []uint32{64496, 64497, 64496}, false},
+		{"<^.~+ .~+$>", []uint32{64496, 64496, 64497}, true},
+		{"<^PeerAS AS-FOO*$>", []uint32{64511, 64497, 64496}, true},
+		{"<^PeerAS AS-FOO*$>", []uint32{64511, 64497, 64510}, false},
+	}
+
+	for _, tt := range tests {
+		regex, err := ParsePathRegex(tt.regex)
+		if err != nil {
+			t.Fatalf(`ParsePathRegex(%q) => %v`, tt.regex, err)
+		}
+
+		got, err := regex.Match(tt.path, 64511, resolver)
+		if err != nil {
+			t.Errorf(`PathRegex(%q).Match(%v) => %v`, tt.regex, tt.path, err)
+		} else if got != tt.want {
+			t.Errorf(`PathRegex(%q).Match(%v) => %v, want %v`, tt.regex, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePathRegexErrors(t *testing.T) {
+	tests := []string{"<(AS64496>", "<[AS64496>", "<FOO>", "<AS64496{2>", "<AS64496{3,2}>", "<(AS1 AS2)~*>",
+		"<AS64510-AS64500>", "<*>"}
+
+	for _, value := range tests {
+		if regex, err := ParsePathRegex(value); err == nil {
+			t.Errorf(`ParsePathRegex(%q) => %v, want an error`, value, regex)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// FilterKind is the kind of a Filter.
+type FilterKind uint8
+
+const (
+	// FilterAny matches every route.
+	FilterAny FilterKind = iota
+	// FilterPeerAS matches the routes originated by the peer AS.
+	FilterPeerAS
+	// FilterAS matches the routes originated by an AS number.
+	FilterAS
+	// FilterSet matches the routes of an as-set, a route-set or a filter-set.
+	FilterSet
+	// FilterPrefixes matches the routes in a set of prefix ranges.
+	FilterPrefixes
+	// FilterPath matches the routes whose AS path matches a regular expression.
+	FilterPath
+	// FilterCommunity matches the routes with some communities.
+	FilterCommunity
+	// FilterNot matches the routes not matched by its operand.
+	FilterNot
+	// FilterAnd matches the routes matched by both of its operands.
+	FilterAnd
+	// FilterOr matches the routes matched by any of its operands.
+	FilterOr
+)
+
+// Filter is an RPSL filter, as defined by RFC 2622 section 5.4, such as "AS64496 AND { 192.0.2.0/24^+ }".
+type Filter struct {
+	Kind FilterKind
+	// Left and Right are the operands of FilterAnd and FilterOr. FilterNot only has Left.
+	Left, Right *Filter
+	// Name is the AS number of FilterAS, or the set name of FilterSet.
+	Name string
+	// Operator is the range operator applied to the routes of FilterPeerAS, FilterAS, FilterSet and FilterPrefixes.
+	Operator RangeOperator
+	// Prefixes are the prefix ranges of FilterPrefixes.
+	Prefixes []PrefixRange
+	// Path is the AS path regular expression of FilterPath.
+	Path *PathRegex
+	// Community is the community match of FilterCommunity.
+	Community *CommunityFilter
+}
+
+// CommunityFilter matches the communities of a route, such as "community(64496:1)" or "community == {64496:1}".
+type CommunityFilter struct {
+	// Method is "contains" if the route must have every community, or "==" if it must have exactly the communities.
+	Method string
+	// Communities are the communities to match.
+	Communities []Community
+}
+
+// Community is a BGP community, as defined by RFC 1997.
+type Community uint32
+
+// Well-known communities, as defined by RFC 1997.
+const (
+	NoExport          Community = 0xFFFFFF01
+	NoAdvertise       Community = 0xFFFFFF02
+	NoExportSubconfed Community = 0xFFFFFF03
+)
+
+// communityNames holds the names of the well-known communities. Internet is the community 0:0.
+var communityNames = map[string]Community{
+	"internet":            0,
+	"no_export":           NoExport,
+	"no_advertise":        NoAdvertise,
+	"no_export_subconfed": NoExportSubconfed,
+}
+
+// ParseCommunity parses a community written as "64496:1", as a 32-bit integer, or as the name of a well-known
+// community such as "no_export".
+func ParseCommunity(value string) (Community, error) {
+	if c, ok := communityNames[strings.ToLower(value)]; ok {
+		return c, nil
+	}
+
+	high, low, found := strings.Cut(value, ":")
+	if !found {
+		c, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("policy: invalid community '%s'", value)
+		}
+
+		return Community(c), nil
+	}
+
+	h, err := strconv.ParseUint(high, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("policy: invalid community '%s'", value)
+	}
+
+	l, err := strconv.ParseUint(low, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("policy: invalid community '%s'", value)
+	}
+
+	return Community(h<<16 | l), nil
+}
+
+// String returns the name of a well-known Community, or else the Community written as "64496:1".
+func (c Community) String() string {
+	switch c {
+	case 0:
+		return "internet"
+	case NoExport:
+		return "no_export"
+	case NoAdvertise:
+		return "no_advertise"
+	case NoExportSubconfed:
+		return "no_export_subconfed"
+	}
+
+	return strconv.FormatUint(uint64(c>>16), 10) + ":" + strconv.FormatUint(uint64(c&0xFFFF), 10)
+}
+
+// ParseFilter parses a filter, such as the filter of an accept or announce clause, or the filter attribute of a
+// filter-set.
+//
+// Example:
+//
+//	f, err := policy.ParseFilter("AS-FOO AND NOT { 0.0.0.0/0^25-32 }")
+//	if err != nil {
+//	    log.Fatalf("Failed to parse filter: %v", err)
+//	}
+//
+//	ok, err := f.Match(&policy.Route{Prefix: netip.MustParsePrefix("192.0.2.0/24")}, resolver)
+func ParseFilter(value string) (*Filter, error) {
+	p := &filterParser{lexer: lexer{input: value}}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, p.errorf("expected end of filter")
+	}
+
+	return f, nil
+}
+
+// filterParser parses filters. NOT has the highest precedence and OR the lowest. Filters following each other
+// without an operator are combined with OR.
+type filterParser struct {
+	lexer
+}
+
+// parseOr parses terms combined with OR.
+func (p *filterParser) parseOr() (*Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.acceptKeyword("or")
+		if token := p.peek(); token == "" || token == ")" || token == "}" {
+			return left, nil
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Filter{Kind: FilterOr, Left: left, Right: right}
+	}
+}
+
+// parseAnd parses factors combined with AND.
+func (p *filterParser) parseAnd() (*Filter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Filter{Kind: FilterAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseNot parses an operand, optionally negated.
+func (p *filterParser) parseNot() (*Filter, error) {
+	if !p.acceptKeyword("not") {
+		return p.parseOperand()
+	}
+
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{Kind: FilterNot, Left: operand}, nil
+}
+
+// parseOperand parses a parenthesized filter, a prefix set, an AS path regular expression, a community match, or a
+// name followed by an optional range operator.
+func (p *filterParser) parseOperand() (*Filter, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '<' {
+		return p.parsePath()
+	}
+
+	token := p.peek()
+	switch {
+	case token == "(":
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return f, nil
+	case token == "{":
+		p.next()
+		prefixes, err := p.parsePrefixes()
+		if err != nil {
+			return nil, err
+		}
+
+		op, err := p.parseRangeOperator()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Filter{Kind: FilterPrefixes, Prefixes: prefixes, Operator: op}, nil
+	case strings.HasPrefix(strings.ToLower(token), "community"):
+		return p.parseCommunity()
+	case token == "" || isPunct(token[0]) || isKeyword(token):
+		return nil, p.errorf("expected a filter")
+	}
+
+	p.next()
+	name, op, err := CutRangeOperator(token)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.EqualFold(name, "ANY") && op.Kind == NoRange:
+		return &Filter{Kind: FilterAny}, nil
+	case strings.EqualFold(name, "PeerAS"):
+		return &Filter{Kind: FilterPeerAS, Operator: op}, nil
+	case isASN(name):
+		return &Filter{Kind: FilterAS, Name: name, Operator: op}, nil
+	case isFilterSet(name):
+		return &Filter{Kind: FilterSet, Name: name, Operator: op}, nil
+	}
+
+	return nil, fmt.Errorf("policy: invalid filter operand '%s'", token)
+}
+
+// isFilterSet returns true if name is an as-set, route-set or filter-set name.
+func isFilterSet(name string) bool {
+	return rpsl.CheckASSetName(name) == nil || rpsl.CheckRouteSetName(name) == nil ||
+		rpsl.CheckFilterSetName(name) == nil
+}
+
+// parsePrefixes parses the comma-separated prefix ranges of a prefix set, after its opening brace.
+func (p *filterParser) parsePrefixes() ([]PrefixRange, error) {
+	end := strings.IndexByte(p.input[p.pos:], '}')
+	if end < 0 {
+		return nil, p.errorf("expected '}'")
+	}
+
+	text := p.input[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	prefixes := make([]PrefixRange, 0, 4)
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		r, err := ParsePrefixRange(item)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixes = append(prefixes, r)
+	}
+
+	return prefixes, nil
+}
+
+// parseRangeOperator parses a range operator immediately following the current position.
+func (p *filterParser) parseRangeOperator() (RangeOperator, error) {
+	if p.pos >= len(p.input) || p.input[p.pos] != '^' {
+		return RangeOperator{}, nil
+	}
+
+	return ParseRangeOperator(p.next())
+}
+
+// parsePath parses an AS path regular expression enclosed in '<' and '>'.
+func (p *filterParser) parsePath() (*Filter, error) {
+	end := strings.IndexByte(p.input[p.pos:], '>')
+	if end < 0 {
+		return nil, p.errorf("expected '>'")
+	}
+
+	path, err := ParsePathRegex(p.input[p.pos : p.pos+end+1])
+	if err != nil {
+		return nil, err
+	}
+
+	p.pos += end + 1
+	return &Filter{Kind: FilterPath, Path: path}, nil
+}
+
+// parseCommunity parses "community(...)", "community.contains(...)" or "community == {...}".
+func (p *filterParser) parseCommunity() (*Filter, error) {
+	token := strings.ToLower(p.next())
+	method := strings.TrimPrefix(token, "community")
+	var close byte
+	switch {
+	case method == "" && p.peek() == "(", method == ".contains":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		close = ')'
+	case method == "" && p.peek() == "==":
+		p.next()
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+
+		close = '}'
+	default:
+		return nil, fmt.Errorf("policy: invalid community filter '%s'", token)
+	}
+
+	end := strings.IndexByte(p.input[p.pos:], close)
+	if end < 0 {
+		return nil, p.errorf("expected '%c'", close)
+	}
+
+	text := p.input[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	cf := &CommunityFilter{Method: "contains"}
+	if close == '}' {
+		cf.Method = "=="
+	}
+
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		c, err := ParseCommunity(item)
+		if err != nil {
+			return nil, err
+		}
+
+		cf.Communities = append(cf.Communities, c)
+	}
+
+	return &Filter{Kind: FilterCommunity, Community: cf}, nil
+}
+
+// String returns a string representation of the Filter, with parentheses where required by precedence.
+func (f *Filter) String() string {
+	switch f.Kind {
+	case FilterAny:
+		return "ANY"
+	case FilterPeerAS:
+		return "PeerAS" + f.Operator.String()
+	case FilterAS, FilterSet:
+		return f.Name + f.Operator.String()
+	case FilterPrefixes:
+		if len(f.Prefixes) == 0 {
+			return "{}" + f.Operator.String()
+		}
+
+		items := make([]string, len(f.Prefixes))
+		for i, r := range f.Prefixes {
+			items[i] = r.String()
+		}
+
+		return "{ " + strings.Join(items, ", ") + " }" + f.Operator.String()
+	case FilterPath:
+		return f.Path.String()
+	case FilterCommunity:
+		return f.Community.String()
+	case FilterNot:
+		return "NOT " + f.Left.operand(FilterNot)
+	case FilterAnd:
+		return f.Left.operand(FilterAnd) + " AND " + f.Right.operand(FilterAnd)
+	case FilterOr:
+		return f.Left.String() + " OR " + f.Right.String()
+	}
+
+	return ""
+}
+
+// operand returns a string representation of the Filter as an operand of parent, parenthesized if it has a lower
+// precedence.
+func (f *Filter) operand(parent FilterKind) string {
+	if f.Kind == FilterOr || f.Kind == FilterAnd && parent == FilterNot {
+		return "(" + f.String() + ")"
+	}
+
+	return f.String()
+}
+
+// String returns a string representation of the CommunityFilter.
+func (cf *CommunityFilter) String() string {
+	items := make([]string, len(cf.Communities))
+	for i, c := range cf.Communities {
+		items[i] = c.String()
+	}
+
+	if cf.Method == "==" {
+		return "community == { " + strings.Join(items, ", ") + " }"
+	}
+
+	return "community(" + strings.Join(items, ", ") + ")"
+}
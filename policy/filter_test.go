@@ -0,0 +1,175 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+// testResolver resolves sets from maps, keyed by upper-case name.
+type testResolver struct {
+	asns     map[string][]uint32
+	prefixes map[string][]string
+	filters  map[string]string
+}
+
+func (r *testResolver) ASNs(name string) ([]uint32, error) {
+	if asns, ok := r.asns[strings.ToUpper(name)]; ok {
+		return asns, nil
+	}
+
+	return nil, errors.New("unknown set " + name)
+}
+
+func (r *testResolver) Prefixes(name string) ([]PrefixRange, error) {
+	items, ok := r.prefixes[strings.ToUpper(name)]
+	if !ok {
+		return nil, errors.New("unknown set " + name)
+	}
+
+	ranges := make([]PrefixRange, len(items))
+	for i, item := range items {
+		var err error
+		if ranges[i], err = ParsePrefixRange(item); err != nil {
+			return nil, err
+		}
+	}
+
+	return ranges, nil
+}
+
+func (r *testResolver) Filter(name string) (*Filter, error) {
+	if text, ok := r.filters[strings.ToUpper(name)]; ok {
+		return ParseFilter(text)
+	}
+
+	return nil, errors.New("unknown set " + name)
+}
+
+var resolver = &testResolver{
+	asns: map[string][]uint32{"AS-FOO": {64496, 64497}},
+	prefixes: map[string][]string{
+		"AS64496": {"192.0.2.0/24"},
+		"AS64497": {"198.51.100.0/24", "2001:db8::/32"},
+		"AS-FOO":  {"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"},
+		"RS-FOO":  {"203.0.113.0/24^+", "10.0.0.0/8^16-24"},
+	},
+	filters: map[string]string{
+		"FLTR-BOGONS": "{ 10.0.0.0/8^+, 0.0.0.0/0^25-32 }",
+		"FLTR-LOOP":   "FLTR-LOOP",
+	},
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"ANY", "ANY"},
+		{"AS64496 AS64497", "AS64496 OR AS64497"},
+		{"AS64496 OR AS64497 AND NOT AS-FOO", "AS64496 OR AS64497 AND NOT AS-FOO"},
+		{"NOT (AS64496 AND RS-FOO^+)", "NOT (AS64496 AND RS-FOO^+)"},
+		{"(AS64496 OR AS64497) AND PeerAS^-", "(AS64496 OR AS64497) AND PeerAS^-"},
+		{"{192.0.2.0/24^+,2001:db8::/32^48-64}^-", "{ 192.0.2.0/24^+, 2001:db8::/32^48-64 }^-"},
+		{"{ }", "{}"},
+		{"<^AS64496 AS-FOO* [AS64500-AS64510 .]~+$>", "<^AS64496 AS-FOO* [AS64500-AS64510 .]~+$>"},
+		{"community(64496:1, no_export)", "community(64496:1, no_export)"},
+		{"community.contains(64496:1) OR community == {4226809857}", "community(64496:1) OR community == { 64496:1 }"},
+		{"fltr-bogons", "fltr-bogons"},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.value)
+		if err != nil {
+			t.Errorf(`ParseFilter(%q) => %v`, tt.value, err)
+			continue
+		}
+
+		if got := f.String(); got != tt.want {
+			t.Errorf(`ParseFilter(%q).String() => %q, want %q`, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []string{"", "AS64496 AND", "NOT", "(AS64496", "FOO", "AS-FOO^", "RS-FOO^33-24", "{ 192.0.2.1/24 }",
+		"{ 192.0.2.0/24", "<AS64496", "community(no_such)", "community(1:2:3)", "AS64496 )"}
+
+	for _, value := range tests {
+		if f, err := ParseFilter(value); err == nil {
+			t.Errorf(`ParseFilter(%q) => %v, want an error`, value, f)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		filter string
+		route  Route
+		want   bool
+	}{
+		{"ANY", Route{Prefix: netip.MustParsePrefix("0.0.0.0/0")}, true},
+		{"AS64496", Route{Prefix: netip.MustParsePrefix("192.0.2.0/24")}, true},
+		{"AS64496", Route{Prefix: netip.MustParsePrefix("192.0.2.0/25")}, false},
+		{"AS64496^+", Route{Prefix: netip.MustParsePrefix("192.0.2.0/25")}, true},
+		{"AS-FOO", Route{Prefix: netip.MustParsePrefix("2001:db8::/32")}, true},
+		{"RS-FOO", Route{Prefix: netip.MustParsePrefix("10.1.0.0/16")}, true},
+		{"RS-FOO", Route{Prefix: netip.MustParsePrefix("10.0.0.0/8")}, false},
+		{"RS-FOO^24", Route{Prefix: netip.MustParsePrefix("10.1.0.0/16")}, false},
+		{"RS-FOO^24", Route{Prefix: netip.MustParsePrefix("10.1.1.0/24")}, true},
+		{"PeerAS", Route{Prefix: netip.MustParsePrefix("192.0.2.0/24"), Path: []uint32{64496}}, true},
+		{"PeerAS", Route{Prefix: netip.MustParsePrefix("192.0.2.0/24"), PeerAS: 64497}, false},
+		{"NOT FLTR-BOGONS", Route{Prefix: netip.MustParsePrefix("10.1.0.0/16")}, false},
+		{"NOT FLTR-BOGONS", Route{Prefix: netip.MustParsePrefix("192.0.2.0/24")}, true},
+		{"AS-FOO AND <AS-FOO$>", Route{Prefix: netip.MustParsePrefix("192.0.2.0/24"), Path: []uint32{64511, 64496}}, true},
+		{"AS-FOO AND <^AS-FOO>", Route{Prefix: netip.MustParsePrefix("192.0.2.0/24"), Path: []uint32{64511, 64496}}, false},
+		{"{ 192.0.2.0/24 }^+ AND community(64496:1)", Route{
+			Prefix:      netip.MustParsePrefix("192.0.2.128/25"),
+			Communities: []Community{NoExport, 64496<<16 | 1},
+		}, true},
+		{"community == {64496:1}", Route{Communities: []Community{NoExport, 64496<<16 | 1}}, false},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf(`ParseFilter(%q) => %v`, tt.filter, err)
+		}
+
+		got, err := f.Match(&tt.route, resolver)
+		if err != nil {
+			t.Errorf(`Filter(%q).Match(%v) => %v`, tt.filter, tt.route.Prefix, err)
+		} else if got != tt.want {
+			t.Errorf(`Filter(%q).Match(%v) => %v, want %v`, tt.filter, tt.route.Prefix, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMatchErrors(t *testing.T) {
+	route := &Route{Prefix: netip.MustParsePrefix("192.0.2.0/24")}
+	tests := []struct {
+		filter   string
+		resolver Resolver
+	}{
+		{"AS64496", nil},
+		{"AS-BAR", resolver},
+		{"FLTR-LOOP", resolver},
+		{"PeerAS", resolver},
+		{"NOT PeerAS", resolver},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf(`ParseFilter(%q) => %v`, tt.filter, err)
+		}
+
+		if ok, err := f.Match(route, tt.resolver); err == nil || ok {
+			t.Errorf(`Filter(%q).Match => %v, %v, want false and an error`, tt.filter, ok, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// lexer splits a policy expression into words and punctuation. Filters are read as raw text, as their syntax differs
+// from the rest of the expression.
+type lexer struct {
+	input string
+	pos   int
+}
+
+// isSpace returns true if c is a whitespace character.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isPunct returns true if c is a punctuation character, which is a token on its own.
+func isPunct(c byte) bool {
+	return c == '{' || c == '}' || c == '(' || c == ')' || c == ';' || c == ','
+}
+
+// skipSpace advances past whitespace.
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && isSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+// done returns true if the whole input was consumed.
+func (l *lexer) done() bool {
+	l.skipSpace()
+	return l.pos >= len(l.input)
+}
+
+// peek returns the next token without consuming it. It returns an empty string at the end of the input.
+func (l *lexer) peek() string {
+	pos := l.pos
+	token := l.next()
+	l.pos = pos
+	return token
+}
+
+// next consumes and returns the next token. It returns an empty string at the end of the input.
+func (l *lexer) next() string {
+	l.skipSpace()
+	if l.pos >= len(l.input) {
+		return ""
+	}
+
+	start := l.pos
+	if isPunct(l.input[l.pos]) {
+		l.pos++
+		return l.input[start:l.pos]
+	}
+
+	for l.pos < len(l.input) && !isSpace(l.input[l.pos]) && !isPunct(l.input[l.pos]) {
+		l.pos++
+	}
+
+	return l.input[start:l.pos]
+}
+
+// peekKeyword returns true if the next token is the given keyword, ignoring case.
+func (l *lexer) peekKeyword(keyword string) bool {
+	return strings.EqualFold(l.peek(), keyword)
+}
+
+// acceptKeyword consumes the next token if it is the given keyword, ignoring case.
+func (l *lexer) acceptKeyword(keyword string) bool {
+	if l.peekKeyword(keyword) {
+		l.next()
+		return true
+	}
+
+	return false
+}
+
+// expect consumes the next token and returns an error if it is not the given keyword or punctuation.
+func (l *lexer) expect(token string) error {
+	pos := l.pos
+	if got := l.next(); !strings.EqualFold(got, token) {
+		l.pos = pos
+		return l.errorf("expected '%s'", token)
+	}
+
+	return nil
+}
+
+// filterEnd holds the keywords ending a filter, which start the next part of a structured policy.
+var filterEnd = []string{"except", "refine"}
+
+// actionEnd holds the keywords ending an action.
+var actionEnd = []string{"from", "to", "at", "accept", "announce", "networks", "except", "refine"}
+
+// readFilter consumes and returns the raw text of a filter. A filter ends at the end of the input, or at a ';', a
+// closing bracket or an EXCEPT or REFINE keyword that is not nested in brackets or in an AS path regular expression.
+func (l *lexer) readFilter() string {
+	return l.readRaw(filterEnd, true)
+}
+
+// readAction consumes and returns the raw text of a single action. An action ends at the end of the input, or at a
+// ';', a closing bracket or a keyword that is not nested in brackets.
+func (l *lexer) readAction() string {
+	return l.readRaw(actionEnd, false)
+}
+
+// readRaw consumes and returns the raw text up to the end of the input, or up to a ';', a closing bracket or one of
+// the keywords that is not nested in brackets. AS path regular expressions are skipped if regex is set.
+func (l *lexer) readRaw(keywords []string, regex bool) string {
+	l.skipSpace()
+	start := l.pos
+	depth := 0
+
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case c == '<' && regex:
+			if end := strings.IndexByte(l.input[l.pos:], '>'); end >= 0 {
+				l.pos += end + 1
+				continue
+			}
+		case c == '{' || c == '(':
+			depth++
+		case c == '}' || c == ')':
+			if depth == 0 {
+				return strings.TrimSpace(l.input[start:l.pos])
+			}
+
+			depth--
+		case c == ';' && depth == 0:
+			return strings.TrimSpace(l.input[start:l.pos])
+		case depth == 0 && (l.pos == start || isSpace(l.input[l.pos-1])):
+			pos := l.pos
+			word := l.next()
+			l.pos = pos
+			for _, keyword := range keywords {
+				if strings.EqualFold(word, keyword) {
+					return strings.TrimSpace(l.input[start:l.pos])
+				}
+			}
+		}
+
+		l.pos++
+	}
+
+	return strings.TrimSpace(l.input[start:l.pos])
+}
+
+// errorf returns an error located at the current position.
+func (l *lexer) errorf(format string, args ...any) error {
+	l.skipSpace()
+	found := "end of input"
+	if token := l.peek(); token != "" {
+		found = fmt.Sprintf("'%s'", token)
+	}
+
+	return fmt.Errorf("policy: %s but found %s at pos %d", fmt.Sprintf(format, args...), found, l.pos)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxFilterDepth is the maximum number of nested filter-sets followed when matching a filter.
+const maxFilterDepth = 32
+
+// Route is a route matched against filters.
+type Route struct {
+	Prefix netip.Prefix
+	// Path is the AS path, starting with the AS of the peer and ending with the origin AS.
+	Path []uint32
+	// Communities are the communities attached to the route.
+	Communities []Community
+	// PeerAS is the AS of the peer the route is exchanged with. When zero, the first AS of the path is used.
+	PeerAS uint32
+}
+
+// peerAS returns the AS of the peer, or zero if it is unknown.
+func (r *Route) peerAS() uint32 {
+	if r.PeerAS == 0 && len(r.Path) > 0 {
+		return r.Path[0]
+	}
+
+	return r.PeerAS
+}
+
+// Resolver looks up the contents of the sets referenced by filters, typically from an IRR database.
+type Resolver interface {
+	// ASNs returns the AS numbers of an as-set.
+	ASNs(name string) ([]uint32, error)
+	// Prefixes returns the prefix ranges of the routes denoted by an AS number, an as-set or a route-set. The routes
+	// of an AS number are the route and route6 objects it originates.
+	Prefixes(name string) ([]PrefixRange, error)
+	// Filter returns the filter of a filter-set.
+	Filter(name string) (*Filter, error)
+}
+
+// Match returns true if the route matches the Filter. Sets are looked up with resolver, which may be nil if the
+// Filter does not reference any set.
+func (f *Filter) Match(route *Route, resolver Resolver) (bool, error) {
+	return f.match(route, resolver, 0)
+}
+
+// match matches the route, following at most maxFilterDepth nested filter-sets.
+func (f *Filter) match(route *Route, resolver Resolver, depth int) (bool, error) {
+	switch f.Kind {
+	case FilterAny:
+		return true, nil
+	case FilterPeerAS:
+		peer := route.peerAS()
+		if peer == 0 {
+			return false, errors.New("policy: cannot match PeerAS without a peer AS")
+		}
+
+		return matchPrefixes(route, resolver, "AS"+strconv.FormatUint(uint64(peer), 10), f.Operator)
+	case FilterAS:
+		return matchPrefixes(route, resolver, f.Name, f.Operator)
+	case FilterSet:
+		return f.matchSet(route, resolver, depth)
+	case FilterPrefixes:
+		return containsRoute(f.Prefixes, f.Operator, route.Prefix), nil
+	case FilterPath:
+		return f.Path.Match(route.Path, route.peerAS(), resolver)
+	case FilterCommunity:
+		return f.Community.Match(route.Communities), nil
+	case FilterNot:
+		ok, err := f.Left.match(route, resolver, depth)
+		if err != nil {
+			return false, err
+		}
+
+		return !ok, nil
+	case FilterAnd, FilterOr:
+		ok, err := f.Left.match(route, resolver, depth)
+		if err != nil || ok == (f.Kind == FilterOr) {
+			return ok, err
+		}
+
+		return f.Right.match(route, resolver, depth)
+	}
+
+	return false, fmt.Errorf("policy: invalid filter kind %d", f.Kind)
+}
+
+// matchSet matches the route against an as-set, a route-set or a filter-set.
+func (f *Filter) matchSet(route *Route, resolver Resolver, depth int) (bool, error) {
+	upper := strings.ToUpper(f.Name)
+	if upper == "AS-ANY" || upper == "RS-ANY" {
+		return true, nil
+	}
+
+	if !strings.HasPrefix(upper, "FLTR-") && !strings.Contains(upper, ":FLTR-") {
+		return matchPrefixes(route, resolver, f.Name, f.Operator)
+	}
+
+	if resolver == nil {
+		return false, fmt.Errorf("policy: cannot resolve '%s' without a resolver", f.Name)
+	}
+
+	if depth >= maxFilterDepth {
+		return false, fmt.Errorf("policy: too many nested filter-sets in '%s'", f.Name)
+	}
+
+	filter, err := resolver.Filter(f.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return filter.match(route, resolver, depth+1)
+}
+
+// matchPrefixes returns true if the route is in the prefix ranges of the named AS or set, after applying op.
+func matchPrefixes(route *Route, resolver Resolver, name string, op RangeOperator) (bool, error) {
+	if resolver == nil {
+		return false, fmt.Errorf("policy: cannot resolve '%s' without a resolver", name)
+	}
+
+	prefixes, err := resolver.Prefixes(name)
+	if err != nil {
+		return false, err
+	}
+
+	return containsRoute(prefixes, op, route.Prefix), nil
+}
+
+// containsRoute returns true if the prefix is in any of the ranges, after applying op.
+func containsRoute(ranges []PrefixRange, op RangeOperator, prefix netip.Prefix) bool {
+	prefix = prefix.Masked()
+	for _, r := range ranges {
+		if r, ok := op.Apply(r); ok && r.Contains(prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match returns true if the communities match the CommunityFilter.
+func (cf *CommunityFilter) Match(communities []Community) bool {
+	for _, c := range cf.Communities {
+		if !slices.Contains(communities, c) {
+			return false
+		}
+	}
+
+	if cf.Method != "==" {
+		return true
+	}
+
+	for _, c := range communities {
+		if !slices.Contains(cf.Communities, c) {
+			return false
+		}
+	}
+
+	return true
+}
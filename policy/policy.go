@@ -0,0 +1,630 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package policy parses the routing policies of RPSL aut-num objects, as defined by RFC 2622 section 6 and extended
+// to multiple address families by RFC 4012, and the filters they accept or announce, as defined by RFC 2622
+// section 5.4. Filters can be matched against routes, looking up the sets they reference with a Resolver.
+//
+// Example:
+//
+//	p, err := policy.ParseImport("from AS64496 action pref = 100; accept AS64496")
+//	if err != nil {
+//	    log.Fatalf("Failed to parse policy: %v", err)
+//	}
+//
+//	for _, factor := range p.Expression.Term.Factors {
+//	    fmt.Printf("Filter: %s\n", factor.Filter.String())
+//	}
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator combines the terms of a structured policy expression.
+type Operator uint8
+
+const (
+	// None marks the last term of an expression.
+	None Operator = iota
+	// Except applies the next expression to the routes it matches, overriding the term.
+	Except
+	// Refine applies the next expression to the routes matched by the term, as a logical AND.
+	Refine
+)
+
+// String returns the keyword of the Operator.
+func (o Operator) String() string {
+	switch o {
+	case Except:
+		return "EXCEPT"
+	case Refine:
+		return "REFINE"
+	default:
+		return ""
+	}
+}
+
+// Policy is the value of an import, export, mp-import or mp-export attribute.
+type Policy struct {
+	// Protocol is the protocol the routes are exchanged with, such as "BGP4". Empty when not specified.
+	Protocol string
+	// Into is the protocol the routes are imported into or exported from. Empty when not specified.
+	Into string
+	// Expression is the policy expression.
+	Expression *Expression
+
+	export bool // Whether the policy is an export policy, for String.
+}
+
+// Expression is a policy expression: a term, optionally combined with the next expression by an EXCEPT or REFINE
+// operator.
+type Expression struct {
+	// AFIs is the list of address families the expression applies to, such as "ipv4.unicast" or "any". Only set in
+	// mp-import and mp-export attributes, empty when not specified.
+	AFIs []string
+	// Term is the first term of the expression.
+	Term *Term
+	// Operator combines Term with Next.
+	Operator Operator
+	// Next is the expression following the operator, nil if Operator is None.
+	Next *Expression
+}
+
+// Term is a sequence of factors, enclosed in braces when there are more than one.
+type Term struct {
+	Factors []*Factor
+	// Braced is true if the term was enclosed in braces.
+	Braced bool
+}
+
+// Factor is a list of peerings with their actions, followed by the filter selecting the routes.
+type Factor struct {
+	Peerings []*PeeringAction
+	// Filter is the accept or announce filter.
+	Filter *Filter
+}
+
+// PeeringAction is a peering and the actions applied to the routes exchanged with it.
+type PeeringAction struct {
+	Peering *Peering
+	Actions []*Action
+}
+
+// Peering identifies a set of BGP sessions, either by name or by AS and router expressions.
+type Peering struct {
+	// Set is the name of a peering-set. When set, the other fields are nil.
+	Set string
+	// AS is the AS expression of the remote ASes.
+	AS *SetExpr
+	// Remote is the router expression of the remote routers, nil when not specified.
+	Remote *SetExpr
+	// Local is the router expression of the local routers, following the "at" keyword, nil when not specified.
+	Local *SetExpr
+}
+
+// SetExpr is an expression combining names of ASes, routers or sets with the AND, OR and EXCEPT operators.
+type SetExpr struct {
+	// Operator is "AND", "OR" or "EXCEPT", or empty for a name.
+	Operator string
+	// Left and Right are the operands of the Operator.
+	Left, Right *SetExpr
+	// Name is the AS number, set name, router name or IP address when Operator is empty.
+	Name string
+}
+
+// Action sets or modifies a route attribute, such as "pref = 100" or "community.append(64496:1)".
+type Action struct {
+	// Attribute is the route attribute, such as "pref" or "community".
+	Attribute string
+	// Method is the method called on the attribute, such as "append". Empty when an operator is used.
+	Method string
+	// Operator is the operator applied to the attribute, such as "=" or ".=". Empty when a method is called.
+	Operator string
+	// Args holds the arguments of the method, or the single operand of the operator.
+	Args []string
+}
+
+// Default is the value of a default or mp-default attribute.
+type Default struct {
+	// AFIs is the list of address families the default applies to. Only set in mp-default attributes, empty when not
+	// specified.
+	AFIs []string
+	// Peering is the peering the default routes are received from, with its actions.
+	Peering *PeeringAction
+	// Networks is the filter selecting the default routes, nil when not specified.
+	Networks *Filter
+}
+
+// ParseImport parses the value of an import attribute.
+func ParseImport(value string) (*Policy, error) {
+	return parsePolicy(value, "from", "accept", false)
+}
+
+// ParseMPImport parses the value of an mp-import attribute.
+func ParseMPImport(value string) (*Policy, error) {
+	return parsePolicy(value, "from", "accept", true)
+}
+
+// ParseExport parses the value of an export attribute.
+func ParseExport(value string) (*Policy, error) {
+	return parsePolicy(value, "to", "announce", false)
+}
+
+// ParseMPExport parses the value of an mp-export attribute.
+func ParseMPExport(value string) (*Policy, error) {
+	return parsePolicy(value, "to", "announce", true)
+}
+
+// ParseDefault parses the value of a default attribute.
+func ParseDefault(value string) (*Default, error) {
+	return parseDefault(value, false)
+}
+
+// ParseMPDefault parses the value of an mp-default attribute.
+func ParseMPDefault(value string) (*Default, error) {
+	return parseDefault(value, true)
+}
+
+// parser parses policies with the keywords of import or export attributes.
+type parser struct {
+	lexer
+	direction string // "from" or "to"
+	filter    string // "accept" or "announce"
+	mp        bool   // Whether afi lists are allowed.
+}
+
+// parsePolicy parses an import or export policy.
+func parsePolicy(value string, direction string, filter string, mp bool) (*Policy, error) {
+	p := &parser{lexer: lexer{input: value}, direction: direction, filter: filter, mp: mp}
+	policy := &Policy{export: direction == "to"}
+
+	if p.acceptKeyword("protocol") {
+		if policy.Protocol = p.next(); policy.Protocol == "" {
+			return nil, p.errorf("expected a protocol name")
+		}
+	}
+
+	if p.acceptKeyword("into") {
+		if policy.Into = p.next(); policy.Into == "" {
+			return nil, p.errorf("expected a protocol name")
+		}
+	}
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.acceptKeyword(";")
+	if !p.done() {
+		return nil, p.errorf("expected end of policy")
+	}
+
+	policy.Expression = expr
+	return policy, nil
+}
+
+// parseExpression parses a policy expression.
+func (p *parser) parseExpression() (*Expression, error) {
+	expr := &Expression{}
+
+	afis, err := p.parseAFIs()
+	if err != nil {
+		return nil, err
+	}
+
+	expr.AFIs = afis
+	if expr.Term, err = p.parseTerm(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.acceptKeyword("except"):
+		expr.Operator = Except
+	case p.acceptKeyword("refine"):
+		expr.Operator = Refine
+	default:
+		return expr, nil
+	}
+
+	if expr.Next, err = p.parseExpression(); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// parseAFIs parses an optional afi list.
+func (p *parser) parseAFIs() ([]string, error) {
+	if !p.mp || !p.acceptKeyword("afi") {
+		return nil, nil
+	}
+
+	afis := make([]string, 0, 2)
+	for {
+		afi := p.next()
+		if afi == "" || isPunct(afi[0]) {
+			return nil, p.errorf("expected an address family")
+		}
+
+		afis = append(afis, strings.ToLower(afi))
+		if p.peek() != "," {
+			return afis, nil
+		}
+
+		p.next()
+	}
+}
+
+// parseTerm parses a single factor, or a list of factors enclosed in braces.
+func (p *parser) parseTerm() (*Term, error) {
+	if p.peek() != "{" {
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Term{Factors: []*Factor{factor}}, nil
+	}
+
+	p.next()
+	term := &Term{Braced: true}
+	for p.peek() != "}" {
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		term.Factors = append(term.Factors, factor)
+
+		// The ';' after the last factor is optional.
+		if p.peek() == "}" {
+			break
+		}
+
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+	}
+
+	p.next()
+	if len(term.Factors) == 0 {
+		return nil, p.errorf("expected at least one policy factor")
+	}
+
+	return term, nil
+}
+
+// parseFactor parses the peerings of a factor, followed by its filter.
+func (p *parser) parseFactor() (*Factor, error) {
+	factor := &Factor{}
+	for p.acceptKeyword(p.direction) {
+		peering, err := p.parsePeeringAction()
+		if err != nil {
+			return nil, err
+		}
+
+		factor.Peerings = append(factor.Peerings, peering)
+	}
+
+	if len(factor.Peerings) == 0 {
+		return nil, p.errorf("expected '%s'", p.direction)
+	}
+
+	if err := p.expect(p.filter); err != nil {
+		return nil, err
+	}
+
+	filter, err := p.parseFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	factor.Filter = filter
+	return factor, nil
+}
+
+// parseFilter parses the filter of a factor or a default, up to the end of the policy or to the keyword following
+// it.
+func (p *parser) parseFilter() (*Filter, error) {
+	text := p.readFilter()
+	if text == "" {
+		return nil, p.errorf("expected a filter")
+	}
+
+	f, err := ParseFilter(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w in filter '%s'", err, text)
+	}
+
+	return f, nil
+}
+
+// parsePeeringAction parses a peering followed by its optional actions.
+func (l *lexer) parsePeeringAction() (*PeeringAction, error) {
+	peering, err := l.parsePeering()
+	if err != nil {
+		return nil, err
+	}
+
+	pa := &PeeringAction{Peering: peering}
+	if !l.acceptKeyword("action") {
+		return pa, nil
+	}
+
+	for {
+		text := l.readAction()
+		if text == "" {
+			break
+		}
+
+		action, err := parseAction(text)
+		if err != nil {
+			return nil, err
+		}
+
+		pa.Actions = append(pa.Actions, action)
+		if l.peek() != ";" {
+			break
+		}
+
+		l.next()
+	}
+
+	if len(pa.Actions) == 0 {
+		return nil, l.errorf("expected an action")
+	}
+
+	return pa, nil
+}
+
+// parsePeering parses a peering-set name, or an AS expression followed by optional router expressions.
+func (l *lexer) parsePeering() (*Peering, error) {
+	if name := l.peek(); isPeeringSet(name) {
+		l.next()
+		return &Peering{Set: name}, nil
+	}
+
+	as, err := l.parseSetExpr(isASOperand)
+	if err != nil {
+		return nil, err
+	}
+
+	peering := &Peering{AS: as}
+	if isRouterOperand(l.peek()) {
+		if peering.Remote, err = l.parseSetExpr(isRouterOperand); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.acceptKeyword("at") {
+		if peering.Local, err = l.parseSetExpr(isRouterOperand); err != nil {
+			return nil, err
+		}
+	}
+
+	return peering, nil
+}
+
+// parseSetExpr parses an expression of names accepted by operand, combined with AND, OR and EXCEPT. OR has the lowest
+// precedence, AND and EXCEPT are left-associative.
+func (l *lexer) parseSetExpr(operand func(string) bool) (*SetExpr, error) {
+	left, err := l.parseSetTerm(operand)
+	if err != nil {
+		return nil, err
+	}
+
+	for l.acceptKeyword("or") {
+		right, err := l.parseSetTerm(operand)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &SetExpr{Operator: "OR", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseSetTerm parses operands combined with AND and EXCEPT.
+func (l *lexer) parseSetTerm(operand func(string) bool) (*SetExpr, error) {
+	left, err := l.parseSetFactor(operand)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+		switch {
+		case l.acceptKeyword("and"):
+			op = "AND"
+		case l.acceptKeyword("except"):
+			op = "EXCEPT"
+		default:
+			return left, nil
+		}
+
+		right, err := l.parseSetFactor(operand)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &SetExpr{Operator: op, Left: left, Right: right}
+	}
+}
+
+// parseSetFactor parses a single operand or a parenthesized expression.
+func (l *lexer) parseSetFactor(operand func(string) bool) (*SetExpr, error) {
+	if l.peek() == "(" {
+		l.next()
+		expr, err := l.parseSetExpr(operand)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := l.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+	}
+
+	name := l.peek()
+	if !operand(name) {
+		return nil, l.errorf("expected a name")
+	}
+
+	l.next()
+	return &SetExpr{Name: name}, nil
+}
+
+// keywords holds the reserved words of policy expressions.
+var keywords = map[string]struct{}{
+	"accept": {}, "action": {}, "afi": {}, "and": {}, "announce": {}, "at": {}, "except": {}, "from": {}, "into": {},
+	"networks": {}, "not": {}, "or": {}, "protocol": {}, "refine": {}, "to": {},
+}
+
+// isKeyword returns true if name is a reserved word.
+func isKeyword(name string) bool {
+	_, ok := keywords[strings.ToLower(name)]
+	return ok
+}
+
+// isASOperand returns true if name is an AS number or an as-set name, including AS-ANY.
+func isASOperand(name string) bool {
+	upper := strings.ToUpper(name)
+	if name == "" || isKeyword(name) || isPunct(name[0]) {
+		return false
+	}
+
+	if len(upper) > 2 && upper[:2] == "AS" && upper[2] >= '0' && upper[2] <= '9' && !strings.Contains(upper, ":") {
+		return true
+	}
+
+	return strings.HasPrefix(upper, "AS-") || strings.Contains(upper, ":AS-")
+}
+
+// isPeeringSet returns true if name is a peering-set name.
+func isPeeringSet(name string) bool {
+	upper := strings.ToUpper(name)
+	return strings.HasPrefix(upper, "PRNG-") || strings.Contains(upper, ":PRNG-")
+}
+
+// isRouterOperand returns true if name can be a router name, an IP address or an rtr-set name.
+func isRouterOperand(name string) bool {
+	return name != "" && !isKeyword(name) && !isPunct(name[0]) && !isASOperand(name)
+}
+
+// parseAction parses a single action.
+func parseAction(text string) (*Action, error) {
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	})
+
+	if end <= 0 {
+		return nil, invalidAction(text)
+	}
+
+	action := &Action{Attribute: strings.ToLower(text[:end])}
+	rest := strings.TrimSpace(text[end:])
+
+	// Method call, such as community.append(64496:1).
+	if strings.HasPrefix(rest, ".") && strings.HasSuffix(rest, ")") {
+		open := strings.IndexByte(rest, '(')
+		if open < 0 {
+			return nil, invalidAction(text)
+		}
+
+		action.Method = strings.TrimSpace(rest[1:open])
+		if action.Method == "" {
+			return nil, invalidAction(text)
+		}
+
+		action.Args = splitArgs(rest[open+1 : len(rest)-1])
+		return action, nil
+	}
+
+	// Operator, such as pref = 100.
+	op := strings.IndexFunc(rest, func(r rune) bool { return !strings.ContainsRune("=<>!+-*/.", r) })
+	if op <= 0 {
+		return nil, invalidAction(text)
+	}
+
+	action.Operator = rest[:op]
+	value := strings.TrimSpace(rest[op:])
+	if value == "" {
+		return nil, invalidAction(text)
+	}
+
+	action.Args = []string{value}
+	return action, nil
+}
+
+// splitArgs splits a comma-separated list of arguments, ignoring commas nested in brackets.
+func splitArgs(text string) []string {
+	args := make([]string, 0, 2)
+	depth := 0
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{', '(':
+			depth++
+		case '}', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(text[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	if last := strings.TrimSpace(text[start:]); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+
+	return args
+}
+
+// invalidAction returns an error for an action that could not be parsed.
+func invalidAction(text string) error {
+	return fmt.Errorf("policy: invalid action '%s'", text)
+}
+
+// parseDefault parses a default or mp-default attribute.
+func parseDefault(value string, mp bool) (*Default, error) {
+	p := &parser{lexer: lexer{input: value}, direction: "to", mp: mp}
+
+	afis, err := p.parseAFIs()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect("to"); err != nil {
+		return nil, err
+	}
+
+	peering, err := p.parsePeeringAction()
+	if err != nil {
+		return nil, err
+	}
+
+	def := &Default{AFIs: afis, Peering: peering}
+	if p.acceptKeyword("networks") {
+		networks, err := p.parseFilter()
+		if err != nil {
+			return nil, err
+		}
+
+		def.Networks = networks
+	}
+
+	p.acceptKeyword(";")
+	if !p.done() {
+		return nil, p.errorf("expected end of policy")
+	}
+
+	return def, nil
+}
@@ -0,0 +1,172 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImport(t *testing.T) {
+	p, err := ParseImport("protocol BGP4 into OSPF from AS64496 192.0.2.1 at 192.0.2.2 " +
+		"action pref = 100; community.append(64496:1, 64496:2); accept { 192.0.2.0/24^+ } AND NOT AS-FOO")
+	if err != nil {
+		t.Fatalf(`ParseImport => %v`, err)
+	}
+
+	if p.Protocol != "BGP4" || p.Into != "OSPF" {
+		t.Fatalf(`ParseImport => protocol %q into %q, want "BGP4" into "OSPF"`, p.Protocol, p.Into)
+	}
+
+	factors := p.Expression.Term.Factors
+	if len(factors) != 1 || len(factors[0].Peerings) != 1 {
+		t.Fatalf(`ParseImport => %d factors, want 1 with 1 peering`, len(factors))
+	}
+
+	if factors[0].Filter.String() != "{ 192.0.2.0/24^+ } AND NOT AS-FOO" {
+		t.Errorf(`Factor.Filter => %q, want %q`, factors[0].Filter.String(), "{ 192.0.2.0/24^+ } AND NOT AS-FOO")
+	}
+
+	pa := factors[0].Peerings[0]
+	want := &Peering{
+		AS:     &SetExpr{Name: "AS64496"},
+		Remote: &SetExpr{Name: "192.0.2.1"},
+		Local:  &SetExpr{Name: "192.0.2.2"},
+	}
+	if !reflect.DeepEqual(pa.Peering, want) {
+		t.Errorf(`PeeringAction.Peering => %v, want %v`, pa.Peering, want)
+	}
+
+	actions := []*Action{
+		{Attribute: "pref", Operator: "=", Args: []string{"100"}},
+		{Attribute: "community", Method: "append", Args: []string{"64496:1", "64496:2"}},
+	}
+	if !reflect.DeepEqual(pa.Actions, actions) {
+		t.Errorf(`PeeringAction.Actions => %v, want %v`, pa.Actions, actions)
+	}
+}
+
+func TestParseStructured(t *testing.T) {
+	p, err := ParseImport("{ from AS64496 accept ANY; from AS64497 accept <^AS64497+$>; }\n" +
+		"refine { from AS-ANY accept NOT { 0.0.0.0/0 } }\n" +
+		"except from AS64498 accept AS64498")
+	if err != nil {
+		t.Fatalf(`ParseImport => %v`, err)
+	}
+
+	expr := p.Expression
+	if !expr.Term.Braced || len(expr.Term.Factors) != 2 || expr.Operator != Refine {
+		t.Fatalf(`ParseImport => %d factors and %v, want 2 braced factors and REFINE`, len(expr.Term.Factors), expr.Operator)
+	}
+
+	if expr.Term.Factors[1].Filter.String() != "<^AS64497+$>" {
+		t.Errorf(`Factor.Filter => %q, want %q`, expr.Term.Factors[1].Filter.String(), "<^AS64497+$>")
+	}
+
+	next := expr.Next
+	if next.Operator != Except || next.Term.Factors[0].Filter.String() != "NOT { 0.0.0.0/0 }" {
+		t.Errorf(`Expression.Next => %v %q, want EXCEPT "NOT { 0.0.0.0/0 }"`, next.Operator, next.Term.Factors[0].Filter.String())
+	}
+
+	if last := next.Next; last == nil || last.Operator != None || last.Term.Factors[0].Filter.String() != "AS64498" {
+		t.Errorf(`Expression.Next.Next => %v, want a last term accepting AS64498`, last)
+	}
+}
+
+func TestParseSetExpr(t *testing.T) {
+	p, err := ParseExport("to AS64496 OR AS64497 AND (AS-FOO EXCEPT AS64498) announce AS64499")
+	if err != nil {
+		t.Fatalf(`ParseExport => %v`, err)
+	}
+
+	as := p.Expression.Term.Factors[0].Peerings[0].Peering.AS
+	if got, want := as.String(), "AS64496 OR (AS64497 AND (AS-FOO EXCEPT AS64498))"; got != want {
+		t.Errorf(`SetExpr.String() => %q, want %q`, got, want)
+	}
+}
+
+func TestParseMPImport(t *testing.T) {
+	p, err := ParseMPImport("afi ipv6.unicast, IPv4.Multicast from AS64496 accept AS64496")
+	if err != nil {
+		t.Fatalf(`ParseMPImport => %v`, err)
+	}
+
+	if want := []string{"ipv6.unicast", "ipv4.multicast"}; !reflect.DeepEqual(p.Expression.AFIs, want) {
+		t.Errorf(`Expression.AFIs => %v, want %v`, p.Expression.AFIs, want)
+	}
+
+	if _, err := ParseImport("afi ipv6 from AS64496 accept AS64496"); err == nil {
+		t.Errorf(`ParseImport => nil, want an error for an afi list`)
+	}
+}
+
+func TestParseDefault(t *testing.T) {
+	d, err := ParseMPDefault("afi ipv6 to AS64496 action pref = 10; networks ANY")
+	if err != nil {
+		t.Fatalf(`ParseMPDefault => %v`, err)
+	}
+
+	if d.Peering.Peering.AS.Name != "AS64496" || len(d.Peering.Actions) != 1 || d.Networks.String() != "ANY" {
+		t.Errorf(`ParseMPDefault => %v, want a default to AS64496 with 1 action`, d)
+	}
+
+	if _, err := ParseDefault("to AS64496 networks NOT"); err == nil {
+		t.Errorf(`ParseDefault("to AS64496 networks NOT") => nil, want an error`)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"from AS64496 accept ANY", "from AS64496 accept ANY"},
+		{"from PRNG-FOO action pref=10 accept AS-FOO;", "from PRNG-FOO action pref = 10; accept AS-FOO"},
+		{"{from AS1 accept AS1;from AS2 accept AS2} except from AS3 accept AS3",
+			"{ from AS1 accept AS1; from AS2 accept AS2; } EXCEPT from AS3 accept AS3"},
+	}
+
+	for _, tt := range tests {
+		p, err := ParseImport(tt.value)
+		if err != nil {
+			t.Fatalf(`ParseImport(%q) => %v`, tt.value, err)
+		}
+
+		if got := p.String(); got != tt.want {
+			t.Errorf(`Policy.String() => %q, want %q`, got, tt.want)
+		}
+
+		again, err := ParseImport(p.String())
+		if err != nil || again.String() != tt.want {
+			t.Errorf(`ParseImport(Policy.String()) => %v, %v, want a round-trip`, again, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "expected 'from'"},
+		{"from", "expected a name"},
+		{"from AS64496", "expected 'accept'"},
+		{"from AS64496 accept", "expected a filter"},
+		{"from AS64496 announce ANY", "expected 'accept'"},
+		{"from AS64496 action accept ANY", "expected an action"},
+		{"from AS64496 action foo accept ANY", "invalid action 'foo'"},
+		{"{ }", "expected at least one policy factor"},
+		{"from AS64496 accept ANY }", "expected end of policy"},
+		{"from AS64496 accept AS64496 AND", "in filter 'AS64496 AND'"},
+		{"from AS64496 accept { 192.0.2.1/24 }", "in filter '{ 192.0.2.1/24 }'"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseImport(tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf(`ParseImport(%q) => %v, want %q`, tt.value, err, tt.want)
+		}
+	}
+}
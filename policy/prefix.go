@@ -0,0 +1,311 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// RangeKind is the kind of a RangeOperator.
+type RangeKind uint8
+
+const (
+	// NoRange matches the prefix itself.
+	NoRange RangeKind = iota
+	// Exclusive is the "^-" operator, matching the more specifics of the prefix, excluding the prefix itself.
+	Exclusive
+	// Inclusive is the "^+" operator, matching the more specifics of the prefix, including the prefix itself.
+	Inclusive
+	// Length is the "^n" or "^n-m" operator, matching the more specifics of length n to m.
+	Length
+)
+
+// RangeOperator is an address prefix range operator, as defined by RFC 2622 section 2. The zero value is no operator.
+type RangeOperator struct {
+	Kind RangeKind
+	// Min and Max are the lengths of a Length operator. Min equals Max for "^n".
+	Min, Max int
+}
+
+// ParseRangeOperator parses a range operator such as "^+", "^-", "^24" or "^24-32". An empty string is no operator.
+func ParseRangeOperator(value string) (RangeOperator, error) {
+	if value == "" {
+		return RangeOperator{}, nil
+	}
+
+	operator, found := strings.CutPrefix(value, "^")
+	if !found {
+		return RangeOperator{}, fmt.Errorf("policy: invalid range operator '%s'", value)
+	}
+
+	switch operator {
+	case "-":
+		return RangeOperator{Kind: Exclusive}, nil
+	case "+":
+		return RangeOperator{Kind: Inclusive}, nil
+	}
+
+	low, high, found := strings.Cut(operator, "-")
+	if !found {
+		high = low
+	}
+
+	lo, err := strconv.ParseUint(low, 10, 8)
+	if err != nil || low[0] == '+' {
+		return RangeOperator{}, fmt.Errorf("policy: invalid range operator '%s'", value)
+	}
+
+	hi, err := strconv.ParseUint(high, 10, 8)
+	if err != nil || high[0] == '+' || hi < lo || hi > 128 {
+		return RangeOperator{}, fmt.Errorf("policy: invalid range operator '%s'", value)
+	}
+
+	return RangeOperator{Kind: Length, Min: int(lo), Max: int(hi)}, nil
+}
+
+// String returns the text of the RangeOperator, including its leading '^', or an empty string for no operator.
+func (op RangeOperator) String() string {
+	switch op.Kind {
+	case Exclusive:
+		return "^-"
+	case Inclusive:
+		return "^+"
+	case Length:
+		if op.Min == op.Max {
+			return "^" + strconv.Itoa(op.Min)
+		}
+
+		return "^" + strconv.Itoa(op.Min) + "-" + strconv.Itoa(op.Max)
+	default:
+		return ""
+	}
+}
+
+// Apply applies the operator to a prefix range, as done for the members of a set followed by an operator. "^-" and
+// "^+" extend the range to every more specific of its shortest prefixes, and "^n-m" restricts it to the lengths n to
+// m, not shorter than its shortest prefixes. It returns false if the resulting range is empty.
+//
+// For example, "^16-24" applied to "5.0.0.0/8^+" gives "5.0.0.0/8^16-24", and applied to "30.0.0.0/8^24-32" gives
+// "30.0.0.0/8^24".
+func (op RangeOperator) Apply(r PrefixRange) (PrefixRange, bool) {
+	bits := r.Prefix.Addr().BitLen()
+	switch op.Kind {
+	case Exclusive:
+		r.Min, r.Max = r.Min+1, bits
+	case Inclusive:
+		r.Max = bits
+	case Length:
+		r.Min, r.Max = max(op.Min, r.Min), op.Max
+	}
+
+	if r.Min > r.Max || r.Max > bits {
+		return PrefixRange{}, false
+	}
+
+	return r, true
+}
+
+// ApplyAll applies the operator to every prefix range of a set, as done for a set name followed by an operator such as
+// "RS-FOO^24". The ranges that become empty are dropped.
+func (op RangeOperator) ApplyAll(ranges []PrefixRange) []PrefixRange {
+	applied := make([]PrefixRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r, ok := op.Apply(r); ok {
+			applied = append(applied, r)
+		}
+	}
+
+	return applied
+}
+
+// CutRangeOperator splits a name or a prefix followed by an optional range operator, such as "RS-FOO^24" or
+// "AS-BAR^+", and returns the name and the parsed operator.
+func CutRangeOperator(value string) (string, RangeOperator, error) {
+	name, operator, found := strings.Cut(value, "^")
+	if !found {
+		return name, RangeOperator{}, nil
+	}
+
+	op, err := ParseRangeOperator("^" + operator)
+	if err != nil {
+		return "", RangeOperator{}, err
+	}
+
+	return name, op, nil
+}
+
+// PrefixRange is an address prefix with the range of lengths of its more specifics, such as "192.0.2.0/24^+".
+type PrefixRange struct {
+	Prefix netip.Prefix
+	// Min and Max are the lengths of the prefixes in the range. They both equal the prefix length without operator.
+	Min, Max int
+}
+
+// ParsePrefixRange parses an IPv4 or IPv6 prefix followed by an optional range operator, such as "192.0.2.0/24",
+// "192.0.2.0/24^+" or "2001:db8::/32^48-64".
+func ParsePrefixRange(value string) (PrefixRange, error) {
+	text, op, err := CutRangeOperator(value)
+	if err != nil {
+		return PrefixRange{}, err
+	}
+
+	prefix, err := netip.ParsePrefix(text)
+	if err != nil || prefix.Masked() != prefix {
+		return PrefixRange{}, fmt.Errorf("policy: invalid prefix '%s'", value)
+	}
+
+	r := PrefixRange{Prefix: prefix, Min: prefix.Bits(), Max: prefix.Bits()}
+
+	// The lengths of an explicit operator cannot be shorter than the prefix.
+	if op.Kind == Length && op.Min < prefix.Bits() {
+		return PrefixRange{}, fmt.Errorf("policy: range operator shorter than the prefix in '%s'", value)
+	}
+
+	if r, ok := op.Apply(r); ok {
+		return r, nil
+	}
+
+	return PrefixRange{}, fmt.Errorf("policy: invalid range operator in '%s'", value)
+}
+
+// Contains returns true if the prefix is in the range: it is covered by the range prefix and its length is between
+// Min and Max.
+func (r PrefixRange) Contains(prefix netip.Prefix) bool {
+	return prefix.Bits() >= r.Min && prefix.Bits() <= r.Max && r.Prefix.Bits() <= prefix.Bits() &&
+		r.Prefix.Contains(prefix.Addr())
+}
+
+// Operator returns the range operator describing the lengths of the range, relative to its prefix.
+func (r PrefixRange) Operator() RangeOperator {
+	bits := r.Prefix.Bits()
+	switch {
+	case r.Min == bits && r.Max == bits:
+		return RangeOperator{}
+	case r.Max == r.Prefix.Addr().BitLen() && r.Min == bits:
+		return RangeOperator{Kind: Inclusive}
+	case r.Max == r.Prefix.Addr().BitLen() && r.Min == bits+1:
+		return RangeOperator{Kind: Exclusive}
+	default:
+		return RangeOperator{Kind: Length, Min: r.Min, Max: r.Max}
+	}
+}
+
+// String returns a string representation of the PrefixRange, with the shortest operator describing its lengths.
+func (r PrefixRange) String() string {
+	return r.Prefix.String() + r.Operator().String()
+}
+
+// Overlaps returns true if some prefixes are in both ranges.
+func (r PrefixRange) Overlaps(o PrefixRange) bool {
+	_, ok := r.Intersect(o)
+	return ok
+}
+
+// Intersect returns the prefixes that are in both ranges. It returns false if there are none, including when the
+// ranges are of different address families.
+func (r PrefixRange) Intersect(o PrefixRange) (PrefixRange, bool) {
+	if !r.Prefix.Overlaps(o.Prefix) {
+		return PrefixRange{}, false
+	}
+
+	// The prefixes overlap, so the longest is covered by the other.
+	prefix := r.Prefix
+	if o.Prefix.Bits() > prefix.Bits() {
+		prefix = o.Prefix
+	}
+
+	i := PrefixRange{Prefix: prefix, Min: max(r.Min, o.Min, prefix.Bits()), Max: min(r.Max, o.Max)}
+	if i.Min > i.Max {
+		return PrefixRange{}, false
+	}
+
+	return i, true
+}
+
+// Len returns the number of prefixes in the range, saturated at math.MaxUint64.
+func (r PrefixRange) Len() uint64 {
+	var total uint64
+	for length := r.Min; length <= r.Max; length++ {
+		shift := length - r.Prefix.Bits()
+		if shift >= 64 || total+1<<shift < total {
+			return math.MaxUint64
+		}
+
+		total += 1 << shift
+	}
+
+	return total
+}
+
+// Expand returns every prefix in the range, shortest first and in address order. It returns an error if the range
+// holds more than limit prefixes.
+//
+// For example, "192.0.2.0/24^25-26" expands to 192.0.2.0/25, 192.0.2.128/25, 192.0.2.0/26, 192.0.2.64/26,
+// 192.0.2.128/26 and 192.0.2.192/26.
+func (r PrefixRange) Expand(limit int) ([]netip.Prefix, error) {
+	count := r.Len()
+	if limit < 0 || count > uint64(limit) {
+		return nil, fmt.Errorf("policy: %s holds more than %d prefixes", r, limit)
+	}
+
+	prefixes := make([]netip.Prefix, 0, count)
+	for length := r.Min; length <= r.Max; length++ {
+		prefix := netip.PrefixFrom(r.Prefix.Addr(), length)
+		for {
+			prefixes = append(prefixes, prefix)
+			next, ok := nextPrefix(prefix)
+			if !ok || !r.Prefix.Contains(next.Addr()) {
+				break
+			}
+
+			prefix = next
+		}
+	}
+
+	return prefixes, nil
+}
+
+// nextPrefix returns the prefix of the same length following p. It returns false if p is the last prefix of its
+// address family, which is always the case of a /0.
+func nextPrefix(p netip.Prefix) (netip.Prefix, bool) {
+	if p.Bits() == 0 {
+		return netip.Prefix{}, false
+	}
+
+	addr := p.Addr().As16()
+	bits := p.Bits()
+	if p.Addr().Is4() {
+		bits += 96
+	}
+
+	// Add one at the last bit of the prefix, carrying over to the previous bytes.
+	i := (bits - 1) / 8
+	carry := byte(1) << (7 - (bits-1)%8)
+	for ; i >= 0 && carry != 0; i-- {
+		sum := addr[i] + carry
+		carry = 0
+		if sum < addr[i] {
+			carry = 1
+		}
+
+		addr[i] = sum
+	}
+
+	next := netip.AddrFrom16(addr)
+	if p.Addr().Is4() {
+		if carry != 0 || i < 11 {
+			return netip.Prefix{}, false
+		}
+
+		next = next.Unmap()
+	} else if carry != 0 {
+		return netip.Prefix{}, false
+	}
+
+	return netip.PrefixFrom(next, p.Bits()), true
+}
@@ -0,0 +1,195 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParsePrefixRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max int
+		want     string
+	}{
+		{"192.0.2.0/24", 24, 24, "192.0.2.0/24"},
+		{"192.0.2.0/24^-", 25, 32, "192.0.2.0/24^-"},
+		{"192.0.2.0/24^+", 24, 32, "192.0.2.0/24^+"},
+		{"192.0.2.0/24^26", 26, 26, "192.0.2.0/24^26"},
+		{"192.0.2.0/24^24-32", 24, 32, "192.0.2.0/24^+"},
+		{"192.0.2.0/24^25-32", 25, 32, "192.0.2.0/24^-"},
+		{"192.0.2.0/24^24", 24, 24, "192.0.2.0/24"},
+		{"2001:db8::/32^48-64", 48, 64, "2001:db8::/32^48-64"},
+		{"2001:db8::/32^+", 32, 128, "2001:db8::/32^+"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParsePrefixRange(tt.value)
+		if err != nil {
+			t.Errorf(`ParsePrefixRange(%q) => %v`, tt.value, err)
+			continue
+		}
+
+		if r.Min != tt.min || r.Max != tt.max {
+			t.Errorf(`ParsePrefixRange(%q) => lengths %d-%d, want %d-%d`, tt.value, r.Min, r.Max, tt.min, tt.max)
+		}
+
+		if got := r.String(); got != tt.want {
+			t.Errorf(`PrefixRange.String() => %q, want %q`, got, tt.want)
+		}
+	}
+
+	invalid := []string{"192.0.2.1/24", "192.0.2.0", "192.0.2.0/24^", "192.0.2.0/24^16", "192.0.2.0/24^33",
+		"192.0.2.0/24^28-26", "192.0.2.0/32^-", "192.0.2.0/24^+1", "2001:db8::/32^129", "AS-FOO^+"}
+	for _, value := range invalid {
+		if r, err := ParsePrefixRange(value); err == nil {
+			t.Errorf(`ParsePrefixRange(%q) => %v, want an error`, value, r)
+		}
+	}
+}
+
+func TestPrefixRangeContains(t *testing.T) {
+	r, _ := ParsePrefixRange("192.0.2.0/24^25-26")
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{"192.0.2.0/24", false},
+		{"192.0.2.128/25", true},
+		{"192.0.2.192/26", true},
+		{"192.0.2.0/27", false},
+		{"198.51.100.0/25", false},
+		{"::/25", false},
+	}
+
+	for _, tt := range tests {
+		if got := r.Contains(netip.MustParsePrefix(tt.prefix)); got != tt.want {
+			t.Errorf(`PrefixRange(%v).Contains(%s) => %v, want %v`, r, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestRangeOperatorApply(t *testing.T) {
+	tests := []struct {
+		operator string
+		ranges   []string
+		want     []string
+	}{
+		// RFC 2622 section 2.
+		{"^16-24", []string{"5.0.0.0/8^+", "30.0.0.0/8^24-32"}, []string{"5.0.0.0/8^16-24", "30.0.0.0/8^24"}},
+		{"^+", []string{"192.0.2.0/24", "10.0.0.0/8^16"}, []string{"192.0.2.0/24^+", "10.0.0.0/8^16-32"}},
+		{"^-", []string{"192.0.2.0/24", "192.0.2.255/32"}, []string{"192.0.2.0/24^-"}},
+		{"^24", []string{"192.0.2.0/24", "192.0.2.0/25"}, []string{"192.0.2.0/24"}},
+		{"", []string{"192.0.2.0/24^+"}, []string{"192.0.2.0/24^+"}},
+	}
+
+	for _, tt := range tests {
+		op, err := ParseRangeOperator(tt.operator)
+		if err != nil {
+			t.Fatalf(`ParseRangeOperator(%q) => %v`, tt.operator, err)
+		}
+
+		ranges := make([]PrefixRange, len(tt.ranges))
+		for i, value := range tt.ranges {
+			ranges[i], _ = ParsePrefixRange(value)
+		}
+
+		got := make([]string, 0, len(tt.want))
+		for _, r := range op.ApplyAll(ranges) {
+			got = append(got, r.String())
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(`RangeOperator(%q).ApplyAll(%v) => %v, want %v`, tt.operator, tt.ranges, got, tt.want)
+		}
+	}
+}
+
+func TestCutRangeOperator(t *testing.T) {
+	name, op, err := CutRangeOperator("AS64496:RS-FOO^24-28")
+	if err != nil || name != "AS64496:RS-FOO" || op != (RangeOperator{Kind: Length, Min: 24, Max: 28}) {
+		t.Errorf(`CutRangeOperator => %q, %v, %v, want "AS64496:RS-FOO", ^24-28`, name, op, err)
+	}
+
+	if name, op, err := CutRangeOperator("AS-BAR"); err != nil || name != "AS-BAR" || op.Kind != NoRange {
+		t.Errorf(`CutRangeOperator => %q, %v, %v, want "AS-BAR" without operator`, name, op, err)
+	}
+
+	if _, _, err := CutRangeOperator("AS-BAR^x"); err == nil {
+		t.Errorf(`CutRangeOperator("AS-BAR^x") => nil, want an error`)
+	}
+}
+
+func TestPrefixRangeIntersect(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"10.0.0.0/8^+", "10.1.0.0/16^24", "10.1.0.0/16^24"},
+		{"10.0.0.0/8^16-24", "10.1.0.0/16^+", "10.1.0.0/16^16-24"},
+		{"10.0.0.0/8^16", "10.1.0.0/16^24", ""},
+		{"10.0.0.0/8", "10.1.0.0/16", ""},
+		{"10.0.0.0/8^+", "192.0.2.0/24", ""},
+		{"0.0.0.0/0^+", "::/0^+", ""},
+		{"192.0.2.0/24^+", "192.0.2.0/24^-", "192.0.2.0/24^-"},
+	}
+
+	for _, tt := range tests {
+		a, _ := ParsePrefixRange(tt.a)
+		b, _ := ParsePrefixRange(tt.b)
+		for _, args := range [][2]PrefixRange{{a, b}, {b, a}} {
+			got, ok := args[0].Intersect(args[1])
+			if !ok && tt.want != "" || ok && got.String() != tt.want {
+				t.Errorf(`PrefixRange(%v).Intersect(%v) => %v, %v, want %q`, args[0], args[1], got, ok, tt.want)
+			}
+
+			if overlaps := args[0].Overlaps(args[1]); overlaps != (tt.want != "") {
+				t.Errorf(`PrefixRange(%v).Overlaps(%v) => %v, want %v`, args[0], args[1], overlaps, !overlaps)
+			}
+		}
+	}
+}
+
+func TestPrefixRangeExpand(t *testing.T) {
+	r, _ := ParsePrefixRange("192.0.2.0/24^25-26")
+	got, err := r.Expand(6)
+	if err != nil {
+		t.Fatalf(`PrefixRange.Expand => %v`, err)
+	}
+
+	want := []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.0/25"), netip.MustParsePrefix("192.0.2.128/25"),
+		netip.MustParsePrefix("192.0.2.0/26"), netip.MustParsePrefix("192.0.2.64/26"),
+		netip.MustParsePrefix("192.0.2.128/26"), netip.MustParsePrefix("192.0.2.192/26"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(`PrefixRange.Expand => %v, want %v`, got, want)
+	}
+
+	if _, err := r.Expand(5); err == nil {
+		t.Errorf(`PrefixRange.Expand(5) => nil, want an error`)
+	}
+
+	// The last prefixes of the address space.
+	for _, value := range []string{"255.255.255.0/24^+", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120^+"} {
+		r, _ := ParsePrefixRange(value)
+		if got, err := r.Expand(1024); err != nil || uint64(len(got)) != r.Len() || len(got) != 511 {
+			t.Errorf(`PrefixRange(%v).Expand => %d prefixes, %v, want 511`, r, len(got), err)
+		}
+	}
+
+	// The whole address space.
+	for _, value := range []string{"::/0", "0.0.0.0/0", "0.0.0.0/0^0-1"} {
+		r, _ := ParsePrefixRange(value)
+		if got, err := r.Expand(10); err != nil || uint64(len(got)) != r.Len() || got[0].Bits() != 0 {
+			t.Errorf(`PrefixRange(%v).Expand => %v, %v, want %d prefixes`, r, got, err, r.Len())
+		}
+	}
+
+	if r, _ := ParsePrefixRange("::/0^+"); r.Len() != ^uint64(0) {
+		t.Errorf(`PrefixRange(::/0^+).Len() => %d, want saturation`, r.Len())
+	}
+}
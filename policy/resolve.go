@@ -0,0 +1,476 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// DefaultMaxDepth is the default maximum nesting of the sets expanded by a SetResolver.
+const DefaultMaxDepth = 32
+
+// ResolverOptions controls how a SetResolver expands sets.
+type ResolverOptions struct {
+	// MaxDepth is the maximum nesting of sets. Expanding a set nested deeper fails. Zero means DefaultMaxDepth.
+	MaxDepth int
+
+	// Strict makes ASNs, Prefixes and Filter fail when a set references a set that cannot be found, instead of
+	// ignoring the reference.
+	Strict bool
+}
+
+// Expansion is the result of the expansion of a set.
+type Expansion struct {
+	// ASNs are the AS numbers of the set, sorted and without duplicates.
+	ASNs []uint32
+	// Prefixes are the prefix ranges of the set, sorted and without duplicates. Only set when expanding routes.
+	Prefixes []PrefixRange
+	// Unresolved holds the names of the referenced sets that could not be found, and the members that could not be
+	// parsed.
+	Unresolved []string
+	// Loops holds the names of the sets that were found to include themselves. Their members are only expanded once.
+	Loops []string
+}
+
+// SetResolver expands the as-sets, route-sets and filter-sets of a collection of objects, following nested sets,
+// mbrs-by-ref and member-of back-references, and the route and route6 objects originated by AS numbers. It
+// implements Resolver, and is safe for concurrent use.
+//
+// Example:
+//
+//	objs, err := rpsl.ParseManyFromReader(file)
+//	if err != nil {
+//	    log.Fatalf("Failed to parse RPSL objects: %v", err)
+//	}
+//
+//	r := policy.NewSetResolver(objs)
+//	exp, err := r.ExpandRoutes("AS-FOO")
+//	if err != nil {
+//	    log.Fatalf("Failed to expand AS-FOO: %v", err)
+//	}
+//
+//	for _, prefix := range exp.Prefixes {
+//	    fmt.Println(prefix)
+//	}
+type SetResolver struct {
+	opts ResolverOptions
+
+	// sets holds the as-set, route-set and filter-set objects by upper-case name.
+	sets map[string]*rpsl.Object
+	// routes holds the prefixes of the route and route6 objects by origin AS.
+	routes map[uint32][]PrefixRange
+	// memberOf holds the aut-num, route and route6 objects by upper-case name of the sets they are a member of.
+	memberOf map[string][]*rpsl.Object
+}
+
+// NewSetResolver indexes the objects and returns a SetResolver expanding their sets. The objects must not be modified
+// while the SetResolver is used.
+func NewSetResolver(objects []rpsl.Object) *SetResolver {
+	return NewSetResolverWithOptions(objects, ResolverOptions{})
+}
+
+// NewSetResolverWithOptions indexes the objects and returns a SetResolver expanding their sets according to opts. The
+// objects must not be modified while the SetResolver is used.
+func NewSetResolverWithOptions(objects []rpsl.Object, opts ResolverOptions) *SetResolver {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+
+	r := &SetResolver{
+		opts:     opts,
+		sets:     make(map[string]*rpsl.Object),
+		routes:   make(map[uint32][]PrefixRange),
+		memberOf: make(map[string][]*rpsl.Object),
+	}
+
+	for i := range objects {
+		obj := &objects[i]
+		switch obj.Class() {
+		case "as-set", "route-set", "filter-set":
+			r.sets[strings.ToUpper(obj.Attributes[0].Value)] = obj
+		case "route", "route6":
+			prefix, err := ParsePrefixRange(obj.Attributes[0].Value)
+			origin := obj.GetFirst("origin")
+			if err != nil || origin == nil {
+				continue
+			}
+
+			asn, err := rpsl.ParseASN(*origin)
+			if err != nil {
+				continue
+			}
+
+			r.routes[asn] = append(r.routes[asn], prefix)
+		case "aut-num":
+			// Only indexed by the sets it is a member of.
+		default:
+			continue
+		}
+
+		for _, value := range obj.GetAll("member-of") {
+			for _, name := range splitList(value) {
+				key := strings.ToUpper(name)
+				r.memberOf[key] = append(r.memberOf[key], obj)
+			}
+		}
+	}
+
+	return r
+}
+
+// splitList splits a list of members separated by commas or spaces.
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+}
+
+// ExpandASSet returns the AS numbers of an as-set.
+func (r *SetResolver) ExpandASSet(name string) (*Expansion, error) {
+	e := r.newExpander()
+	asns, err := e.expandASSet(name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asn := range asns {
+		e.asns[asn] = struct{}{}
+	}
+
+	return e.result(), nil
+}
+
+// ExpandRoutes returns the prefix ranges of the routes of an AS number, an as-set or a route-set, optionally followed
+// by a range operator such as "RS-FOO^+". The routes of an AS number are the route and route6 objects it originates.
+// For an as-set, the AS numbers of the set are returned as well.
+func (r *SetResolver) ExpandRoutes(name string) (*Expansion, error) {
+	name, op, err := CutRangeOperator(name)
+	if err != nil {
+		return nil, err
+	}
+
+	e := r.newExpander()
+	prefixes, err := e.expandRoutes(name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	e.prefixes = op.ApplyAll(prefixes)
+	return e.result(), nil
+}
+
+// ASNs returns the AS numbers of an as-set. It implements Resolver.
+func (r *SetResolver) ASNs(name string) ([]uint32, error) {
+	exp, err := r.ExpandASSet(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.check(name, exp); err != nil {
+		return nil, err
+	}
+
+	return exp.ASNs, nil
+}
+
+// Prefixes returns the prefix ranges of the routes of an AS number, an as-set or a route-set. It implements
+// Resolver.
+func (r *SetResolver) Prefixes(name string) ([]PrefixRange, error) {
+	exp, err := r.ExpandRoutes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.check(name, exp); err != nil {
+		return nil, err
+	}
+
+	return exp.Prefixes, nil
+}
+
+// Filter returns the filter of a filter-set, from its mp-filter attribute, or else from its filter attribute. It
+// implements Resolver.
+func (r *SetResolver) Filter(name string) (*Filter, error) {
+	obj := r.set(name, "filter-set")
+	if obj == nil {
+		return nil, fmt.Errorf("policy: filter-set '%s' not found", name)
+	}
+
+	value := obj.GetFirst("mp-filter")
+	if value == nil {
+		value = obj.GetFirst("filter")
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("policy: filter-set '%s' has no filter", name)
+	}
+
+	return ParseFilter(*value)
+}
+
+// check returns an error for the unresolved references of an expansion in strict mode.
+func (r *SetResolver) check(name string, exp *Expansion) error {
+	if r.opts.Strict && len(exp.Unresolved) > 0 {
+		return fmt.Errorf("policy: unresolved references in '%s': %s", name, strings.Join(exp.Unresolved, ", "))
+	}
+
+	return nil
+}
+
+// set returns the set of the given class and name, or nil if it cannot be found.
+func (r *SetResolver) set(name string, class string) *rpsl.Object {
+	if obj, ok := r.sets[strings.ToUpper(name)]; ok && obj.Class() == class {
+		return obj
+	}
+
+	return nil
+}
+
+// expander holds the state of a single expansion.
+type expander struct {
+	r          *SetResolver
+	asns       map[uint32]struct{}
+	prefixes   []PrefixRange
+	unresolved []string
+	loops      []string
+
+	// members and routes hold the AS numbers of the as-sets and the routes of the route-sets already expanded, and
+	// expanding the sets being expanded, to detect loops.
+	members   map[string][]uint32
+	routes    map[string][]PrefixRange
+	expanding map[string]struct{}
+}
+
+// newExpander returns an expander with empty state.
+func (r *SetResolver) newExpander() *expander {
+	return &expander{
+		r:         r,
+		asns:      make(map[uint32]struct{}),
+		members:   make(map[string][]uint32),
+		routes:    make(map[string][]PrefixRange),
+		expanding: make(map[string]struct{}),
+	}
+}
+
+// result returns the sorted and deduplicated result of the expansion.
+func (e *expander) result() *Expansion {
+	exp := &Expansion{
+		ASNs:       make([]uint32, 0, len(e.asns)),
+		Prefixes:   e.prefixes,
+		Unresolved: e.unresolved,
+		Loops:      e.loops,
+	}
+
+	for asn := range e.asns {
+		exp.ASNs = append(exp.ASNs, asn)
+	}
+
+	slices.Sort(exp.ASNs)
+	slices.SortFunc(exp.Prefixes, comparePrefixRanges)
+	exp.Prefixes = slices.Compact(exp.Prefixes)
+	return exp
+}
+
+// comparePrefixRanges orders prefix ranges by address, prefix length and lengths.
+func comparePrefixRanges(a PrefixRange, b PrefixRange) int {
+	return cmp.Or(
+		a.Prefix.Addr().Compare(b.Prefix.Addr()),
+		cmp.Compare(a.Prefix.Bits(), b.Prefix.Bits()),
+		cmp.Compare(a.Min, b.Min),
+		cmp.Compare(a.Max, b.Max),
+	)
+}
+
+// unresolve records a reference that could not be resolved.
+func (e *expander) unresolve(name string) {
+	if !slices.Contains(e.unresolved, name) {
+		e.unresolved = append(e.unresolved, name)
+	}
+}
+
+// enter marks a set as being expanded. It returns false if the set is already being expanded, recording the loop, and
+// an error if the set is nested too deeply.
+func (e *expander) enter(key string, name string, depth int) (bool, error) {
+	if _, ok := e.expanding[key]; ok {
+		if !slices.Contains(e.loops, name) {
+			e.loops = append(e.loops, name)
+		}
+
+		return false, nil
+	}
+
+	if depth > e.r.opts.MaxDepth {
+		return false, fmt.Errorf("policy: sets nested deeper than %d in '%s'", e.r.opts.MaxDepth, name)
+	}
+
+	e.expanding[key] = struct{}{}
+	return true, nil
+}
+
+// expandASSet returns the AS numbers of an as-set.
+func (e *expander) expandASSet(name string, depth int) ([]uint32, error) {
+	key := strings.ToUpper(name)
+	if asns, ok := e.members[key]; ok {
+		return asns, nil
+	}
+
+	obj := e.r.set(name, "as-set")
+	if obj == nil {
+		e.unresolve(name)
+		return nil, nil
+	}
+
+	ok, err := e.enter(key, name, depth)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	defer delete(e.expanding, key)
+
+	var asns []uint32
+	for _, value := range obj.GetAll("members") {
+		for _, member := range splitList(value) {
+			if asn, err := rpsl.ParseASN(member); err == nil {
+				asns = append(asns, asn)
+				continue
+			}
+
+			if rpsl.CheckASSetName(member) != nil {
+				e.unresolve(member)
+				continue
+			}
+
+			members, err := e.expandASSet(member, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			asns = append(asns, members...)
+		}
+	}
+
+	for _, member := range e.r.referencedBy(obj, key, "aut-num") {
+		if asn, err := rpsl.ParseASN(member.Attributes[0].Value); err == nil {
+			asns = append(asns, asn)
+		}
+	}
+
+	e.members[key] = asns
+	return asns, nil
+}
+
+// expandRoutes returns the routes of an AS number, an as-set or a route-set.
+func (e *expander) expandRoutes(name string, depth int) ([]PrefixRange, error) {
+	if asn, err := rpsl.ParseASN(name); err == nil {
+		e.asns[asn] = struct{}{}
+		return e.r.routes[asn], nil
+	}
+
+	if rpsl.CheckASSetName(name) != nil {
+		return e.expandRouteSet(name, depth)
+	}
+
+	asns, err := e.expandASSet(name, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefixes []PrefixRange
+	for _, asn := range asns {
+		e.asns[asn] = struct{}{}
+		prefixes = append(prefixes, e.r.routes[asn]...)
+	}
+
+	return prefixes, nil
+}
+
+// expandRouteSet returns the routes of a route-set, before applying any range operator.
+func (e *expander) expandRouteSet(name string, depth int) ([]PrefixRange, error) {
+	key := strings.ToUpper(name)
+	if prefixes, ok := e.routes[key]; ok {
+		return prefixes, nil
+	}
+
+	obj := e.r.set(name, "route-set")
+	if obj == nil {
+		e.unresolve(name)
+		return nil, nil
+	}
+
+	ok, err := e.enter(key, name, depth)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	defer delete(e.expanding, key)
+
+	var prefixes []PrefixRange
+	for _, value := range append(obj.GetAll("members"), obj.GetAll("mp-members")...) {
+		for _, member := range splitList(value) {
+			if r, err := ParsePrefixRange(member); err == nil {
+				prefixes = append(prefixes, r)
+				continue
+			}
+
+			set, op, err := CutRangeOperator(member)
+			if err != nil || rpsl.CheckASNumber(set) != nil && rpsl.CheckASSetName(set) != nil &&
+				rpsl.CheckRouteSetName(set) != nil {
+				e.unresolve(member)
+				continue
+			}
+
+			routes, err := e.expandRoutes(set, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			prefixes = append(prefixes, op.ApplyAll(routes)...)
+		}
+	}
+
+	for _, member := range e.r.referencedBy(obj, key, "route", "route6") {
+		if r, err := ParsePrefixRange(member.Attributes[0].Value); err == nil {
+			prefixes = append(prefixes, r)
+		}
+	}
+
+	e.routes[key] = prefixes
+	return prefixes, nil
+}
+
+// referencedBy returns the objects of the given classes that are a member of the set through their member-of
+// attribute, and are maintained by one of the maintainers of the mbrs-by-ref attribute of the set. The keyword ANY
+// accepts every maintainer.
+func (r *SetResolver) referencedBy(set *rpsl.Object, key string, classes ...string) []*rpsl.Object {
+	maintainers := make(map[string]struct{})
+	for _, value := range set.GetAll("mbrs-by-ref") {
+		for _, mntner := range splitList(value) {
+			maintainers[strings.ToUpper(mntner)] = struct{}{}
+		}
+	}
+
+	if len(maintainers) == 0 {
+		return nil
+	}
+
+	_, any := maintainers["ANY"]
+	var members []*rpsl.Object
+	for _, obj := range r.memberOf[key] {
+		if !slices.Contains(classes, obj.Class()) {
+			continue
+		}
+
+		for _, value := range obj.GetAll("mnt-by") {
+			if _, ok := maintainers[strings.ToUpper(strings.TrimSpace(value))]; ok || any {
+				members = append(members, obj)
+				break
+			}
+		}
+	}
+
+	return members
+}
@@ -0,0 +1,183 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"net/netip"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+const testObjects = `as-set:      AS-FOO
+members:     AS64496, AS-BAR
+mbrs-by-ref: FOO-MNT
+
+as-set:      AS-BAR
+members:     AS64497 AS-FOO
+members:     AS-MISSING
+
+as-set:      AS64500:AS-DEEP
+members:     AS64500:AS-DEEP1
+
+as-set:      AS64500:AS-DEEP1
+members:     AS64500:AS-DEEP2
+
+as-set:      AS64500:AS-DEEP2
+members:     AS64500
+
+aut-num:     AS64498
+member-of:   AS-FOO
+mnt-by:      FOO-MNT
+
+aut-num:     AS64499
+member-of:   AS-FOO
+mnt-by:      OTHER-MNT
+
+route:       192.0.2.0/24
+origin:      AS64496
+
+route:       198.51.100.0/24
+origin:      AS64497
+
+route6:      2001:db8::/32
+origin:      AS64498
+member-of:   RS-FOO
+mnt-by:      OTHER-MNT
+
+route:       203.0.113.0/24
+origin:      AS64499
+member-of:   RS-FOO
+mnt-by:      OTHER-MNT
+
+route-set:   RS-FOO
+members:     10.0.0.0/8^16, AS64496^+, RS-BAR^24
+mp-members:  2001:db8:1::/48
+mbrs-by-ref: ANY
+
+route-set:   RS-BAR
+members:     172.16.0.0/12^+, RS-FOO
+
+filter-set:  FLTR-FOO
+filter:      { 0.0.0.0/0 }
+mp-filter:   RS-FOO OR AS-FOO
+`
+
+// newTestResolver returns a SetResolver over testObjects.
+func newTestResolver(t *testing.T, opts ResolverOptions) *SetResolver {
+	objects, err := rpsl.ParseMany(testObjects)
+	if err != nil {
+		t.Fatalf(`ParseMany => %v`, err)
+	}
+
+	return NewSetResolverWithOptions(objects, opts)
+}
+
+func TestExpandASSet(t *testing.T) {
+	r := newTestResolver(t, ResolverOptions{})
+	exp, err := r.ExpandASSet("as-foo")
+	if err != nil {
+		t.Fatalf(`ExpandASSet => %v`, err)
+	}
+
+	if want := []uint32{64496, 64497, 64498}; !reflect.DeepEqual(exp.ASNs, want) {
+		t.Errorf(`Expansion.ASNs => %v, want %v`, exp.ASNs, want)
+	}
+
+	if want := []string{"AS-MISSING"}; !reflect.DeepEqual(exp.Unresolved, want) {
+		t.Errorf(`Expansion.Unresolved => %v, want %v`, exp.Unresolved, want)
+	}
+
+	if want := []string{"AS-FOO"}; !reflect.DeepEqual(exp.Loops, want) {
+		t.Errorf(`Expansion.Loops => %v, want %v`, exp.Loops, want)
+	}
+}
+
+func TestExpandRoutes(t *testing.T) {
+	r := newTestResolver(t, ResolverOptions{})
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"AS64496", []string{"192.0.2.0/24"}},
+		{"AS64496^+", []string{"192.0.2.0/24^+"}},
+		{"AS-BAR", []string{"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"}},
+		{"RS-FOO", []string{
+			"10.0.0.0/8^16", "172.16.0.0/12^24", "192.0.2.0/24^+", "203.0.113.0/24", "2001:db8::/32",
+			"2001:db8:1::/48",
+		}},
+		{"RS-FOO^24", []string{"10.0.0.0/8^24", "172.16.0.0/12^24", "192.0.2.0/24", "203.0.113.0/24"}},
+	}
+
+	for _, tt := range tests {
+		exp, err := r.ExpandRoutes(tt.name)
+		if err != nil {
+			t.Fatalf(`ExpandRoutes(%q) => %v`, tt.name, err)
+		}
+
+		got := make([]string, len(exp.Prefixes))
+		for i, prefix := range exp.Prefixes {
+			got[i] = prefix.String()
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(`ExpandRoutes(%q) => %v, want %v`, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	r := newTestResolver(t, ResolverOptions{MaxDepth: 1})
+	if _, err := r.ExpandASSet("AS64500:AS-DEEP"); err == nil {
+		t.Errorf(`ExpandASSet => nil, want a depth error`)
+	}
+
+	strict := newTestResolver(t, ResolverOptions{Strict: true})
+	if _, err := strict.ASNs("AS-FOO"); err == nil {
+		t.Errorf(`ASNs => nil, want an unresolved reference error`)
+	}
+
+	if _, err := strict.Prefixes("RS-MISSING"); err == nil {
+		t.Errorf(`Prefixes => nil, want an unresolved reference error`)
+	}
+
+	lenient := newTestResolver(t, ResolverOptions{})
+	if asns, err := lenient.ASNs("AS64500:AS-DEEP"); err != nil || !reflect.DeepEqual(asns, []uint32{64500}) {
+		t.Errorf(`ASNs => %v, %v, want [64500]`, asns, err)
+	}
+}
+
+func TestSetResolverFilter(t *testing.T) {
+	r := newTestResolver(t, ResolverOptions{})
+	f, err := ParseFilter("FLTR-FOO AND NOT AS64497")
+	if err != nil {
+		t.Fatalf(`ParseFilter => %v`, err)
+	}
+
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{"192.0.2.0/25", true},
+		{"2001:db8::/32", true},
+		{"198.51.100.0/24", false},
+		{"0.0.0.0/0", false},
+	}
+
+	var wg sync.WaitGroup
+	for _, tt := range tests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := f.Match(&Route{Prefix: netip.MustParsePrefix(tt.prefix)}, r)
+			if err != nil || got != tt.want {
+				t.Errorf(`Filter.Match(%s) => %v, %v, want %v`, tt.prefix, got, err, tt.want)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
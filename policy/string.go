@@ -0,0 +1,155 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"strings"
+)
+
+// String returns a string representation of the Policy.
+func (p *Policy) String() string {
+	var str strings.Builder
+	if p.Protocol != "" {
+		str.WriteString("protocol ")
+		str.WriteString(p.Protocol)
+		str.WriteByte(' ')
+	}
+
+	if p.Into != "" {
+		str.WriteString("into ")
+		str.WriteString(p.Into)
+		str.WriteByte(' ')
+	}
+
+	direction, filter := "from", "accept"
+	if p.export {
+		direction, filter = "to", "announce"
+	}
+
+	writeExpression(&str, p.Expression, direction, filter)
+	return str.String()
+}
+
+// writeExpression writes an expression, with the keywords of an import or export policy.
+func writeExpression(str *strings.Builder, e *Expression, direction string, filter string) {
+	if len(e.AFIs) > 0 {
+		str.WriteString("afi ")
+		str.WriteString(strings.Join(e.AFIs, ", "))
+		str.WriteByte(' ')
+	}
+
+	if e.Term.Braced {
+		str.WriteString("{ ")
+	}
+
+	for _, factor := range e.Term.Factors {
+		for _, peering := range factor.Peerings {
+			str.WriteString(direction)
+			str.WriteByte(' ')
+			str.WriteString(peering.String())
+			str.WriteByte(' ')
+		}
+
+		str.WriteString(filter)
+		str.WriteByte(' ')
+		str.WriteString(factor.Filter.String())
+		if e.Term.Braced {
+			str.WriteString("; ")
+		}
+	}
+
+	if e.Term.Braced {
+		str.WriteByte('}')
+	}
+
+	if e.Operator != None && e.Next != nil {
+		str.WriteByte(' ')
+		str.WriteString(e.Operator.String())
+		str.WriteByte(' ')
+		writeExpression(str, e.Next, direction, filter)
+	}
+}
+
+// String returns a string representation of the Default.
+func (d *Default) String() string {
+	var str strings.Builder
+	if len(d.AFIs) > 0 {
+		str.WriteString("afi ")
+		str.WriteString(strings.Join(d.AFIs, ", "))
+		str.WriteByte(' ')
+	}
+
+	str.WriteString("to ")
+	str.WriteString(d.Peering.String())
+	if d.Networks != nil {
+		str.WriteString(" networks ")
+		str.WriteString(d.Networks.String())
+	}
+
+	return str.String()
+}
+
+// String returns a string representation of the PeeringAction.
+func (pa *PeeringAction) String() string {
+	var str strings.Builder
+	str.WriteString(pa.Peering.String())
+	if len(pa.Actions) > 0 {
+		str.WriteString(" action")
+		for _, action := range pa.Actions {
+			str.WriteByte(' ')
+			str.WriteString(action.String())
+			str.WriteByte(';')
+		}
+	}
+
+	return str.String()
+}
+
+// String returns a string representation of the Peering.
+func (p *Peering) String() string {
+	if p.Set != "" {
+		return p.Set
+	}
+
+	var str strings.Builder
+	str.WriteString(p.AS.String())
+	if p.Remote != nil {
+		str.WriteByte(' ')
+		str.WriteString(p.Remote.String())
+	}
+
+	if p.Local != nil {
+		str.WriteString(" at ")
+		str.WriteString(p.Local.String())
+	}
+
+	return str.String()
+}
+
+// String returns a string representation of the SetExpr, with parentheses around nested operations.
+func (e *SetExpr) String() string {
+	if e.Operator == "" {
+		return e.Name
+	}
+
+	return operand(e.Left) + " " + e.Operator + " " + operand(e.Right)
+}
+
+// operand returns a string representation of an operand, parenthesized if it is an operation.
+func operand(e *SetExpr) string {
+	if e.Operator == "" {
+		return e.Name
+	}
+
+	return "(" + e.String() + ")"
+}
+
+// String returns a string representation of the Action.
+func (a *Action) String() string {
+	if a.Method != "" {
+		return a.Attribute + "." + a.Method + "(" + strings.Join(a.Args, ", ") + ")"
+	}
+
+	return a.Attribute + " " + a.Operator + " " + strings.Join(a.Args, ", ")
+}
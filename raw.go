@@ -0,0 +1,247 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"bytes"
+	"cmp"
+	"strings"
+)
+
+// valueColumn is the column at which values are aligned, as in the output of the RIPE database.
+const valueColumn = 16
+
+// RawObject holds the original text surrounding an Object parsed in lossless mode.
+type RawObject struct {
+	// Leading is the text preceding the first attribute, such as comment lines at the top of the input.
+	Leading string
+	// Trailing is the text following the last attribute, such as comment lines and the empty lines separating the
+	// Object from the next one.
+	Trailing string
+}
+
+// RawAttribute holds the original text of an Attribute parsed in lossless mode.
+type RawAttribute struct {
+	// Leading is the text of the comment lines between the previous attribute and this one.
+	Leading string
+	// Text is the original text of the attribute, including continuation lines and line endings.
+	Text string
+	// Name is the name of the attribute with its original casing.
+	Name string
+	// Comments holds the end-of-line comment of every line of the attribute, without the leading '#'. It is empty for
+	// lines without a comment.
+	Comments []string
+	// Continuation is the character starting the first continuation line, one of '+', ' ' or '\t'. It is zero for
+	// attributes spanning a single line.
+	Continuation byte
+
+	// The parsed name and value, used to detect modifications.
+	name  string
+	value string
+}
+
+// newRawAttribute records the original text of attr. The attribute spans the lines of raw delimited by starts and
+// ends, and its leading comment lines start at prev.
+func newRawAttribute(attr *Attribute, raw []byte, prev int, starts []int, ends []int) *RawAttribute {
+	first := dropLineEnding(raw[starts[0]:ends[0]])
+	colon := bytes.IndexByte(first, ':')
+
+	r := &RawAttribute{
+		Leading:  string(raw[prev:starts[0]]),
+		Text:     string(raw[starts[0]:ends[len(ends)-1]]),
+		Name:     string(first[:colon]),
+		Comments: make([]string, len(starts)),
+		name:     attr.Name,
+		value:    attr.Value,
+	}
+
+	for i := range starts {
+		line := dropLineEnding(raw[starts[i]:ends[i]])
+		if i == 0 {
+			line = line[colon+1:]
+		} else if r.Continuation == 0 {
+			r.Continuation = line[0]
+		}
+
+		if idx := bytes.IndexByte(line, '#'); idx >= 0 {
+			r.Comments[i] = string(bytes.TrimSpace(line[idx+1:]))
+		}
+	}
+
+	return r
+}
+
+// Modified returns true if the Attribute was changed since it was parsed, or if it was not parsed in lossless mode.
+func (a *Attribute) Modified() bool {
+	return a.Raw == nil || a.Raw.name != a.Name || a.Raw.value != a.Value
+}
+
+// Comment returns the end-of-line comments of the Attribute, joined with a space. It is empty if the Attribute was not
+// parsed in lossless mode.
+func (a *Attribute) Comment() string {
+	if a.Raw == nil {
+		return ""
+	}
+
+	return joinComments(a.Raw.Comments)
+}
+
+// appendBytes appends the text of the Attribute to buf, reusing the original text if it was not modified. A modified
+// Attribute parsed in lossless mode keeps the layout of its original lines: the casing and alignment of its name, its
+// continuation lines, their end-of-line comments and line endings.
+func (a *Attribute) appendBytes(buf *bytes.Buffer) {
+	if a.Raw != nil {
+		buf.WriteString(a.Raw.Leading)
+	}
+
+	if !a.Modified() {
+		buf.WriteString(a.Raw.Text)
+		return
+	}
+
+	// Start on a new line if the previous attribute was the last line of the input.
+	if buf.Len() > 0 && buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	lines := []rawLine{{ending: "\n"}}
+	if a.Raw != nil {
+		lines = a.Raw.lines()
+	}
+
+	// The name is kept with its original casing and alignment, unless it was renamed.
+	name := a.Name
+	original := a.Raw != nil && strings.EqualFold(a.Raw.Name, a.Name)
+	if original {
+		name = a.Raw.Name
+	}
+
+	if !original || lines[0].prefix == name+":" {
+		lines[0].prefix = name + ":" + strings.Repeat(" ", max(valueColumn-len(name)-1, 1))
+	}
+
+	// The words of the value are spread over the original lines, the last line taking the remaining ones.
+	words := []string{a.Value}
+	if len(lines) > 1 {
+		words = strings.Fields(a.Value)
+	}
+
+	for i, line := range lines {
+		n := min(line.words, len(words))
+		if i == len(lines)-1 {
+			n = len(words)
+		}
+
+		value := strings.Join(words[:n], " ")
+		words = words[n:]
+		switch {
+		case value != "":
+			buf.WriteString(line.prefix)
+			buf.WriteString(value)
+			if line.comment != "" {
+				buf.WriteString(cmp.Or(line.gap, " "))
+			}
+		case line.words == 0:
+			// Lines that had no value, such as a lone '+' or a comment, are kept as they were.
+			buf.WriteString(line.prefix)
+			buf.WriteString(line.gap)
+		case i > 0 && line.comment == "":
+			// Continuation lines left without value or comment are dropped.
+			continue
+		default:
+			buf.WriteString(strings.TrimRight(line.prefix, " \t"))
+			if line.comment != "" {
+				buf.WriteByte(' ')
+			}
+		}
+
+		buf.WriteString(line.comment)
+		buf.WriteString(line.ending)
+	}
+}
+
+// rawLine is the layout of a line of an Attribute parsed in lossless mode.
+type rawLine struct {
+	// prefix is the text preceding the value: the name, colon and spacing on the first line, or the continuation
+	// character and spacing on the next ones.
+	prefix string
+	// words is the number of words of the value on the line.
+	words int
+	// gap is the spacing between the value and the comment.
+	gap string
+	// comment is the end-of-line comment, starting with '#', or empty.
+	comment string
+	// ending is the line ending, empty on the last line of the input.
+	ending string
+}
+
+// lines returns the layout of the original lines of the attribute.
+func (r *RawAttribute) lines() []rawLine {
+	text := r.Text
+	lines := make([]rawLine, 0, len(r.Comments))
+	for i := 0; text != ""; i++ {
+		line := text
+		ending := ""
+		if idx := strings.IndexByte(text, '\n'); idx >= 0 {
+			line = text[:idx]
+			ending = "\n"
+		}
+
+		text = text[len(line)+len(ending):]
+		if trimmed := strings.TrimSuffix(line, "\r"); trimmed != line {
+			line, ending = trimmed, "\r"+ending
+		}
+
+		prefix := ""
+		if i == 0 {
+			prefix, line = line[:len(r.Name)+1], line[len(r.Name)+1:]
+		} else if strings.HasPrefix(line, "+") {
+			prefix, line = "+", line[1:]
+		}
+
+		value := strings.TrimLeft(line, " \t")
+		prefix += line[:len(line)-len(value)]
+
+		comment := ""
+		if idx := strings.IndexByte(value, '#'); idx >= 0 {
+			value, comment = value[:idx], value[idx:]
+		}
+
+		trimmed := strings.TrimRight(value, " \t")
+		lines = append(lines, rawLine{
+			prefix:  prefix,
+			words:   len(strings.Fields(trimmed)),
+			gap:     value[len(trimmed):],
+			comment: comment,
+			ending:  ending,
+		})
+	}
+
+	// The last attribute of the input may end without a line ending.
+	lines[len(lines)-1].ending = cmp.Or(lines[len(lines)-1].ending, "\n")
+	return lines
+}
+
+// Bytes returns the text of the Object. Attributes parsed in lossless mode and left unmodified are reproduced
+// byte-for-byte, together with the comments and empty lines surrounding them. Modified attributes keep the layout of
+// their original lines, with their comments and continuation lines, and new attributes are written on a single line.
+//
+// Concatenating the Bytes of all the objects parsed in lossless mode from an input reproduces the input, with the
+// exception of the malformed objects skipped at the end of the input in lenient mode.
+func (o *Object) Bytes() []byte {
+	var buf bytes.Buffer
+	if o.Raw != nil {
+		buf.WriteString(o.Raw.Leading)
+	}
+
+	for i := range o.Attributes {
+		o.Attributes[i].appendBytes(&buf)
+	}
+
+	if o.Raw != nil {
+		buf.WriteString(o.Raw.Trailing)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,245 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const losslessInput = "% This is the RIPE Database query service.\n" +
+	"\n" +
+	"Mntner:          DEV-MNT  # Comment \n" +
+	"descr:\tDEV maintainer\r\n" +
+	"+                second line # with a comment\n" +
+	"  third line\n" +
+	"# comment between attributes\n" +
+	"admin-c:         VM1-DEV\n" +
+	"source:          DEV\n" +
+	"% trailing comment\n" +
+	"\n" +
+	"\n" +
+	"person:  John Doe\n" +
+	"source:  TEST"
+
+func TestLosslessRoundTrip(t *testing.T) {
+	objs, err := ParseManyWithOptions(losslessInput, ParseOptions{Lossless: true})
+	if err != nil {
+		t.Fatalf(`ParseManyWithOptions() => %v`, err)
+	}
+
+	if len(objs) != 2 {
+		t.Fatalf(`ParseManyWithOptions() => %v objects, want %v`, len(objs), 2)
+	}
+
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		buf.Write(obj.Bytes())
+	}
+
+	if buf.String() != losslessInput {
+		t.Fatalf(`Bytes() => %q, want %q`, buf.String(), losslessInput)
+	}
+
+	// The parsed values are still normalized.
+	if objs[0].Attributes[0].Name != "mntner" {
+		t.Errorf(`Name => %v, want %v`, objs[0].Attributes[0].Name, "mntner")
+	}
+
+	if objs[0].Attributes[1].Value != "DEV maintainer second line third line" {
+		t.Errorf(`Value => %v, want %v`, objs[0].Attributes[1].Value, "DEV maintainer second line third line")
+	}
+}
+
+func TestLosslessRawAttribute(t *testing.T) {
+	obj, err := parseLossless(losslessInput)
+	if err != nil {
+		t.Fatalf(`ParseManyWithOptions() => %v`, err)
+	}
+
+	mntner := obj.Attributes[0].Raw
+	if mntner.Name != "Mntner" {
+		t.Errorf(`Raw.Name => %v, want %v`, mntner.Name, "Mntner")
+	}
+
+	if len(mntner.Comments) != 1 || mntner.Comments[0] != "Comment" {
+		t.Errorf(`Raw.Comments => %q, want %q`, mntner.Comments, []string{"Comment"})
+	}
+
+	descr := obj.Attributes[1].Raw
+	if descr.Continuation != '+' {
+		t.Errorf(`Raw.Continuation => %q, want %q`, descr.Continuation, '+')
+	}
+
+	if len(descr.Comments) != 3 || descr.Comments[1] != "with a comment" {
+		t.Errorf(`Raw.Comments => %q, want 3 comments`, descr.Comments)
+	}
+
+	adminC := obj.Attributes[2].Raw
+	if adminC.Leading != "# comment between attributes\n" {
+		t.Errorf(`Raw.Leading => %q, want %q`, adminC.Leading, "# comment between attributes\n")
+	}
+
+	if obj.Raw.Leading != "% This is the RIPE Database query service.\n\n" {
+		t.Errorf(`Object.Raw.Leading => %q`, obj.Raw.Leading)
+	}
+
+	if obj.Raw.Trailing != "% trailing comment\n\n\n" {
+		t.Errorf(`Object.Raw.Trailing => %q`, obj.Raw.Trailing)
+	}
+}
+
+func TestLosslessModified(t *testing.T) {
+	obj, err := parseLossless(losslessInput)
+	if err != nil {
+		t.Fatalf(`ParseManyWithOptions() => %v`, err)
+	}
+
+	obj.Attributes[1].Value = "New maintainer"
+	obj.Attributes[2].Value = "JD1-DEV"
+	obj.Attributes = append(obj.Attributes, Attribute{Name: "remarks", Value: "added"})
+
+	if obj.Attributes[0].Modified() {
+		t.Errorf(`Modified() => true, want false`)
+	}
+
+	if !obj.Attributes[1].Modified() {
+		t.Errorf(`Modified() => false, want true`)
+	}
+
+	expected := "% This is the RIPE Database query service.\n" +
+		"\n" +
+		"Mntner:          DEV-MNT  # Comment \n" +
+		"descr:\tNew maintainer\r\n" +
+		"+ # with a comment\n" +
+		"# comment between attributes\n" +
+		"admin-c:         JD1-DEV\n" +
+		"source:          DEV\n" +
+		"remarks:        added\n" +
+		"% trailing comment\n" +
+		"\n" +
+		"\n"
+
+	if string(obj.Bytes()) != expected {
+		t.Fatalf(`Bytes() => %q, want %q`, obj.Bytes(), expected)
+	}
+}
+
+func TestLosslessModifiedLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		value string
+		want  string
+	}{
+		{
+			name:  "Comment",
+			raw:   "remarks:        old value   # keep me\n",
+			value: "new value",
+			want:  "remarks:        new value   # keep me\n",
+		},
+		{
+			name:  "Plus",
+			raw:   "descr:          first # one\n+               second # two\n",
+			value: "first updated second",
+			want:  "descr:          first # one\n+               updated second # two\n",
+		},
+		{
+			name:  "Space",
+			raw:   "descr: a b\n  c d\n",
+			value: "a b c d e",
+			want:  "descr: a b\n  c d e\n",
+		},
+		{
+			name:  "Tab",
+			raw:   "descr: a b\r\n\tc d # tab\r\n",
+			value: "x y z",
+			want:  "descr: x y\r\n\tz # tab\r\n",
+		},
+		{
+			name:  "Shorter",
+			raw:   "descr: a b\n+ c d # comment\n+ e\n",
+			value: "a",
+			want:  "descr: a\n+ # comment\n",
+		},
+		{
+			name:  "EmptyLine",
+			raw:   "remarks: a\n+\n+ b\n",
+			value: "c d",
+			want:  "remarks: c\n+\n+ d\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := ParseWithOptions(tt.raw, ParseOptions{Lossless: true})
+			if err != nil {
+				t.Fatalf(`ParseWithOptions() => %v`, err)
+			}
+
+			comment := obj.Attributes[0].Comment()
+			obj.Attributes[0].Value = tt.value
+			if got := string(obj.Bytes()); got != tt.want {
+				t.Fatalf(`Bytes() => %q, want %q`, got, tt.want)
+			}
+
+			again, err := ParseWithOptions(string(obj.Bytes()), ParseOptions{Lossless: true})
+			if err != nil {
+				t.Fatalf(`ParseWithOptions(Bytes()) => %v`, err)
+			}
+
+			if again.Attributes[0].Value != tt.value || again.Attributes[0].Comment() != comment {
+				t.Errorf(`ParseWithOptions(Bytes()) => %q # %q, want %q # %q`, again.Attributes[0].Value,
+					again.Attributes[0].Comment(), tt.value, comment)
+			}
+		})
+	}
+}
+
+func TestLosslessLenientKeepsSkippedObjects(t *testing.T) {
+	raw := "person:  John Doe\n" +
+		"\n" +
+		"bad key: value\n" +
+		"\n" +
+		"person:  Jane Smith\n"
+
+	objs, err := ParseManyWithOptions(raw, ParseOptions{Lossless: true, Lenient: true})
+	if err == nil {
+		t.Fatalf(`ParseManyWithOptions() => nil error, want error`)
+	}
+
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		buf.Write(obj.Bytes())
+	}
+
+	if buf.String() != raw {
+		t.Fatalf(`Bytes() => %q, want %q`, buf.String(), raw)
+	}
+}
+
+func TestBytesWithoutRaw(t *testing.T) {
+	obj := Object{
+		Attributes: []Attribute{
+			{Name: "person", Value: "John Doe"},
+			{Name: "source", Value: "TEST"},
+		},
+	}
+
+	expected := "person:         John Doe\n" +
+		"source:         TEST\n"
+
+	if string(obj.Bytes()) != expected {
+		t.Fatalf(`Bytes() => %q, want %q`, obj.Bytes(), expected)
+	}
+}
+
+// parseLossless parses the first object of raw in lossless mode.
+func parseLossless(raw string) (*Object, error) {
+	scanner := NewScannerWithOptions(strings.NewReader(raw), ParseOptions{Lossless: true})
+	scanner.Scan()
+	obj := scanner.Object()
+	return &obj, scanner.Err()
+}
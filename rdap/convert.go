@@ -0,0 +1,305 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdap
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// conformance is the rdapConformance of the converted objects.
+var conformance = []string{"rdap_level_0"}
+
+// contactRoles maps the attributes referencing entities to their roles, in the order the entities are listed.
+var contactRoles = []struct {
+	attribute string
+	role      string
+}{
+	{"org", RoleRegistrant},
+	{"admin-c", RoleAdministrative},
+	{"tech-c", RoleTechnical},
+	{"abuse-c", RoleAbuse},
+	{"mnt-by", RoleRegistrant},
+}
+
+// Converter converts RPSL objects to RDAP objects.
+type Converter struct {
+	// Lookup returns the person, role or organisation object with the given handle, or nil if it is unknown. It is
+	// used to embed the vCards of the referenced entities. When nil, entities only have a handle and roles.
+	Lookup func(handle string) *rpsl.Object
+	// Port43 is the host name of the whois server serving the objects.
+	Port43 string
+}
+
+// Convert converts an inetnum, inet6num, aut-num, domain, person, role or organisation object to an *IPNetwork, an
+// *Autnum, a *Domain or an *Entity.
+func (c *Converter) Convert(o *rpsl.Object) (any, error) {
+	switch o.Class() {
+	case "inetnum", "inet6num":
+		return c.IPNetwork(o)
+	case "aut-num":
+		return c.Autnum(o)
+	case "domain":
+		return c.Domain(o)
+	case "person", "role", "organisation":
+		return c.Entity(o)
+	}
+
+	return nil, fmt.Errorf("rdap: cannot convert objects of class '%s'", o.Class())
+}
+
+// IPNetwork converts an inetnum or inet6num object.
+func (c *Converter) IPNetwork(o *rpsl.Object) (*IPNetwork, error) {
+	n := &IPNetwork{IPVersion: "v4"}
+	switch o.Class() {
+	case "inetnum":
+		inetnum, err := rpsl.NewInetnum(o)
+		if err != nil {
+			return nil, err
+		}
+
+		n.StartAddress = inetnum.Start.String()
+		n.EndAddress = inetnum.End.String()
+	case "inet6num":
+		inet6num, err := rpsl.NewInet6num(o)
+		if err != nil {
+			return nil, err
+		}
+
+		n.IPVersion = "v6"
+		n.StartAddress = inet6num.Prefix.Addr().String()
+		n.EndAddress = rpsl.LastAddr(inet6num.Prefix).String()
+	default:
+		return nil, fmt.Errorf("rdap: cannot convert objects of class '%s' to ip network", o.Class())
+	}
+
+	n.Common = c.common(o, "ip network", *o.GetFirst(o.Class()), true)
+	n.Name = value(o, "netname")
+	n.Type = value(o, "status")
+	n.Country = value(o, "country")
+	return n, nil
+}
+
+// Autnum converts an aut-num object.
+func (c *Converter) Autnum(o *rpsl.Object) (*Autnum, error) {
+	autnum, err := rpsl.NewAutNum(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Autnum{
+		Common:      c.common(o, "autnum", "AS"+strconv.FormatUint(uint64(autnum.ASN), 10), true),
+		StartAutnum: autnum.ASN,
+		EndAutnum:   autnum.ASN,
+		Name:        autnum.ASName,
+		Type:        autnum.Status,
+	}, nil
+}
+
+// Domain converts a domain object.
+func (c *Converter) Domain(o *rpsl.Object) (*Domain, error) {
+	if err := o.EnsureClass("domain"); err != nil {
+		return nil, fmt.Errorf("rdap: %w", err)
+	}
+
+	name := strings.TrimSuffix(*o.GetFirst("domain"), ".")
+	d := &Domain{Common: c.common(o, "domain", name, true), LDHName: name}
+	for _, nserver := range o.GetAll("nserver") {
+		if fields := strings.Fields(nserver); len(fields) > 0 {
+			d.Nameservers = append(d.Nameservers, Nameserver{
+				ObjectClassName: "nameserver",
+				LDHName:         strings.TrimSuffix(fields[0], "."),
+			})
+		}
+	}
+
+	for _, rdata := range o.GetAll("ds-rdata") {
+		ds, err := parseDSData(rdata)
+		if err != nil {
+			return nil, err
+		}
+
+		if d.SecureDNS == nil {
+			d.SecureDNS = &SecureDNS{DelegationSigned: true}
+		}
+
+		d.SecureDNS.DSData = append(d.SecureDNS.DSData, ds)
+	}
+
+	return d, nil
+}
+
+// parseDSData parses the value of a ds-rdata attribute: the key tag, the algorithm, the digest type and the digest.
+func parseDSData(value string) (DSData, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return DSData{}, fmt.Errorf("rdap: invalid ds-rdata '%s'", value)
+	}
+
+	var ds DSData
+	var errs [3]error
+	ds.KeyTag, errs[0] = strconv.Atoi(fields[0])
+	ds.Algorithm, errs[1] = strconv.Atoi(fields[1])
+	ds.DigestType, errs[2] = strconv.Atoi(fields[2])
+	for _, err := range errs {
+		if err != nil {
+			return DSData{}, fmt.Errorf("rdap: invalid ds-rdata '%s'", value)
+		}
+	}
+
+	ds.Digest = strings.Join(fields[3:], "")
+	return ds, nil
+}
+
+// Entity converts a person, role or organisation object.
+func (c *Converter) Entity(o *rpsl.Object) (*Entity, error) {
+	e, err := c.entity(o, true)
+	if err != nil {
+		return nil, err
+	}
+
+	e.RDAPConformance = conformance
+	return e, nil
+}
+
+// entity converts a person, role or organisation object, embedding the entities it references if nested is true.
+func (c *Converter) entity(o *rpsl.Object, nested bool) (*Entity, error) {
+	card := VCard{{Name: "version", Type: "text", Value: "4.0"}}
+	handle := ""
+	switch o.Class() {
+	case "person":
+		handle = value(o, "nic-hdl")
+		card = append(card, VCardProperty{Name: "kind", Type: "text", Value: "individual"})
+	case "role":
+		handle = value(o, "nic-hdl")
+		card = append(card, VCardProperty{Name: "kind", Type: "text", Value: "group"})
+	case "organisation":
+		handle = value(o, "organisation")
+		card = append(card, VCardProperty{Name: "kind", Type: "text", Value: "org"})
+	default:
+		return nil, fmt.Errorf("rdap: cannot convert objects of class '%s' to entity", o.Class())
+	}
+
+	if handle == "" {
+		return nil, fmt.Errorf("rdap: %s object has no handle", o.Class())
+	}
+
+	name := value(o, o.Class())
+	if o.Class() == "organisation" {
+		name = value(o, "org-name")
+	}
+
+	card = append(card, VCardProperty{Name: "fn", Type: "text", Value: name})
+	if address := o.GetAll("address"); len(address) > 0 {
+		card = append(card, VCardProperty{
+			Name:   "adr",
+			Params: map[string]any{"label": strings.Join(address, "\n")},
+			Type:   "text",
+			Value:  []any{"", "", "", "", "", "", ""},
+		})
+	}
+
+	for _, phone := range o.GetAll("phone") {
+		card = append(card, VCardProperty{Name: "tel", Params: map[string]any{"type": "voice"}, Type: "text", Value: phone})
+	}
+
+	for _, fax := range o.GetAll("fax-no") {
+		card = append(card, VCardProperty{Name: "tel", Params: map[string]any{"type": "fax"}, Type: "text", Value: fax})
+	}
+
+	for _, email := range o.GetAll("e-mail") {
+		card = append(card, VCardProperty{Name: "email", Type: "text", Value: email})
+	}
+
+	if abuse := value(o, "abuse-mailbox"); abuse != "" {
+		card = append(card, VCardProperty{Name: "email", Params: map[string]any{"type": "abuse"}, Type: "text", Value: abuse})
+	}
+
+	e := &Entity{Common: c.common(o, "entity", handle, nested), VCardArray: card}
+	return e, nil
+}
+
+// common converts the members shared by the object classes, embedding the referenced entities if nested is true.
+func (c *Converter) common(o *rpsl.Object, class string, handle string, nested bool) Common {
+	common := Common{
+		ObjectClassName: class,
+		Handle:          handle,
+		Port43:          c.Port43,
+	}
+
+	if class != "entity" {
+		common.RDAPConformance = conformance
+	}
+
+	if nested {
+		common.Entities = c.entities(o)
+	}
+
+	if descr := o.GetAll("descr"); len(descr) > 0 {
+		common.Remarks = append(common.Remarks, Remark{Title: "description", Description: descr})
+	}
+
+	if remarks := o.GetAll("remarks"); len(remarks) > 0 {
+		common.Remarks = append(common.Remarks, Remark{Description: remarks})
+	}
+
+	if created := value(o, "created"); created != "" {
+		common.Events = append(common.Events, Event{Action: "registration", Date: created})
+	}
+
+	if modified := value(o, "last-modified"); modified != "" {
+		common.Events = append(common.Events, Event{Action: "last changed", Date: modified})
+	}
+
+	return common
+}
+
+// entities returns the entities referenced by the object, with their roles. Entities referenced by several
+// attributes are listed once with all their roles.
+func (c *Converter) entities(o *rpsl.Object) []Entity {
+	var entities []Entity
+	index := map[string]int{}
+	for _, cr := range contactRoles {
+		for _, handle := range o.GetAll(cr.attribute) {
+			i, ok := index[handle]
+			if !ok {
+				i = len(entities)
+				index[handle] = i
+				entities = append(entities, c.reference(handle))
+			}
+
+			if e := &entities[i]; !slices.Contains(e.Roles, cr.role) {
+				e.Roles = append(e.Roles, cr.role)
+			}
+		}
+	}
+
+	return entities
+}
+
+// reference returns the entity with the given handle, with its vCard if it can be looked up.
+func (c *Converter) reference(handle string) Entity {
+	if c.Lookup != nil {
+		if o := c.Lookup(handle); o != nil {
+			if e, err := c.entity(o, false); err == nil {
+				return *e
+			}
+		}
+	}
+
+	return Entity{Common: Common{ObjectClassName: "entity", Handle: handle}}
+}
+
+// value returns the first value of an attribute, or an empty string if it is missing.
+func value(o *rpsl.Object, name string) string {
+	if v := o.GetFirst(name); v != nil {
+		return *v
+	}
+
+	return ""
+}
@@ -0,0 +1,313 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// Unmarshal decodes an RDAP response into RPSL objects: the object of the response, followed by the person, role and
+// organisation objects of the entities with a vCard it embeds.
+func Unmarshal(data []byte) ([]rpsl.Object, error) {
+	var head struct {
+		ObjectClassName string `json:"objectClassName"`
+	}
+
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("rdap: %w", err)
+	}
+
+	var main interface{ Object() (*rpsl.Object, error) }
+	var common *Common
+	switch head.ObjectClassName {
+	case "ip network":
+		n := &IPNetwork{}
+		main, common = n, &n.Common
+	case "autnum":
+		a := &Autnum{}
+		main, common = a, &a.Common
+	case "domain":
+		d := &Domain{}
+		main, common = d, &d.Common
+	case "entity":
+		e := &Entity{}
+		main, common = e, &e.Common
+	default:
+		return nil, fmt.Errorf("rdap: unsupported object class '%s'", head.ObjectClassName)
+	}
+
+	if err := json.Unmarshal(data, main); err != nil {
+		return nil, fmt.Errorf("rdap: %w", err)
+	}
+
+	obj, err := main.Object()
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []rpsl.Object{*obj}
+	seen := map[string]bool{common.Handle: true}
+	var walk func(entities []Entity) error
+	walk = func(entities []Entity) error {
+		for i := range entities {
+			e := &entities[i]
+			if len(e.VCardArray) > 0 && !seen[e.Handle] {
+				seen[e.Handle] = true
+				obj, err := e.Object()
+				if err != nil {
+					return err
+				}
+
+				objs = append(objs, *obj)
+			}
+
+			if err := walk(e.Entities); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(common.Entities); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// Object converts the IPNetwork to an inetnum or inet6num object.
+func (n *IPNetwork) Object() (*rpsl.Object, error) {
+	start, err := netip.ParseAddr(n.StartAddress)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: invalid start address '%s'", n.StartAddress)
+	}
+
+	end, err := netip.ParseAddr(n.EndAddress)
+	if err != nil || end.Is4() != start.Is4() || end.Less(start) {
+		return nil, fmt.Errorf("rdap: invalid end address '%s'", n.EndAddress)
+	}
+
+	var b builder
+	if start.Is4() {
+		b.add("inetnum", start.String()+" - "+end.String())
+	} else {
+		prefix, ok := rangePrefix(start, end)
+		if !ok {
+			return nil, fmt.Errorf("rdap: range %s - %s is not a prefix", start, end)
+		}
+
+		b.add("inet6num", prefix.String())
+	}
+
+	b.add("netname", n.Name)
+	b.remarks(&n.Common, "description")
+	b.add("country", n.Country)
+	b.contacts(&n.Common)
+	b.add("status", n.Type)
+	return b.finish(&n.Common), nil
+}
+
+// Object converts the Autnum to an aut-num object.
+func (a *Autnum) Object() (*rpsl.Object, error) {
+	if a.StartAutnum != a.EndAutnum {
+		return nil, fmt.Errorf("rdap: range AS%d - AS%d is not a single AS", a.StartAutnum, a.EndAutnum)
+	}
+
+	var b builder
+	b.add("aut-num", "AS"+strconv.FormatUint(uint64(a.StartAutnum), 10))
+	b.add("as-name", a.Name)
+	b.remarks(&a.Common, "description")
+	b.contacts(&a.Common)
+	b.add("status", a.Type)
+	return b.finish(&a.Common), nil
+}
+
+// Object converts the Domain to a domain object.
+func (d *Domain) Object() (*rpsl.Object, error) {
+	if d.LDHName == "" {
+		return nil, fmt.Errorf("rdap: domain has no name")
+	}
+
+	var b builder
+	b.add("domain", d.LDHName)
+	b.remarks(&d.Common, "description")
+	b.contacts(&d.Common)
+	for _, ns := range d.Nameservers {
+		b.add("nserver", ns.LDHName)
+	}
+
+	if d.SecureDNS != nil {
+		for _, ds := range d.SecureDNS.DSData {
+			b.add("ds-rdata", fmt.Sprintf("%d %d %d %s", ds.KeyTag, ds.Algorithm, ds.DigestType, ds.Digest))
+		}
+	}
+
+	return b.finish(&d.Common), nil
+}
+
+// Object converts the Entity to a person, role or organisation object, depending on the kind of its vCard.
+func (e *Entity) Object() (*rpsl.Object, error) {
+	if e.Handle == "" {
+		return nil, fmt.Errorf("rdap: entity has no handle")
+	}
+
+	card := e.VCardArray
+	var b builder
+	switch card.Text("kind") {
+	case "org":
+		b.add("organisation", e.Handle)
+		b.add("org-name", card.Text("fn"))
+	case "group":
+		b.add("role", card.Text("fn"))
+	default:
+		b.add("person", card.Text("fn"))
+	}
+
+	for _, adr := range card.Get("adr") {
+		address := adr.Text()
+		if label, ok := adr.Params["label"].(string); ok {
+			address = label
+		}
+
+		for _, line := range strings.Split(address, "\n") {
+			b.add("address", line)
+		}
+	}
+
+	for _, tel := range card.Get("tel") {
+		if !tel.HasType("fax") {
+			b.add("phone", strings.TrimPrefix(tel.Text(), "tel:"))
+		}
+	}
+
+	for _, tel := range card.Get("tel") {
+		if tel.HasType("fax") {
+			b.add("fax-no", strings.TrimPrefix(tel.Text(), "tel:"))
+		}
+	}
+
+	for _, email := range card.Get("email") {
+		if !email.HasType("abuse") {
+			b.add("e-mail", email.Text())
+		}
+	}
+
+	if b.o.Class() == "organisation" {
+		b.contacts(&e.Common)
+	} else {
+		b.add("nic-hdl", e.Handle)
+	}
+
+	if b.o.Class() == "role" {
+		for _, email := range card.Get("email") {
+			if email.HasType("abuse") {
+				b.add("abuse-mailbox", email.Text())
+			}
+		}
+	}
+
+	return b.finish(&e.Common), nil
+}
+
+// builder builds an RPSL object, skipping empty values.
+type builder struct {
+	o rpsl.Object
+}
+
+// add appends an attribute, unless its value is empty.
+func (b *builder) add(name string, value string) {
+	if value != "" {
+		b.o.Attributes = append(b.o.Attributes, rpsl.Attribute{Name: name, Value: value})
+	}
+}
+
+// remarks appends the lines of the remarks with the given title as descr attributes.
+func (b *builder) remarks(c *Common, title string) {
+	for _, r := range c.Remarks {
+		if r.Title == title {
+			for _, line := range r.Description {
+				b.add("descr", line)
+			}
+		}
+	}
+}
+
+// contacts appends an attribute for every role of the entities.
+func (b *builder) contacts(c *Common) {
+	for _, cr := range contactRoles {
+		for _, e := range c.Entities {
+			if !containsRole(e.Roles, cr.role) {
+				continue
+			}
+
+			// Registrants are either organisations or maintainers.
+			if cr.role == RoleRegistrant && isOrganisation(&e) != (cr.attribute == "org") {
+				continue
+			}
+
+			b.add(cr.attribute, e.Handle)
+		}
+	}
+}
+
+// finish appends the remarks without a description title and the events, and returns the object.
+func (b *builder) finish(c *Common) *rpsl.Object {
+	for _, r := range c.Remarks {
+		if r.Title != "description" {
+			for _, line := range r.Description {
+				b.add("remarks", line)
+			}
+		}
+	}
+
+	for _, e := range c.Events {
+		switch e.Action {
+		case "registration":
+			b.add("created", e.Date)
+		case "last changed":
+			b.add("last-modified", e.Date)
+		}
+	}
+
+	return &b.o
+}
+
+// containsRole returns true if roles contains role, ignoring case.
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isOrganisation returns true if the entity is an organisation rather than a maintainer.
+func isOrganisation(e *Entity) bool {
+	if kind := e.VCardArray.Text("kind"); kind != "" {
+		return kind == "org"
+	}
+
+	return strings.HasPrefix(strings.ToUpper(e.Handle), "ORG-")
+}
+
+// rangePrefix returns the prefix spanning exactly from start to end.
+func rangePrefix(start netip.Addr, end netip.Addr) (netip.Prefix, bool) {
+	for bits := 0; bits <= start.BitLen(); bits++ {
+		prefix := netip.PrefixFrom(start, bits)
+		if prefix.Masked().Addr() == start && rpsl.LastAddr(prefix) == end {
+			return prefix, true
+		}
+	}
+
+	return netip.Prefix{}, false
+}
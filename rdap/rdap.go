@@ -0,0 +1,251 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rdap converts RPSL objects to and from the Registration Data Access Protocol (RDAP) responses defined by
+// RFC 9083. The inetnum and inet6num classes map to ip network objects, aut-num to autnum, domain to domain, and
+// person, role and organisation to entity objects, whose contacts are represented as jCards (RFC 7095).
+//
+// Example:
+//
+//	c := rdap.Converter{Port43: "whois.ripe.net"}
+//	network, err := c.IPNetwork(obj)
+//	if err != nil {
+//	    log.Fatalf("Failed to convert object: %v", err)
+//	}
+//
+//	body, err := json.Marshal(network)
+package rdap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Roles of the entities, derived from the attributes referencing them.
+const (
+	RoleRegistrant     = "registrant"
+	RoleAdministrative = "administrative"
+	RoleTechnical      = "technical"
+	RoleAbuse          = "abuse"
+)
+
+// Common holds the members shared by the RDAP object classes.
+type Common struct {
+	RDAPConformance []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName"`
+	Handle          string   `json:"handle,omitempty"`
+	Status          []string `json:"status,omitempty"`
+	Entities        []Entity `json:"entities,omitempty"`
+	Remarks         []Remark `json:"remarks,omitempty"`
+	Links           []Link   `json:"links,omitempty"`
+	Events          []Event  `json:"events,omitempty"`
+	Port43          string   `json:"port43,omitempty"`
+	Notices         []Remark `json:"notices,omitempty"`
+}
+
+// IPNetwork is an ip network object, converted from an inetnum or inet6num object.
+type IPNetwork struct {
+	Common
+	StartAddress string `json:"startAddress"`
+	EndAddress   string `json:"endAddress"`
+	IPVersion    string `json:"ipVersion"`
+	Name         string `json:"name,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Country      string `json:"country,omitempty"`
+	ParentHandle string `json:"parentHandle,omitempty"`
+}
+
+// Autnum is an autnum object, converted from an aut-num object.
+type Autnum struct {
+	Common
+	StartAutnum uint32 `json:"startAutnum"`
+	EndAutnum   uint32 `json:"endAutnum"`
+	Name        string `json:"name,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Country     string `json:"country,omitempty"`
+}
+
+// Domain is a domain object, converted from a domain object.
+type Domain struct {
+	Common
+	LDHName     string       `json:"ldhName"`
+	Nameservers []Nameserver `json:"nameservers,omitempty"`
+	SecureDNS   *SecureDNS   `json:"secureDNS,omitempty"`
+}
+
+// Nameserver is a nameserver of a Domain.
+type Nameserver struct {
+	ObjectClassName string `json:"objectClassName"`
+	LDHName         string `json:"ldhName"`
+}
+
+// SecureDNS holds the DNSSEC delegation signer records of a Domain.
+type SecureDNS struct {
+	DelegationSigned bool     `json:"delegationSigned"`
+	DSData           []DSData `json:"dsData,omitempty"`
+}
+
+// DSData is a delegation signer record.
+type DSData struct {
+	KeyTag     int    `json:"keyTag"`
+	Algorithm  int    `json:"algorithm"`
+	DigestType int    `json:"digestType"`
+	Digest     string `json:"digest"`
+}
+
+// Entity is an entity object, converted from a person, role or organisation object, or a reference to one with only
+// a Handle and Roles.
+type Entity struct {
+	Common
+	VCardArray VCard    `json:"vcardArray,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+}
+
+// Remark is a remark or a notice.
+type Remark struct {
+	Title       string   `json:"title,omitempty"`
+	Description []string `json:"description"`
+	Links       []Link   `json:"links,omitempty"`
+}
+
+// Link is a link to a related resource.
+type Link struct {
+	Value string `json:"value,omitempty"`
+	Rel   string `json:"rel,omitempty"`
+	Href  string `json:"href"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Event is an event in the life of an object, such as "registration" or "last changed".
+type Event struct {
+	Action string `json:"eventAction"`
+	Actor  string `json:"eventActor,omitempty"`
+	Date   string `json:"eventDate"`
+}
+
+// VCard is a jCard, encoded as ["vcard", [properties...]].
+type VCard []VCardProperty
+
+// VCardProperty is a property of a VCard, encoded as [name, parameters, type, value].
+type VCardProperty struct {
+	Name   string
+	Params map[string]any
+	Type   string
+	Value  any
+}
+
+// MarshalJSON encodes the VCard as a jCard.
+func (v VCard) MarshalJSON() ([]byte, error) {
+	props := make([]any, len(v))
+	for i, p := range v {
+		params := p.Params
+		if params == nil {
+			params = map[string]any{}
+		}
+
+		props[i] = []any{p.Name, params, p.Type, p.Value}
+	}
+
+	return json.Marshal([]any{"vcard", props})
+}
+
+// UnmarshalJSON decodes a jCard.
+func (v *VCard) UnmarshalJSON(data []byte) error {
+	var card []json.RawMessage
+	if err := json.Unmarshal(data, &card); err != nil {
+		return err
+	}
+
+	var kind string
+	if len(card) != 2 || json.Unmarshal(card[0], &kind) != nil || kind != "vcard" {
+		return errors.New("rdap: invalid jCard")
+	}
+
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(card[1], &props); err != nil {
+		return fmt.Errorf("rdap: invalid jCard properties: %w", err)
+	}
+
+	*v = make(VCard, 0, len(props))
+	for _, raw := range props {
+		var p VCardProperty
+		if len(raw) < 4 {
+			return errors.New("rdap: invalid jCard property")
+		}
+
+		if err := errors.Join(
+			json.Unmarshal(raw[0], &p.Name),
+			json.Unmarshal(raw[1], &p.Params),
+			json.Unmarshal(raw[2], &p.Type),
+			json.Unmarshal(raw[3], &p.Value),
+		); err != nil {
+			return fmt.Errorf("rdap: invalid jCard property: %w", err)
+		}
+
+		p.Name = strings.ToLower(p.Name)
+		*v = append(*v, p)
+	}
+
+	return nil
+}
+
+// Get returns the properties with the given name.
+func (v VCard) Get(name string) []VCardProperty {
+	var props []VCardProperty
+	for _, p := range v {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+
+	return props
+}
+
+// Text returns the value of the first property with the given name, or an empty string if there is none.
+func (v VCard) Text(name string) string {
+	for _, p := range v {
+		if p.Name == name {
+			return p.Text()
+		}
+	}
+
+	return ""
+}
+
+// Text returns the value of the property as a string. Structured values have their non-empty components joined with
+// a newline.
+func (p *VCardProperty) Text() string {
+	switch value := p.Value.(type) {
+	case string:
+		return value
+	case []any:
+		var parts []string
+		for _, part := range value {
+			if s, ok := part.(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+
+		return strings.Join(parts, "\n")
+	}
+
+	return ""
+}
+
+// HasType returns true if the "type" parameter of the property contains t.
+func (p *VCardProperty) HasType(t string) bool {
+	switch value := p.Params["type"].(type) {
+	case string:
+		return strings.EqualFold(value, t)
+	case []any:
+		for _, v := range value {
+			if s, ok := v.(string); ok && strings.EqualFold(s, t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
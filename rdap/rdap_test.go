@@ -0,0 +1,211 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdap
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+const testObjects = `inetnum:        192.0.2.0 - 192.0.2.255
+netname:        EXAMPLE-NET
+descr:          Example network
+country:        NL
+org:            ORG-EX1-TEST
+admin-c:        JD1-TEST
+tech-c:         JD1-TEST
+abuse-c:        AR1-TEST
+status:         ASSIGNED PA
+mnt-by:         EX-MNT
+created:        2024-01-02T03:04:05Z
+source:         TEST
+
+person:         John Doe
+address:        1 Example Street
+address:        Amsterdam
+phone:          +31 20 000 0000
+e-mail:         john@example.net
+nic-hdl:        JD1-TEST
+source:         TEST
+
+role:           Abuse Role
+address:        Amsterdam
+e-mail:         noc@example.net
+nic-hdl:        AR1-TEST
+abuse-mailbox:  abuse@example.net
+source:         TEST
+
+organisation:   ORG-EX1-TEST
+org-name:       Example
+org-type:       OTHER
+address:        Amsterdam
+e-mail:         info@example.net
+source:         TEST
+`
+
+// newTestConverter returns the objects of testObjects and a Converter looking them up.
+func newTestConverter(t *testing.T) ([]rpsl.Object, *Converter) {
+	objs, err := rpsl.ParseMany(testObjects)
+	if err != nil {
+		t.Fatalf(`ParseMany => %v`, err)
+	}
+
+	lookup := func(handle string) *rpsl.Object {
+		for i := range objs {
+			if objs[i].Class() != "inetnum" && (handle == value(&objs[i], "nic-hdl") || handle == value(&objs[i], "organisation")) {
+				return &objs[i]
+			}
+		}
+
+		return nil
+	}
+
+	return objs, &Converter{Lookup: lookup, Port43: "whois.example.net"}
+}
+
+func TestIPNetwork(t *testing.T) {
+	objs, c := newTestConverter(t)
+	n, err := c.IPNetwork(&objs[0])
+	if err != nil {
+		t.Fatalf(`IPNetwork => %v`, err)
+	}
+
+	if n.StartAddress != "192.0.2.0" || n.EndAddress != "192.0.2.255" || n.IPVersion != "v4" || n.Name != "EXAMPLE-NET" {
+		t.Errorf(`IPNetwork => %+v`, n)
+	}
+
+	roles := map[string][]string{}
+	for _, e := range n.Entities {
+		roles[e.Handle] = e.Roles
+	}
+
+	want := map[string][]string{
+		"ORG-EX1-TEST": {RoleRegistrant},
+		"JD1-TEST":     {RoleAdministrative, RoleTechnical},
+		"AR1-TEST":     {RoleAbuse},
+		"EX-MNT":       {RoleRegistrant},
+	}
+
+	if !reflect.DeepEqual(roles, want) {
+		t.Errorf(`IPNetwork.Entities => %v, want %v`, roles, want)
+	}
+
+	if fn := n.Entities[1].VCardArray.Text("fn"); fn != "John Doe" {
+		t.Errorf(`IPNetwork.Entities[1] fn => %q, want "John Doe"`, fn)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	objs, c := newTestConverter(t)
+	n, err := c.IPNetwork(&objs[0])
+	if err != nil {
+		t.Fatalf(`IPNetwork => %v`, err)
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf(`json.Marshal => %v`, err)
+	}
+
+	back, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf(`Unmarshal => %v`, err)
+	}
+
+	if len(back) != 4 {
+		t.Fatalf(`Unmarshal => %d objects, want 4`, len(back))
+	}
+
+	want := "inetnum:192.0.2.0 - 192.0.2.255\nnetname:EXAMPLE-NET\ndescr:Example network\ncountry:NL\n" +
+		"org:ORG-EX1-TEST\nadmin-c:JD1-TEST\ntech-c:JD1-TEST\nabuse-c:AR1-TEST\nmnt-by:EX-MNT\n" +
+		"status:ASSIGNED PA\ncreated:2024-01-02T03:04:05Z"
+	if got := back[0].String(); got != want {
+		t.Errorf(`Unmarshal => %q, want %q`, got, want)
+	}
+
+	person := "person:John Doe\naddress:1 Example Street\naddress:Amsterdam\nphone:+31 20 000 0000\n" +
+		"e-mail:john@example.net\nnic-hdl:JD1-TEST"
+	role := "role:Abuse Role\naddress:Amsterdam\ne-mail:noc@example.net\nnic-hdl:AR1-TEST\n" +
+		"abuse-mailbox:abuse@example.net"
+	for _, want := range []string{person, role} {
+		found := false
+		for _, obj := range back[1:] {
+			found = found || obj.String() == want
+		}
+
+		if !found {
+			t.Errorf(`Unmarshal => %v, want %q`, back[1:], want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	c := &Converter{}
+	tests := []struct {
+		object string
+		class  string
+	}{
+		{"inet6num: 2001:db8::/32\nnetname: EXAMPLE\nsource: TEST", "ip network"},
+		{"aut-num: AS64496\nas-name: EXAMPLE\nsource: TEST", "autnum"},
+		{"domain: 2.0.192.in-addr.arpa\nnserver: ns1.example.net\nds-rdata: 52151 1 1 13ee60f7499a70e5aadaf05828e7fc59e8e70bc1\nsource: TEST", "domain"},
+		{"role: Example\nnic-hdl: EX1-TEST\nsource: TEST", "entity"},
+	}
+
+	for _, tt := range tests {
+		obj, err := rpsl.Parse(tt.object)
+		if err != nil {
+			t.Fatalf(`Parse => %v`, err)
+		}
+
+		converted, err := c.Convert(obj)
+		if err != nil {
+			t.Fatalf(`Convert(%s) => %v`, obj.Class(), err)
+		}
+
+		data, _ := json.Marshal(converted)
+		back, err := Unmarshal(data)
+		if err != nil {
+			t.Fatalf(`Unmarshal(%s) => %v`, data, err)
+		}
+
+		var head struct {
+			ObjectClassName string `json:"objectClassName"`
+		}
+
+		_ = json.Unmarshal(data, &head)
+		if head.ObjectClassName != tt.class {
+			t.Errorf(`Convert(%s) => %s, want %s`, obj.Class(), head.ObjectClassName, tt.class)
+		}
+
+		// The source is not part of RDAP responses.
+		obj.Attributes = obj.Attributes[:len(obj.Attributes)-1]
+		if back[0].String() != obj.String() {
+			t.Errorf(`Unmarshal(Convert(%s)) => %q, want %q`, obj.Class(), back[0].String(), obj.String())
+		}
+	}
+
+	if _, err := c.Convert(&rpsl.Object{Attributes: []rpsl.Attribute{{Name: "mntner", Value: "EX-MNT"}}}); err == nil {
+		t.Errorf(`Convert(mntner) => nil, want an error`)
+	}
+}
+
+func TestVCard(t *testing.T) {
+	data := `["vcard",[["version",{},"text","4.0"],["fn",{},"text","Joe"],["tel",{"type":["work","fax"]},"uri","tel:+1-555"]]]`
+	var card VCard
+	if err := json.Unmarshal([]byte(data), &card); err != nil {
+		t.Fatalf(`json.Unmarshal => %v`, err)
+	}
+
+	tel := card.Get("tel")
+	if card.Text("fn") != "Joe" || len(tel) != 1 || !tel[0].HasType("fax") || tel[0].HasType("voice") {
+		t.Errorf(`VCard => %+v`, card)
+	}
+
+	if err := json.Unmarshal([]byte(`["vcard"]`), &card); err == nil {
+		t.Errorf(`json.Unmarshal(invalid) => nil, want an error`)
+	}
+}
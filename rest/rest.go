@@ -0,0 +1,292 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rest converts RPSL objects from and to the JSON and XML formats of the RIPE Database REST API.
+//
+// Example:
+//
+//	objs, err := rest.Unmarshal(body)
+//	if err != nil {
+//	    log.Fatalf("Failed to decode response: %v", err)
+//	}
+//
+//	for _, obj := range objs {
+//	    fmt.Println(obj.String())
+//	}
+package rest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// WhoisResources is the document returned by the REST API, named whois-resources in XML.
+type WhoisResources struct {
+	XMLName            xml.Name       `json:"-" xml:"whois-resources"`
+	Link               *Link          `json:"link,omitempty" xml:"link,omitempty"`
+	Objects            *Objects       `json:"objects,omitempty" xml:"objects,omitempty"`
+	ErrorMessages      *ErrorMessages `json:"errormessages,omitempty" xml:"errormessages,omitempty"`
+	TermsAndConditions *Link          `json:"terms-and-conditions,omitempty" xml:"terms-and-conditions,omitempty"`
+}
+
+// Objects is the list of objects of a WhoisResources.
+type Objects struct {
+	Object []Object `json:"object" xml:"object"`
+}
+
+// Object is an RPSL object as represented by the REST API.
+type Object struct {
+	Type       string      `json:"type" xml:"type,attr"`
+	Link       *Link       `json:"link,omitempty" xml:"link,omitempty"`
+	Source     *Source     `json:"source,omitempty" xml:"source,omitempty"`
+	PrimaryKey *Attributes `json:"primary-key,omitempty" xml:"primary-key,omitempty"`
+	Attributes Attributes  `json:"attributes" xml:"attributes"`
+}
+
+// Attributes is a list of attributes.
+type Attributes struct {
+	Attribute []Attribute `json:"attribute" xml:"attribute"`
+}
+
+// Attribute is an attribute of an Object. Attributes referencing another object have a ReferencedType, and a Link
+// to the referenced object when known.
+type Attribute struct {
+	Link           *Link  `json:"link,omitempty" xml:"link,omitempty"`
+	Name           string `json:"name" xml:"name,attr"`
+	Value          string `json:"value" xml:"value,attr"`
+	ReferencedType string `json:"referenced-type,omitempty" xml:"referenced-type,attr,omitempty"`
+	Comment        string `json:"comment,omitempty" xml:"comment,attr,omitempty"`
+}
+
+// Link is an XLink locator.
+type Link struct {
+	Type string `json:"type" xml:"http://www.w3.org/1999/xlink type,attr"`
+	Href string `json:"href" xml:"http://www.w3.org/1999/xlink href,attr"`
+}
+
+// Source is the source of an Object, in lowercase.
+type Source struct {
+	ID string `json:"id" xml:"id,attr"`
+}
+
+// ErrorMessages is the list of messages of a WhoisResources.
+type ErrorMessages struct {
+	ErrorMessage []ErrorMessage `json:"errormessage" xml:"errormessage"`
+}
+
+// ErrorMessage is a message returned by the REST API. Its Text is a format string whose '%s' verbs are replaced by
+// the Args.
+type ErrorMessage struct {
+	Severity  string     `json:"severity" xml:"severity,attr"`
+	Attribute *Attribute `json:"attribute,omitempty" xml:"attribute,omitempty"`
+	Text      string     `json:"text" xml:"text,attr"`
+	Args      []Arg      `json:"args,omitempty" xml:"args,omitempty"`
+}
+
+// Arg is an argument of an ErrorMessage.
+type Arg struct {
+	Value string `json:"value" xml:"value,attr"`
+}
+
+// Error returns the text of the ErrorMessage, with its arguments.
+func (m *ErrorMessage) Error() string {
+	text := m.Text
+	for _, arg := range m.Args {
+		text = strings.Replace(text, "%s", arg.Value, 1)
+	}
+
+	return strings.TrimSpace(text)
+}
+
+// Options configures the conversion of RPSL objects.
+type Options struct {
+	// BaseURL is the URL of the REST API, such as "https://rest.db.ripe.net". When set, links to the objects and to the
+	// objects they reference are added.
+	BaseURL string
+}
+
+// NewObject converts an RPSL object.
+func NewObject(o *rpsl.Object) Object {
+	return NewObjectWithOptions(o, Options{})
+}
+
+// NewObjectWithOptions converts an RPSL object with the given options.
+func NewObjectWithOptions(o *rpsl.Object, opts Options) Object {
+	class := o.Class()
+	source := ""
+	if value := o.GetFirst("source"); value != nil {
+		source = strings.ToLower(*value)
+	}
+
+	obj := Object{Type: class}
+	if source != "" {
+		obj.Source = &Source{ID: source}
+	}
+
+	if t := rpsl.LookupTemplate(class); t != nil {
+		key := ""
+		obj.PrimaryKey = &Attributes{}
+		for _, name := range t.Keys(rpsl.PrimaryKey) {
+			if value := o.GetFirst(name); value != nil {
+				obj.PrimaryKey.Attribute = append(obj.PrimaryKey.Attribute, Attribute{Name: name, Value: *value})
+				key += *value
+			}
+		}
+
+		obj.Link = opts.link(source, class, key)
+	}
+
+	obj.Attributes.Attribute = make([]Attribute, len(o.Attributes))
+	for i := range o.Attributes {
+		a := &o.Attributes[i]
+		attr := Attribute{
+			Name:           a.Name,
+			Value:          a.Value,
+			ReferencedType: ReferencedType(class, a.Name, a.Value),
+			Comment:        a.Comment(),
+		}
+
+		if attr.ReferencedType != "" {
+			attr.Link = opts.link(source, attr.ReferencedType, strings.Fields(a.Value)[0])
+		}
+
+		obj.Attributes.Attribute[i] = attr
+	}
+
+	return obj
+}
+
+// link returns the link to an object, or nil if there is no base URL.
+func (opts *Options) link(source string, class string, key string) *Link {
+	if opts.BaseURL == "" || source == "" || key == "" {
+		return nil
+	}
+
+	href := strings.TrimSuffix(opts.BaseURL, "/") + "/" + source + "/" + class + "/" + key
+	return &Link{Type: "locator", Href: href}
+}
+
+// Object converts the Object to an RPSL object. Comments are kept in the Raw field of the attributes.
+func (o *Object) Object() *rpsl.Object {
+	obj := &rpsl.Object{Attributes: make([]rpsl.Attribute, len(o.Attributes.Attribute))}
+	for i, a := range o.Attributes.Attribute {
+		attr := rpsl.Attribute{Name: strings.ToLower(a.Name), Value: a.Value}
+		if a.Comment != "" {
+			attr.Raw = &rpsl.RawAttribute{Comments: []string{a.Comment}}
+		}
+
+		obj.Attributes[i] = attr
+	}
+
+	return obj
+}
+
+// NewWhoisResources converts RPSL objects to a WhoisResources.
+func NewWhoisResources(objs []rpsl.Object, opts Options) *WhoisResources {
+	w := &WhoisResources{Objects: &Objects{Object: make([]Object, len(objs))}}
+	for i := range objs {
+		w.Objects.Object[i] = NewObjectWithOptions(&objs[i], opts)
+	}
+
+	return w
+}
+
+// Result returns the RPSL objects of the WhoisResources. If it has no object but an error message, the first
+// ErrorMessage of severity "Error" is returned as the error.
+func (w *WhoisResources) Result() ([]rpsl.Object, error) {
+	if w.Objects == nil || len(w.Objects.Object) == 0 {
+		if w.ErrorMessages != nil {
+			for i := range w.ErrorMessages.ErrorMessage {
+				if m := &w.ErrorMessages.ErrorMessage[i]; strings.EqualFold(m.Severity, "error") {
+					return nil, m
+				}
+			}
+		}
+
+		return nil, nil
+	}
+
+	objs := make([]rpsl.Object, len(w.Objects.Object))
+	for i := range w.Objects.Object {
+		objs[i] = *w.Objects.Object[i].Object()
+	}
+
+	return objs, nil
+}
+
+// Unmarshal decodes the RPSL objects of a JSON document.
+func Unmarshal(data []byte) ([]rpsl.Object, error) {
+	var w WhoisResources
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("rest: %w", err)
+	}
+
+	return w.Result()
+}
+
+// UnmarshalXML decodes the RPSL objects of an XML document.
+func UnmarshalXML(data []byte) ([]rpsl.Object, error) {
+	var w WhoisResources
+	if err := xml.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("rest: %w", err)
+	}
+
+	return w.Result()
+}
+
+// Marshal encodes RPSL objects to a JSON document.
+func Marshal(objs []rpsl.Object) ([]byte, error) {
+	return json.Marshal(NewWhoisResources(objs, Options{}))
+}
+
+// MarshalXML encodes RPSL objects to an XML document.
+func MarshalXML(objs []rpsl.Object) ([]byte, error) {
+	data, err := xml.Marshal(NewWhoisResources(objs, Options{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// ReferencedType returns the class of the object referenced by an attribute of an object of the given class, or an
+// empty string if the attribute does not reference a single object. Contacts are assumed to be person objects, except
+// abuse-c which references a role object.
+func ReferencedType(class string, name string, value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || strings.Contains(value, ",") || strings.EqualFold(fields[0], "ANY") {
+		return ""
+	}
+
+	switch strings.ToLower(name) {
+	case "mnt-by", "mnt-lower", "mnt-routes", "mnt-domains", "mnt-ref", "mbrs-by-ref":
+		return "mntner"
+	case "admin-c", "tech-c", "zone-c", "ping-hdl", "author":
+		return "person"
+	case "abuse-c":
+		return "role"
+	case "org", "sponsoring-org":
+		return "organisation"
+	case "mnt-irt":
+		return "irt"
+	case "origin", "local-as":
+		return "aut-num"
+	case "form":
+		return "poetic-form"
+	case "member-of":
+		switch strings.ToLower(class) {
+		case "aut-num":
+			return "as-set"
+		case "route", "route6":
+			return "route-set"
+		case "inet-rtr":
+			return "rtr-set"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,147 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+const testJSON = `{
+  "link": {"type": "locator", "href": "https://rest.db.ripe.net/ripe/aut-num/AS3333"},
+  "objects": {
+    "object": [{
+      "type": "aut-num",
+      "link": {"type": "locator", "href": "https://rest.db.ripe.net/ripe/aut-num/AS3333"},
+      "source": {"id": "ripe"},
+      "primary-key": {"attribute": [{"name": "aut-num", "value": "AS3333"}]},
+      "attributes": {
+        "attribute": [
+          {"name": "aut-num", "value": "AS3333"},
+          {"name": "as-name", "value": "RIPE-NCC-AS"},
+          {"name": "remarks", "value": "Reseaux IP Europeens", "comment": "RIPE"},
+          {"link": {"type": "locator", "href": "https://rest.db.ripe.net/ripe/mntner/RIPE-NCC-MNT"},
+           "name": "mnt-by", "value": "RIPE-NCC-MNT", "referenced-type": "mntner"},
+          {"name": "source", "value": "RIPE"}
+        ]
+      }
+    }]
+  },
+  "terms-and-conditions": {"type": "locator", "href": "http://www.ripe.net/db/support/db-terms-conditions.pdf"}
+}`
+
+const testXML = `<?xml version="1.0" encoding="UTF-8" standalone="no" ?>
+<whois-resources xmlns:xlink="http://www.w3.org/1999/xlink">
+  <objects>
+    <object type="aut-num">
+      <link xlink:type="locator" xlink:href="https://rest.db.ripe.net/ripe/aut-num/AS3333"/>
+      <source id="ripe"/>
+      <primary-key><attribute name="aut-num" value="AS3333"/></primary-key>
+      <attributes>
+        <attribute name="aut-num" value="AS3333"/>
+        <attribute name="as-name" value="RIPE-NCC-AS"/>
+        <attribute name="remarks" value="Reseaux IP Europeens" comment="RIPE"/>
+        <attribute name="mnt-by" value="RIPE-NCC-MNT" referenced-type="mntner">
+          <link xlink:type="locator" xlink:href="https://rest.db.ripe.net/ripe/mntner/RIPE-NCC-MNT"/>
+        </attribute>
+        <attribute name="source" value="RIPE"/>
+      </attributes>
+    </object>
+  </objects>
+</whois-resources>`
+
+const testObject = "aut-num:AS3333\nas-name:RIPE-NCC-AS\nremarks:Reseaux IP Europeens\nmnt-by:RIPE-NCC-MNT\nsource:RIPE"
+
+func TestUnmarshal(t *testing.T) {
+	for name, unmarshal := range map[string]func([]byte) ([]rpsl.Object, error){
+		"json": func(data []byte) ([]rpsl.Object, error) { return Unmarshal(data) },
+		"xml":  func(data []byte) ([]rpsl.Object, error) { return UnmarshalXML(data) },
+	} {
+		data := testJSON
+		if name == "xml" {
+			data = testXML
+		}
+
+		objs, err := unmarshal([]byte(data))
+		if err != nil {
+			t.Fatalf(`%s: Unmarshal => %v`, name, err)
+		}
+
+		if len(objs) != 1 || objs[0].String() != testObject {
+			t.Fatalf(`%s: Unmarshal => %v, want %q`, name, objs, testObject)
+		}
+
+		if comment := objs[0].Attributes[2].Comment(); comment != "RIPE" {
+			t.Errorf(`%s: Attribute.Comment => %q, want "RIPE"`, name, comment)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	objs, err := Unmarshal([]byte(testJSON))
+	if err != nil {
+		t.Fatalf(`Unmarshal => %v`, err)
+	}
+
+	w := NewWhoisResources(objs, Options{BaseURL: "https://rest.db.ripe.net/"})
+	data, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf(`json.Marshal => %v`, err)
+	}
+
+	var want, got WhoisResources
+	_ = json.Unmarshal([]byte(testJSON), &want)
+	_ = json.Unmarshal(data, &got)
+	wantObj, _ := json.Marshal(want.Objects)
+	gotObj, _ := json.Marshal(got.Objects)
+	if string(gotObj) != string(wantObj) {
+		t.Errorf(`NewWhoisResources => %s, want %s`, gotObj, wantObj)
+	}
+
+	xmlData, err := MarshalXML(objs)
+	if err != nil {
+		t.Fatalf(`MarshalXML => %v`, err)
+	}
+
+	back, err := UnmarshalXML(xmlData)
+	if err != nil || len(back) != 1 || back[0].String() != testObject {
+		t.Errorf(`UnmarshalXML(MarshalXML) => %v, %v`, back, err)
+	}
+}
+
+func TestErrorMessages(t *testing.T) {
+	data := `{"errormessages": {"errormessage": [{"severity": "Error",
+		"text": "ERROR:101: no entries found\n\nNo entries found in source %s.\n", "args": [{"value": "RIPE"}]}]}}`
+
+	_, err := Unmarshal([]byte(data))
+	var m *ErrorMessage
+	if !errors.As(err, &m) || !strings.HasSuffix(m.Error(), "in source RIPE.") {
+		t.Errorf(`Unmarshal => %v, want an ErrorMessage`, err)
+	}
+}
+
+func TestReferencedType(t *testing.T) {
+	tests := []struct {
+		class, name, value, want string
+	}{
+		{"route", "origin", "AS64496", "aut-num"},
+		{"route", "member-of", "RS-FOO", "route-set"},
+		{"aut-num", "member-of", "AS-FOO", "as-set"},
+		{"aut-num", "member-of", "AS-FOO, AS-BAR", ""},
+		{"inetnum", "mnt-routes", "FOO-MNT {192.0.2.0/24}", "mntner"},
+		{"as-set", "mbrs-by-ref", "ANY", ""},
+		{"inetnum", "abuse-c", "AR1-RIPE", "role"},
+		{"inetnum", "netname", "EXAMPLE", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ReferencedType(tt.class, tt.name, tt.value); got != tt.want {
+			t.Errorf(`ReferencedType(%q, %q, %q) => %q, want %q`, tt.class, tt.name, tt.value, got, tt.want)
+		}
+	}
+}
@@ -52,7 +52,29 @@ func Parse(raw string) (*Object, error) {
 //
 //	fmt.Printf("Parsed Object: %+v\n", obj)
 func ParseFromReader(r io.Reader) (*Object, error) {
-	objects, err := parseObjects(r)
+	return ParseFromReaderWithOptions(r, ParseOptions{})
+}
+
+// ParseWithOptions parses a string containing a single RPSL object according to opts and returns a representation of
+// the parsed data.
+// If the string contains multiple objects, an error will be returned.
+// If the string is empty, an error will be returned.
+// Lenient mode is not supported, as there is no other object to keep going with.
+func ParseWithOptions(raw string, opts ParseOptions) (*Object, error) {
+	return ParseFromReaderWithOptions(strings.NewReader(raw), opts)
+}
+
+// ParseFromReaderWithOptions parses an RPSL object from an io.Reader according to opts and returns a representation of
+// the parsed data.
+// If the reader contains multiple objects, an error will be returned.
+// If the reader is empty, an error will be returned.
+// Lenient mode is not supported, as there is no other object to keep going with.
+func ParseFromReaderWithOptions(r io.Reader, opts ParseOptions) (*Object, error) {
+	if opts.Lenient {
+		return nil, errors.New("lenient mode is not supported when parsing a single object")
+	}
+
+	objects, err := ParseManyFromReaderWithOptions(r, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -120,14 +142,66 @@ func ParseMany(raw string) ([]Object, error) {
 //	    fmt.Printf("Parsed Object: %+v\n", obj)
 //	}
 func ParseManyFromReader(r io.Reader) ([]Object, error) {
-	objects, err := parseObjects(r)
-	if err != nil {
+	return ParseManyFromReaderWithOptions(r, ParseOptions{})
+}
+
+// ParseOptions controls how RPSL objects are parsed.
+type ParseOptions struct {
+	// Lenient skips malformed objects up to the next empty line instead of aborting the whole parse. The errors of the
+	// skipped objects are reported as ParseErrors alongside the objects that could be parsed.
+	Lenient bool
+
+	// Lossless records the original text of every object and attribute, including comments, key casing, spacing and
+	// continuation lines, so that Object.Bytes can reproduce the input byte-for-byte.
+	Lossless bool
+}
+
+// ParseManyWithOptions parses a string containing multiple RPSL objects according to opts and returns a
+// representation of the parsed data.
+// If the string does not contain any objects, nil will be returned.
+// In lenient mode, both the parsed objects and the ParseErrors of the skipped objects are returned.
+//
+// Example:
+//
+//	objs, err := ParseManyWithOptions(raw, ParseOptions{Lenient: true})
+//
+//	var report ParseErrors
+//	if errors.As(err, &report) {
+//		for _, perr := range report {
+//			log.Printf("Skipped object %d at line %d: %v", perr.Object, perr.Line, perr.Err)
+//		}
+//	} else if err != nil {
+//		log.Fatalf("Failed to parse RPSL objects: %v", err)
+//	}
+//
+//	for _, obj := range objs {
+//		fmt.Printf("Parsed Object: %+v\n", obj)
+//	}
+func ParseManyWithOptions(raw string, opts ParseOptions) ([]Object, error) {
+	return ParseManyFromReaderWithOptions(strings.NewReader(raw), opts)
+}
+
+// ParseManyFromReaderWithOptions parses multiple RPSL objects from an io.Reader according to opts and returns a
+// representation of the parsed data.
+// If the reader does not contain any objects, nil will be returned.
+// In lenient mode, both the parsed objects and the ParseErrors of the skipped objects are returned.
+func ParseManyFromReaderWithOptions(r io.Reader, opts ParseOptions) ([]Object, error) {
+	// Start with a small capacity that will grow if needed.
+	objects := make([]Object, 0, 4)
+
+	scanner := NewScannerWithOptions(r, opts)
+	for scanner.Scan() {
+		objects = append(objects, scanner.Object())
+	}
+
+	err := scanner.Err()
+	if _, ok := err.(ParseErrors); err != nil && !ok {
 		return nil, err
 	}
 
 	if len(objects) == 0 {
-		return nil, nil
+		return nil, err
 	}
 
-	return objects, nil
+	return objects, err
 }
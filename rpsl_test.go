@@ -540,3 +540,79 @@ func BenchmarkParseFromReader(b *testing.B) {
 		}
 	}
 }
+
+func TestParseManyLenient(t *testing.T) {
+	raw := "person:  John Doe\n" +
+		"source:  TEST\n" +
+		"\n" +
+		"this is not a valid RPSL object\n" +
+		"source:  TEST\n" +
+		"\n" +
+		"person:  Jane Smith\n" +
+		"source:  TEST\n" +
+		"\n" +
+		"@person: Alice Brown\n"
+
+	objs, err := ParseManyWithOptions(raw, ParseOptions{Lenient: true})
+	if len(objs) != 2 {
+		t.Fatalf("ParseManyWithOptions objects count: got %v, want 2", len(objs))
+	}
+
+	if *objs[1].GetFirst("person") != "Jane Smith" {
+		t.Fatalf("ParseManyWithOptions second object: got %v, want %v", *objs[1].GetFirst("person"), "Jane Smith")
+	}
+
+	var report ParseErrors
+	if !errors.As(err, &report) {
+		t.Fatalf("ParseManyWithOptions error: got %v, want ParseErrors", err)
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("ParseManyWithOptions errors count: got %v, want 2", len(report))
+	}
+
+	if report[0].Object != 1 || report[0].Line != 4 {
+		t.Errorf("ParseManyWithOptions first error: got object %v line %v, want object 1 line 4", report[0].Object, report[0].Line)
+	}
+
+	if report[1].Object != 3 || report[1].Line != 10 {
+		t.Errorf("ParseManyWithOptions second error: got object %v line %v, want object 3 line 10", report[1].Object, report[1].Line)
+	}
+
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr != report[0] {
+		t.Errorf("errors.As(ParseError) => %v, want first error", perr)
+	}
+}
+
+func TestParseManyLenientWithoutErrors(t *testing.T) {
+	objs, err := ParseManyWithOptions("person: John Doe\n\nperson: Jane Smith", ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("ParseManyWithOptions error: %v", err)
+	}
+
+	if len(objs) != 2 {
+		t.Fatalf("ParseManyWithOptions objects count: got %v, want 2", len(objs))
+	}
+}
+
+func TestParseLenientUnsupported(t *testing.T) {
+	if obj, err := ParseWithOptions("person: John Doe", ParseOptions{Lenient: true}); err == nil {
+		t.Errorf("ParseWithOptions(Lenient) => %v, want an error", obj)
+	}
+
+	if _, err := ParseWithOptions("person: John Doe", ParseOptions{Lossless: true}); err != nil {
+		t.Errorf("ParseWithOptions(Lossless) error: %v", err)
+	}
+}
+
+func TestParseManyStrictDiscardsObjects(t *testing.T) {
+	objs, err := ParseManyWithOptions("person: John Doe\n\nbad key: value", ParseOptions{})
+	if err == nil {
+		t.Fatalf("ParseManyWithOptions error: got nil, want error")
+	}
+
+	if objs != nil {
+		t.Fatalf("ParseManyWithOptions objects: got %v, want nil", objs)
+	}
+}
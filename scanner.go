@@ -0,0 +1,340 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+)
+
+// Scanner reads RPSL objects one at a time from an io.Reader. Successive calls to Scan step through the objects of the
+// input, so that arbitrarily large dumps can be processed without holding every object in memory at once.
+//
+// The internal line and object buffers are reused between calls to Scan. The Object returned by Object does not
+// reference these buffers and remains valid after subsequent calls to Scan.
+//
+// Example:
+//
+//	file, err := os.Open("ripe.db.inetnum")
+//	if err != nil {
+//	    log.Fatalf("Failed to open file: %v", err)
+//	}
+//	defer file.Close()
+//
+//	scanner := NewScanner(file)
+//	for scanner.Scan() {
+//	    obj := scanner.Object()
+//	    fmt.Printf("Parsed Object: %+v\n", obj)
+//	}
+//
+//	if err := scanner.Err(); err != nil {
+//	    log.Fatalf("Failed to parse RPSL objects: %v", err)
+//	}
+type Scanner struct {
+	lines   *bufio.Scanner
+	buf     *bytes.Buffer
+	numbers []int // Input line number of every line in buf.
+	line    int   // Number of lines read so far.
+	index   int   // Index of the next object.
+	ended   bool  // Whether the current object was terminated by an empty line.
+	carry   bool  // Whether the last line read starts the next object.
+	opts    ParseOptions
+	object  Object
+	errs    ParseErrors // Errors of the objects skipped in lenient mode.
+	err     error
+	done    bool
+
+	// Lossless mode only.
+	raw    *bytes.Buffer // Original text of the current object.
+	starts []int         // Offset in raw of every line in buf.
+	ends   []int         // Offset in raw of the end of every line in buf.
+	kept   bool          // Whether raw holds the text of a skipped object.
+}
+
+// NewScanner returns a new Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	return NewScannerWithOptions(r, ParseOptions{})
+}
+
+// NewScannerWithOptions returns a new Scanner reading from r and parsing according to opts.
+func NewScannerWithOptions(r io.Reader, opts ParseOptions) *Scanner {
+	// Pre-allocate a buffer for the scanner, but increase max size
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 0, 512), 4*1024*1024) // Allow large lines.
+	lines.Split(scanRawLines)
+
+	s := &Scanner{
+		lines: lines,
+		buf:   bytes.NewBuffer(make([]byte, 0, 512)),
+		opts:  opts,
+	}
+
+	if opts.Lossless {
+		s.raw = bytes.NewBuffer(make([]byte, 0, 512))
+	}
+
+	return s
+}
+
+// Scan advances the Scanner to the next object, which will then be available through the Object method. It returns
+// false when the scan stops, either by reaching the end of the input or an error. After Scan returns false, the Err
+// method will return any error that occurred during scanning, except that if it was io.EOF, Err will return nil.
+//
+// In lenient mode, malformed objects are skipped and Scan only stops at the end of the input or on a read error.
+func (s *Scanner) Scan() bool {
+	if s.done {
+		return false
+	}
+
+	s.object = Object{}
+	for {
+		obj, ok := s.next()
+		if !ok {
+			s.done = true
+			return false
+		}
+
+		// Skip objects that did not yield any attribute.
+		if len(obj.Attributes) > 0 {
+			s.object = obj
+			return true
+		}
+	}
+}
+
+// Object returns the most recent object read by a call to Scan.
+func (s *Scanner) Object() Object {
+	return s.object
+}
+
+// Err returns the first non-EOF error that was encountered by the Scanner. In lenient mode, the errors of the skipped
+// objects are reported as ParseErrors once the scan stops, unless a read error occurred.
+func (s *Scanner) Err() error {
+	if s.err == nil && s.done && len(s.errs) > 0 {
+		return s.errs
+	}
+
+	return s.err
+}
+
+// next reads the lines of the next object from the input and parses them. An object ends at the first line that
+// starts another object after an empty line, so that the empty and comment lines following an object are kept with
+// it. It returns false once the input is exhausted or an error occurred.
+func (s *Scanner) next() (Object, bool) {
+	s.reset()
+
+	// Process the input line by line.
+	for s.carry || s.lines.Scan() {
+		if !s.carry {
+			s.line++
+		}
+
+		s.carry = false
+		raw := s.lines.Bytes()
+		line := dropLineEnding(raw)
+
+		// Skip comment lines.
+		if len(line) > 0 && (line[0] == '%' || line[0] == '#') {
+			s.keep(raw)
+			continue
+		}
+
+		// Handle empty lines.
+		if len(line) == 0 {
+			if s.buf.Len() > 0 {
+				s.ended = true
+			}
+
+			s.keep(raw)
+			continue
+		}
+
+		// The line starts the next object, process it on the next call.
+		if s.ended {
+			s.carry = true
+			return s.parse()
+		}
+
+		// Add the line to the current object.
+		if s.buf.Len() > 0 {
+			s.buf.WriteByte('\n')
+		}
+
+		s.buf.Write(line)
+		s.numbers = append(s.numbers, s.line)
+		if s.raw != nil {
+			s.starts = append(s.starts, s.raw.Len())
+			s.raw.Write(raw)
+			s.ends = append(s.ends, s.raw.Len())
+		}
+	}
+
+	if err := s.lines.Err(); err != nil {
+		s.err = &ParseError{Object: s.index, Line: s.line + 1, Err: err}
+		return Object{}, false
+	}
+
+	// Don't forget the last object if there is one.
+	if s.buf.Len() > 0 {
+		return s.parse()
+	}
+
+	return Object{}, false
+}
+
+// reset clears the buffers of the current object.
+func (s *Scanner) reset() {
+	s.buf.Reset()
+	s.numbers = s.numbers[:0]
+	s.ended = false
+
+	if s.raw != nil {
+		if !s.kept {
+			s.raw.Reset()
+		}
+
+		s.starts = s.starts[:0]
+		s.ends = s.ends[:0]
+		s.kept = false
+	}
+}
+
+// keep records the original text of a line that is not part of an attribute.
+func (s *Scanner) keep(raw []byte) {
+	if s.raw != nil {
+		s.raw.Write(raw)
+	}
+}
+
+// parse parses the buffered object.
+func (s *Scanner) parse() (Object, bool) {
+	index := s.index
+	s.index++
+
+	attributes, err := parseAttributesAt(s.buf.Bytes(), s.numbers)
+	if err != nil {
+		perr, ok := err.(*ParseError)
+		if !ok {
+			s.err = err
+			return Object{}, false
+		}
+
+		perr.Object = index
+		if s.opts.Lenient {
+			// Skip the object, but keep its text in front of the next one.
+			s.errs = append(s.errs, perr)
+			s.kept = true
+			return Object{}, true
+		}
+
+		s.err = perr
+		return Object{}, false
+	}
+
+	obj := Object{Attributes: attributes}
+	if s.raw != nil {
+		s.attachRaw(&obj)
+	}
+
+	return obj, true
+}
+
+// attachRaw records the original text of the buffered object in obj and its attributes.
+func (s *Scanner) attachRaw(obj *Object) {
+	raw := s.raw.Bytes()
+
+	// Find the first buffered line of every attribute, lines are in increasing order.
+	first := make([]int, len(obj.Attributes)+1)
+	k := 0
+	for i := range obj.Attributes {
+		for s.numbers[k] != obj.Attributes[i].Line {
+			k++
+		}
+
+		first[i] = k
+	}
+	first[len(obj.Attributes)] = len(s.numbers)
+
+	last := s.ends[len(s.ends)-1]
+	obj.Raw = &RawObject{
+		Leading:  string(raw[:s.starts[0]]),
+		Trailing: string(raw[last:]),
+	}
+
+	prev := s.starts[0]
+	for i := range obj.Attributes {
+		a, b := first[i], first[i+1]
+		obj.Attributes[i].Raw = newRawAttribute(&obj.Attributes[i], raw, prev, s.starts[a:b], s.ends[a:b])
+		prev = s.ends[b-1]
+	}
+}
+
+// scanRawLines is a split function for a bufio.Scanner that returns each line of text, including its line ending.
+func scanRawLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+
+	// If we're at EOF, we have a final, non-terminated line.
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	// Request more data.
+	return 0, nil, nil
+}
+
+// dropLineEnding drops a terminal \n or \r\n from the line.
+func dropLineEnding(line []byte) []byte {
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		line = line[:n-1]
+	}
+
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+
+	return line
+}
+
+// Objects returns an iterator over the RPSL objects read from r. Iteration stops at the first error, which is yielded
+// together with a zero Object. Breaking out of the loop stops reading from r.
+//
+// See ObjectsWithOptions to skip malformed objects.
+//
+// Example:
+//
+//	for obj, err := range Objects(file) {
+//	    if err != nil {
+//	        log.Fatalf("Failed to parse RPSL objects: %v", err)
+//	    }
+//
+//	    fmt.Printf("Parsed Object: %+v\n", obj)
+//	}
+func Objects(r io.Reader) iter.Seq2[Object, error] {
+	return ObjectsWithOptions(r, ParseOptions{})
+}
+
+// ObjectsWithOptions returns an iterator over the RPSL objects read from r, parsed according to opts. In lenient mode,
+// the errors of the skipped objects are yielded as ParseErrors after the last object.
+func ObjectsWithOptions(r io.Reader, opts ParseOptions) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		scanner := NewScannerWithOptions(r, opts)
+		for scanner.Scan() {
+			if !yield(scanner.Object(), nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(Object{}, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	raw := "% Comment at the top\n" +
+		"\n" +
+		"person:  John Doe\n" +
+		"source:  TEST\n" +
+		"\n" +
+		"\n" +
+		"person:  Jane Smith\n" +
+		"source:  TEST\n"
+
+	scanner := NewScanner(strings.NewReader(raw))
+
+	names := make([]string, 0)
+	for scanner.Scan() {
+		obj := scanner.Object()
+		names = append(names, *obj.GetFirst("person"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf(`scanner.Err() => %v`, err)
+	}
+
+	if len(names) != 2 {
+		t.Fatalf(`scanner.Scan() => %v objects, want %v`, len(names), 2)
+	}
+
+	if names[0] != "John Doe" || names[1] != "Jane Smith" {
+		t.Fatalf(`scanner.Object() => %v, want %v`, names, []string{"John Doe", "Jane Smith"})
+	}
+
+	if scanner.Scan() {
+		t.Fatalf(`scanner.Scan() => true after end of input, want false`)
+	}
+}
+
+func TestScannerObjectOutlivesScan(t *testing.T) {
+	raw := "person:  John Doe\n" +
+		"\n" +
+		"person:  Jane Smith\n"
+
+	scanner := NewScanner(strings.NewReader(raw))
+	if !scanner.Scan() {
+		t.Fatalf(`scanner.Scan() => false, want true`)
+	}
+
+	first := scanner.Object()
+	if !scanner.Scan() {
+		t.Fatalf(`scanner.Scan() => false, want true`)
+	}
+
+	if first.Attributes[0].Value != "John Doe" {
+		t.Fatalf(`first.Attributes[0].Value => %v, want %v`, first.Attributes[0].Value, "John Doe")
+	}
+}
+
+func TestScannerError(t *testing.T) {
+	raw := "person:  John Doe\n" +
+		"\n" +
+		"this is not a valid RPSL object\n" +
+		"\n" +
+		"person:  Jane Smith\n"
+
+	scanner := NewScanner(strings.NewReader(raw))
+
+	count := 0
+	for scanner.Scan() {
+		count++
+	}
+
+	if count != 1 {
+		t.Fatalf(`scanner.Scan() => %v objects, want %v`, count, 1)
+	}
+
+	if err := scanner.Err(); err == nil || !strings.Contains(err.Error(), "parseKey: illegal character") {
+		t.Fatalf(`scanner.Err() => %v, want illegal character error`, err)
+	}
+}
+
+func TestObjectsIterator(t *testing.T) {
+	raw := "person:  John Doe\n" +
+		"\n" +
+		"person:  Jane Smith\n" +
+		"\n" +
+		"person:  Alice Brown\n"
+
+	count := 0
+	for obj, err := range Objects(strings.NewReader(raw)) {
+		if err != nil {
+			t.Fatalf(`Objects() => %v`, err)
+		}
+
+		count++
+		if *obj.GetFirst("person") == "Jane Smith" {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Fatalf(`Objects() => %v objects before break, want %v`, count, 2)
+	}
+}
+
+func TestObjectsIteratorError(t *testing.T) {
+	raw := "person:  John Doe\n" +
+		"\n" +
+		"@invalid: value\n"
+
+	var last error
+	count := 0
+	for _, err := range Objects(strings.NewReader(raw)) {
+		if err != nil {
+			last = err
+			continue
+		}
+
+		count++
+	}
+
+	if count != 1 {
+		t.Fatalf(`Objects() => %v objects, want %v`, count, 1)
+	}
+
+	if last == nil || !strings.Contains(last.Error(), "illegal character") {
+		t.Fatalf(`Objects() => error %v, want illegal character error`, last)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// Match selects the objects returned by a range lookup, relative to the queried range.
+type Match uint8
+
+const (
+	// MatchExact selects the objects with the same range.
+	MatchExact Match = iota
+	// MatchOneLess selects the smallest objects strictly containing the range.
+	MatchOneLess
+	// MatchAllLess selects the objects containing the range, including the exact match.
+	MatchAllLess
+	// MatchOneMore selects the largest objects strictly contained in the range.
+	MatchOneMore
+	// MatchAllMore selects the objects strictly contained in the range.
+	MatchAllMore
+)
+
+// Database looks up the objects served by a Server. It must be safe for concurrent use.
+type Database interface {
+	// Lookup returns the objects having a lookup key attribute with the value key, ignoring case.
+	Lookup(key string) []rpsl.Object
+	// Inverse returns the objects having an attribute with the given name referencing key, ignoring case.
+	Inverse(attribute string, key string) []rpsl.Object
+	// Range returns the inetnum, inet6num, route and route6 objects related to the range from start to end. Every
+	// class is matched independently.
+	Range(start netip.Addr, end netip.Addr, match Match) []rpsl.Object
+}
+
+// memoryDatabase is a Database scanning a slice of objects.
+type memoryDatabase struct {
+	objs []rpsl.Object
+}
+
+// NewMemoryDatabase returns a Database over a slice of objects, which must not be modified afterwards. Every lookup
+// scans all the objects, which is suitable for small databases and tests.
+func NewMemoryDatabase(objs []rpsl.Object) Database {
+	return &memoryDatabase{objs: objs}
+}
+
+// Lookup returns the objects having a lookup key attribute with the value key.
+func (db *memoryDatabase) Lookup(key string) []rpsl.Object {
+	var objs []rpsl.Object
+	for _, o := range db.objs {
+		t := rpsl.LookupTemplate(o.Class())
+		if t == nil {
+			continue
+		}
+
+		for _, name := range t.Keys(rpsl.LookupKey) {
+			if value := o.GetFirst(name); value != nil && strings.EqualFold(*value, key) {
+				objs = append(objs, o)
+				break
+			}
+		}
+	}
+
+	return objs
+}
+
+// Inverse returns the objects having an attribute with the given name referencing key.
+func (db *memoryDatabase) Inverse(attribute string, key string) []rpsl.Object {
+	var objs []rpsl.Object
+	for _, o := range db.objs {
+		for _, value := range o.GetAll(attribute) {
+			if references(value, key) {
+				objs = append(objs, o)
+				break
+			}
+		}
+	}
+
+	return objs
+}
+
+// references returns true if the value of an attribute, possibly a comma-separated list, references key.
+func references(value string, key string) bool {
+	for _, item := range strings.Split(value, ",") {
+		if fields := strings.Fields(item); len(fields) > 0 && strings.EqualFold(fields[0], key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Range returns the objects related to the range from start to end.
+func (db *memoryDatabase) Range(start netip.Addr, end netip.Addr, match Match) []rpsl.Object {
+	q := span{start, end}
+	byClass := map[string][]rangedObject{}
+	var classes []string
+	for _, o := range db.objs {
+		s, ok := objectSpan(&o)
+		if !ok || s.start.Is4() != start.Is4() {
+			continue
+		}
+
+		var selected bool
+		switch match {
+		case MatchExact:
+			selected = s == q
+		case MatchOneLess:
+			selected = s.contains(q) && s != q
+		case MatchAllLess:
+			selected = s.contains(q)
+		case MatchOneMore, MatchAllMore:
+			selected = q.contains(s) && s != q
+		}
+
+		if selected {
+			if _, ok := byClass[o.Class()]; !ok {
+				classes = append(classes, o.Class())
+			}
+
+			byClass[o.Class()] = append(byClass[o.Class()], rangedObject{o, s})
+		}
+	}
+
+	var objs []rpsl.Object
+	for _, class := range classes {
+		candidates := byClass[class]
+		for _, c := range candidates {
+			if match == MatchOneLess && !smallest(c.span, candidates) || match == MatchOneMore && !largest(c.span, candidates) {
+				continue
+			}
+
+			objs = append(objs, c.obj)
+		}
+	}
+
+	return objs
+}
+
+// rangedObject is an object and its address range.
+type rangedObject struct {
+	obj  rpsl.Object
+	span span
+}
+
+// span is an address range.
+type span struct {
+	start, end netip.Addr
+}
+
+// contains returns true if s contains o.
+func (s span) contains(o span) bool {
+	return s.start.Compare(o.start) <= 0 && o.end.Compare(s.end) <= 0
+}
+
+// smallest returns true if no other candidate is strictly contained in s.
+func smallest(s span, candidates []rangedObject) bool {
+	for _, c := range candidates {
+		if c.span != s && s.contains(c.span) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// largest returns true if no other candidate strictly contains s.
+func largest(s span, candidates []rangedObject) bool {
+	for _, c := range candidates {
+		if c.span != s && c.span.contains(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// objectSpan returns the address range of an inetnum, inet6num, route or route6 object.
+func objectSpan(o *rpsl.Object) (span, bool) {
+	switch o.Class() {
+	case "inetnum", "inet6num", "route", "route6":
+	default:
+		return span{}, false
+	}
+
+	start, end, err := rpsl.ParseRange(*o.GetFirst(o.Class()))
+	if err != nil {
+		return span{}, false
+	}
+
+	return span{start, end}, true
+}
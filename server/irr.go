@@ -0,0 +1,324 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+// irrState is the state of the IRRd commands of a connection.
+type irrState struct {
+	// persistent is set by the "!!" command.
+	persistent bool
+	// sources are the sources selected by the "!s" command.
+	sources []string
+}
+
+// answerIRR writes the answer to an IRRd command, the line following the "!". It returns false if the connection
+// must be closed.
+func (s *Server) answerIRR(buf *bytes.Buffer, command string, st *irrState) bool {
+	if command == "!" {
+		st.persistent = true
+		return true
+	}
+
+	if command == "q" {
+		return false
+	}
+
+	data, err := s.irrCommand(command, st)
+	_ = whois.WriteIRRResponse(buf, data, err)
+	return true
+}
+
+// irrCommand returns the data of the answer to an IRRd command, the line following the "!".
+func (s *Server) irrCommand(command string, st *irrState) ([]byte, error) {
+	if command == "" {
+		return nil, irrErrorf("Missing command")
+	}
+
+	arg := command[1:]
+	switch command[0] {
+	case 'g':
+		return s.irrOrigin(arg, "route", st)
+	case '6':
+		return s.irrOrigin(arg, "route6", st)
+	case 'i':
+		return s.irrMembers(arg, st)
+	case 'a':
+		return s.irrSetPrefixes(arg, st)
+	case 'r':
+		return s.irrRoutes(arg, st)
+	case 'm':
+		class, key, ok := strings.Cut(arg, ",")
+		if !ok || key == "" {
+			return nil, irrErrorf("Invalid !m command, expected !m<class>,<key>")
+		}
+
+		objs := s.irrFilter(s.db.Lookup(key), st, strings.ToLower(class))
+		return formatObjects(objs)
+	case 's':
+		return s.irrSources(arg, st)
+	case 'v':
+		return []byte(s.opts.Version), nil
+	default:
+		return nil, irrErrorf("Unrecognized command '%c'", command[0])
+	}
+}
+
+// irrOrigin answers the "!g" and "!6" commands with the prefixes of the objects of the class originated by an AS.
+func (s *Server) irrOrigin(arg string, class string, st *irrState) ([]byte, error) {
+	asn, err := rpsl.ParseASN(strings.TrimSpace(arg))
+	if err != nil {
+		return nil, irrErrorf("Invalid AS number '%s'", arg)
+	}
+
+	var prefixes []string
+	for _, o := range s.irrFilter(s.db.Inverse("origin", fmt.Sprintf("AS%d", asn)), st, class) {
+		if prefix := o.Attributes[0].Value; !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return []byte(strings.Join(prefixes, " ")), nil
+}
+
+// irrMembers answers the "!i" command with the members of an as-set or route-set, expanded recursively with ",1".
+func (s *Server) irrMembers(arg string, st *irrState) ([]byte, error) {
+	name, recursive := strings.CutSuffix(strings.TrimSpace(arg), ",1")
+	set := s.irrSet(name, st)
+	if set == nil {
+		return nil, whois.ErrKeyNotFound
+	}
+
+	if recursive {
+		if s.opts.Resolver == nil {
+			return nil, irrErrorf("Recursive set expansion is not supported")
+		}
+
+		var members []string
+		if set.Class() == "as-set" {
+			asns, err := s.opts.Resolver.ASNs(name)
+			if err != nil {
+				return nil, irrErrorf("%v", err)
+			}
+
+			for _, asn := range asns {
+				members = append(members, fmt.Sprintf("AS%d", asn))
+			}
+		} else {
+			prefixes, err := s.opts.Resolver.Prefixes(name)
+			if err != nil {
+				return nil, irrErrorf("%v", err)
+			}
+
+			for _, prefix := range prefixes {
+				members = append(members, prefix.String())
+			}
+		}
+
+		return []byte(strings.Join(members, " ")), nil
+	}
+
+	var members []string
+	add := func(member string) {
+		if !slices.Contains(members, member) {
+			members = append(members, member)
+		}
+	}
+
+	for _, value := range append(set.GetAll("members"), set.GetAll("mp-members")...) {
+		for _, member := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			add(member)
+		}
+	}
+
+	for _, o := range s.referencedBy(set, st) {
+		add(o.Attributes[0].Value)
+	}
+
+	return []byte(strings.Join(members, " ")), nil
+}
+
+// irrSetPrefixes answers the "!a", "!a4" and "!a6" commands with the prefixes of the routes of an as-set or route-set.
+func (s *Server) irrSetPrefixes(arg string, st *irrState) ([]byte, error) {
+	family := 0
+	if strings.HasPrefix(arg, "4") || strings.HasPrefix(arg, "6") {
+		family = int(arg[0] - '0')
+		arg = arg[1:]
+	}
+
+	name := strings.TrimSpace(arg)
+	if rpsl.CheckASNumber(name) != nil && s.irrSet(name, st) == nil {
+		return nil, whois.ErrKeyNotFound
+	}
+
+	if s.opts.Resolver == nil {
+		return nil, irrErrorf("Set expansion is not supported")
+	}
+
+	ranges, err := s.opts.Resolver.Prefixes(name)
+	if err != nil {
+		return nil, irrErrorf("%v", err)
+	}
+
+	var prefixes []string
+	for _, r := range ranges {
+		if family == 4 && !r.Prefix.Addr().Is4() || family == 6 && r.Prefix.Addr().Is4() {
+			continue
+		}
+
+		if prefix := r.Prefix.String(); !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return []byte(strings.Join(prefixes, " ")), nil
+}
+
+// irrRoutes answers the "!r" command with the route and route6 objects of a prefix. The options are "o" for the
+// origins of the exact match, "l" for the one level less specific, "L" for all less specific and "M" for all more
+// specific objects.
+func (s *Server) irrRoutes(arg string, st *irrState) ([]byte, error) {
+	text, option, _ := strings.Cut(strings.TrimSpace(arg), ",")
+	prefix, err := netip.ParsePrefix(text)
+	if err != nil {
+		return nil, irrErrorf("Invalid prefix '%s'", text)
+	}
+
+	match := MatchExact
+	switch option {
+	case "", "o":
+	case "l":
+		match = MatchOneLess
+	case "L":
+		match = MatchAllLess
+	case "M":
+		match = MatchAllMore
+	default:
+		return nil, irrErrorf("Invalid option '%s'", option)
+	}
+
+	start, end, _ := rpsl.ParseRange(prefix.Masked().String())
+	objs := filterObjects(s.db.Range(start, end, match), func(o *rpsl.Object) bool {
+		return o.Class() == "route" || o.Class() == "route6"
+	})
+
+	objs = s.irrFilter(objs, st, "")
+	if len(objs) == 0 {
+		return nil, whois.ErrKeyNotFound
+	}
+
+	if option != "o" {
+		return formatObjects(objs)
+	}
+
+	var origins []string
+	for _, o := range objs {
+		if origin := o.GetFirst("origin"); origin != nil && !slices.Contains(origins, *origin) {
+			origins = append(origins, *origin)
+		}
+	}
+
+	return []byte(strings.Join(origins, " ")), nil
+}
+
+// irrSources answers the "!s" command. "!s-lc" lists the selected sources, and "!s<source>,..." selects sources.
+func (s *Server) irrSources(arg string, st *irrState) ([]byte, error) {
+	if arg == "-lc" {
+		sources := st.sources
+		if len(sources) == 0 {
+			sources = s.opts.Sources
+		}
+
+		return []byte(strings.Join(sources, ",")), nil
+	}
+
+	sources := strings.Split(arg, ",")
+	for _, source := range sources {
+		if source == "" || len(s.opts.Sources) > 0 && !containsFold(s.opts.Sources, source) {
+			return nil, irrErrorf("Unknown source '%s'", source)
+		}
+	}
+
+	st.sources = sources
+	return nil, nil
+}
+
+// irrSet returns the as-set or route-set with the given name, or nil if it does not exist.
+func (s *Server) irrSet(name string, st *irrState) *rpsl.Object {
+	for _, o := range s.irrFilter(s.db.Lookup(name), st, "") {
+		if o.Class() == "as-set" || o.Class() == "route-set" {
+			return &o
+		}
+	}
+
+	return nil
+}
+
+// referencedBy returns the objects that are a member of a set through their member-of attribute, and are maintained
+// by one of the maintainers of the mbrs-by-ref attribute of the set.
+func (s *Server) referencedBy(set *rpsl.Object, st *irrState) []rpsl.Object {
+	maintainers := set.GetAll("mbrs-by-ref")
+	if len(maintainers) == 0 {
+		return nil
+	}
+
+	classes := []string{"aut-num"}
+	if set.Class() == "route-set" {
+		classes = []string{"route", "route6"}
+	}
+
+	var members []rpsl.Object
+	for _, o := range s.irrFilter(s.db.Inverse("member-of", set.Attributes[0].Value), st, "") {
+		if !slices.Contains(classes, o.Class()) {
+			continue
+		}
+
+		if slices.ContainsFunc(o.GetAll("mnt-by"), func(mntner string) bool {
+			return containsFold(maintainers, mntner) || containsFold(maintainers, "ANY")
+		}) {
+			members = append(members, o)
+		}
+	}
+
+	return members
+}
+
+// irrFilter returns the objects of the selected sources and, unless class is empty, of the given class.
+func (s *Server) irrFilter(objs []rpsl.Object, st *irrState, class string) []rpsl.Object {
+	return filterObjects(objs, func(o *rpsl.Object) bool {
+		return (class == "" || o.Class() == class) && (len(st.sources) == 0 || containsFold(st.sources, source(o)))
+	})
+}
+
+// formatObjects returns the text of the objects, without credentials, or ErrKeyNotFound if there are none.
+func formatObjects(objs []rpsl.Object) ([]byte, error) {
+	if len(objs) == 0 {
+		return nil, whois.ErrKeyNotFound
+	}
+
+	var buf bytes.Buffer
+	for i := range objs {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+
+		_ = formatter.WriteObject(&buf, filter(&objs[i], true))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// irrErrorf returns an error sent in an "F" answer.
+func irrErrorf(format string, args ...any) error {
+	return &whois.IRRError{Message: fmt.Sprintf(format, args...)}
+}
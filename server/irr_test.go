@@ -0,0 +1,97 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/policy"
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+func TestIRRCommands(t *testing.T) {
+	objs, err := rpsl.ParseMany(testDatabase)
+	if err != nil {
+		t.Fatalf(`ParseMany => %v`, err)
+	}
+
+	_, addr := newTestServer(t, Options{Sources: []string{"TEST"}, Resolver: policy.NewSetResolver(objs)})
+	c := whois.NewClientWithOptions(addr, whois.ClientOptions{Timeout: 5 * time.Second})
+	s, err := c.OpenIRR(context.Background())
+	if err != nil {
+		t.Fatalf(`OpenIRR => %v`, err)
+	}
+
+	defer s.Close()
+	tests := []struct {
+		command string
+		want    string
+		err     error
+	}{
+		{"!gAS64497", "192.0.2.0/24", nil},
+		{"!6AS64496", "2001:db8::/32", nil},
+		{"!gAS64511", "", nil},
+		{"!iAS-TEST", "AS3333 AS-NESTED AS64497", nil},
+		{"!iAS-TEST,1", "AS3333 AS64496 AS64497", nil},
+		{"!iAS-UNKNOWN", "", whois.ErrKeyNotFound},
+		{"!a4AS-TEST", "192.0.2.0/24 193.0.0.0/21", nil},
+		{"!a6AS-TEST", "2001:db8::/32", nil},
+		{"!r193.0.0.0/21,o", "AS3333", nil},
+		{"!r198.51.100.0/24", "", whois.ErrKeyNotFound},
+		{"!s-lc", "TEST", nil},
+		{"!sTEST", "", nil},
+		{"!sOTHER", "", &whois.IRRError{Message: "Unknown source 'OTHER'"}},
+		{"!x", "", &whois.IRRError{Message: "Unrecognized command 'x'"}},
+	}
+
+	for _, tt := range tests {
+		data, err := s.Query(context.Background(), tt.command)
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf(`Query(%s) => %v, want %v`, tt.command, err, tt.err)
+			}
+
+			continue
+		}
+
+		if err != nil || string(data) != tt.want {
+			t.Errorf(`Query(%s) => %q, %v, want %q`, tt.command, data, err, tt.want)
+		}
+	}
+
+	routes, err := s.Routes(context.Background(), netip.MustParsePrefix("193.0.0.0/22"), "l")
+	if err != nil {
+		t.Fatalf(`Routes => %v`, err)
+	}
+
+	if got := keys(routes); !reflect.DeepEqual(got, []string{"193.0.0.0/21AS3333"}) {
+		t.Errorf(`Routes => %v, want [193.0.0.0/21AS3333]`, got)
+	}
+
+	mntners, err := s.Object(context.Background(), "mntner", "RIPE-NCC-MNT")
+	if err != nil {
+		t.Fatalf(`Object => %v`, err)
+	}
+
+	if got := mntners[0].GetAll("auth"); !reflect.DeepEqual(got, []string{"MD5-PW"}) {
+		t.Errorf(`Object => auth %v, want [MD5-PW]`, got)
+	}
+
+	// A single command is answered without the persistent mode.
+	data, err := c.IRRQuery(context.Background(), "!gAS3333")
+	if err != nil || strings.TrimSpace(string(data)) != "193.0.0.0/21" {
+		t.Errorf(`IRRQuery => %q, %v, want 193.0.0.0/21`, data, err)
+	}
+
+	if _, err := c.IRRQuery(context.Background(), "!mmntner,UNKNOWN-MNT"); !errors.Is(err, whois.ErrKeyNotFound) {
+		t.Errorf(`IRRQuery => %v, want %v`, err, whois.ErrKeyNotFound)
+	}
+}
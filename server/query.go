@@ -0,0 +1,312 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+// contactAttributes are the attributes referencing the person and role objects returned with the objects found,
+// unless recursion is disabled.
+var contactAttributes = []string{"admin-c", "tech-c", "zone-c", "author", "ping-hdl"}
+
+// personalAttributes are the attributes removed from the objects, unless filtering is disabled.
+var personalAttributes = []string{"e-mail", "notify", "changed", "ref-nfy", "upd-to", "mnt-nfy", "irt-nfy"}
+
+// formatter writes the objects of the responses.
+var formatter = rpsl.Formatter{Comments: true}
+
+// stripKeepalive removes the -k flags from a query line, and returns true if there was an odd number of them.
+func stripKeepalive(line string) (string, bool) {
+	fields := strings.Fields(line)
+	toggle := false
+	kept := fields[:0]
+	for _, field := range fields {
+		if field == "-k" || field == "--persistent-connection" {
+			toggle = !toggle
+			continue
+		}
+
+		kept = append(kept, field)
+	}
+
+	return strings.Join(kept, " "), toggle
+}
+
+// writeError writes a "%ERROR" comment and the empty line ending the response.
+func writeError(buf *bytes.Buffer, code int, msg string, details ...string) {
+	fmt.Fprintf(buf, "%%ERROR:%d: %s\n", code, msg)
+	if len(details) > 0 {
+		buf.WriteString("%\n")
+		for _, detail := range details {
+			fmt.Fprintf(buf, "%% %s\n", detail)
+		}
+	}
+
+	buf.WriteByte('\n')
+}
+
+// answer writes the response to a query line, without the -k flags.
+func (s *Server) answer(buf *bytes.Buffer, line string) {
+	fmt.Fprintf(buf, "%% This is the %s whois server.\n%% The objects are in RPSL format.\n\n", s.opts.Version)
+
+	if rest, ok := strings.CutPrefix(line, "-q "); ok {
+		switch strings.TrimSpace(rest) {
+		case "version":
+			fmt.Fprintf(buf, "%% %s\n\n", s.opts.Version)
+		case "sources":
+			for _, source := range s.opts.Sources {
+				fmt.Fprintf(buf, "%s:3:N:0-0\n", source)
+			}
+
+			buf.WriteByte('\n')
+		default:
+			writeError(buf, 111, "invalid option supplied")
+		}
+
+		return
+	}
+
+	if strings.TrimSpace(line) == "" {
+		writeError(buf, 106, "no search key specified")
+		return
+	}
+
+	q, err := whois.ParseQuery(line)
+	if err != nil {
+		writeError(buf, 111, "invalid option supplied", strings.TrimPrefix(err.Error(), "whois: "))
+		return
+	}
+
+	for _, source := range q.Sources {
+		if len(s.opts.Sources) > 0 && !containsFold(s.opts.Sources, source) {
+			writeError(buf, 102, "unknown source", fmt.Sprintf("'%s'", source))
+			return
+		}
+	}
+
+	for _, class := range q.Types {
+		if rpsl.LookupTemplate(class) == nil {
+			writeError(buf, 103, "unknown object type", fmt.Sprintf("'%s'", class))
+			return
+		}
+	}
+
+	for _, attribute := range q.Inverse {
+		if !inverseKey(attribute) {
+			writeError(buf, 104, "unknown attribute", fmt.Sprintf("'%s'", attribute))
+			return
+		}
+	}
+
+	objs := s.search(q)
+	if len(objs) == 0 {
+		sources := q.Sources
+		if len(sources) == 0 {
+			sources = s.opts.Sources
+		}
+
+		detail := "No entries found."
+		if len(sources) > 0 {
+			detail = fmt.Sprintf("No entries found in source %s.", strings.Join(sources, ","))
+		}
+
+		writeError(buf, 101, "no entries found", detail)
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, o := range objs {
+		id := identity(&o)
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		if q.PrimaryKeys {
+			writeObject(buf, primaryKeys(&o))
+			continue
+		}
+
+		fmt.Fprintf(buf, "%% Information related to '%s'\n\n", o.PrimaryKey())
+		writeObject(buf, filter(&o, q.NoFiltering))
+		if q.NoRecursion {
+			continue
+		}
+
+		for _, contact := range s.contacts(&o, q) {
+			if id := identity(&contact); !seen[id] {
+				seen[id] = true
+				writeObject(buf, filter(&contact, q.NoFiltering))
+			}
+		}
+	}
+}
+
+// search returns the objects matching a query, before recursion.
+func (s *Server) search(q *whois.Query) []rpsl.Object {
+	var objs []rpsl.Object
+	if len(q.Inverse) > 0 {
+		for _, attribute := range q.Inverse {
+			objs = append(objs, s.db.Inverse(strings.ToLower(attribute), q.Text)...)
+		}
+	} else if start, end, ok := parseRange(q.Text); ok {
+		switch {
+		case q.Exact:
+			objs = s.db.Range(start, end, MatchExact)
+		case q.OneLessSpecific:
+			objs = s.db.Range(start, end, MatchOneLess)
+		case q.AllLessSpecific:
+			objs = s.db.Range(start, end, MatchAllLess)
+		case q.OneMoreSpecific:
+			objs = s.db.Range(start, end, MatchOneMore)
+		case q.AllMoreSpecific:
+			objs = s.db.Range(start, end, MatchAllMore)
+		default:
+			// The exact match, or the one level less specific object of the classes without an exact match.
+			objs = s.db.Range(start, end, MatchExact)
+			for _, o := range s.db.Range(start, end, MatchOneLess) {
+				if !slices.ContainsFunc(objs, func(e rpsl.Object) bool { return e.Class() == o.Class() }) {
+					objs = append(objs, o)
+				}
+			}
+		}
+	} else {
+		objs = s.db.Lookup(q.Text)
+	}
+
+	return filterObjects(objs, func(o *rpsl.Object) bool {
+		return (len(q.Types) == 0 || containsFold(q.Types, o.Class())) &&
+			(len(q.Sources) == 0 || containsFold(q.Sources, source(o)))
+	})
+}
+
+// contacts returns the person and role objects referenced by the contact attributes of an object.
+func (s *Server) contacts(o *rpsl.Object, q *whois.Query) []rpsl.Object {
+	var contacts []rpsl.Object
+	for _, attribute := range contactAttributes {
+		for _, value := range o.GetAll(attribute) {
+			for _, c := range s.db.Lookup(value) {
+				if c.Class() != "person" && c.Class() != "role" {
+					continue
+				}
+
+				if len(q.Sources) > 0 && !containsFold(q.Sources, source(&c)) {
+					continue
+				}
+
+				contacts = append(contacts, c)
+			}
+		}
+	}
+
+	return contacts
+}
+
+// parseRange parses the search key of a query as an address, a prefix or an address range.
+func parseRange(text string) (netip.Addr, netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(text); err == nil {
+		return addr, addr, true
+	}
+
+	start, end, err := rpsl.ParseRange(text)
+	return start, end, err == nil
+}
+
+// inverseKey returns true if the attribute is an inverse key of any known class.
+func inverseKey(attribute string) bool {
+	for _, class := range rpsl.DefaultRegistry.Classes() {
+		if slices.Contains(rpsl.LookupTemplate(class).Keys(rpsl.InverseKey), strings.ToLower(attribute)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// primaryKeys returns a copy of an object with only its primary key attributes.
+func primaryKeys(o *rpsl.Object) *rpsl.Object {
+	keys := []string{o.Class()}
+	if t := rpsl.LookupTemplate(o.Class()); t != nil {
+		keys = t.Keys(rpsl.PrimaryKey)
+	}
+
+	out := &rpsl.Object{}
+	for _, attr := range o.Attributes {
+		if slices.Contains(keys, attr.Name) {
+			out.Attributes = append(out.Attributes, rpsl.Attribute{Name: attr.Name, Value: attr.Value})
+		}
+	}
+
+	return out
+}
+
+// identity returns a key identifying an object in a response.
+func identity(o *rpsl.Object) string {
+	return strings.ToLower(o.Class() + ":" + o.PrimaryKey())
+}
+
+// source returns the value of the source attribute of an object.
+func source(o *rpsl.Object) string {
+	if value := o.GetFirst("source"); value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+// filter returns a copy of an object without its credentials and, unless all is true, without its personal data.
+func filter(o *rpsl.Object, all bool) *rpsl.Object {
+	filtered := !all
+	out := &rpsl.Object{Attributes: make([]rpsl.Attribute, 0, len(o.Attributes))}
+	for _, attr := range o.Attributes {
+		attr := rpsl.Attribute{Name: attr.Name, Value: attr.Value}
+		switch {
+		case attr.Name == "auth":
+			// Credentials are never returned, only their scheme.
+			if fields := strings.Fields(attr.Value); len(fields) > 0 && !strings.HasPrefix(fields[0], "PGPKEY-") && !strings.HasPrefix(fields[0], "X509-") {
+				attr.Value = fields[0]
+				attr.Raw = &rpsl.RawAttribute{Comments: []string{"Filtered"}}
+			}
+		case filtered && slices.Contains(personalAttributes, attr.Name):
+			continue
+		case filtered && attr.Name == "source":
+			attr.Raw = &rpsl.RawAttribute{Comments: []string{"Filtered"}}
+		}
+
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	return out
+}
+
+// writeObject writes an object followed by an empty line.
+func writeObject(buf *bytes.Buffer, o *rpsl.Object) {
+	_ = formatter.WriteObject(buf, o)
+	buf.WriteByte('\n')
+}
+
+// filterObjects returns the objects for which keep returns true, in a new slice.
+func filterObjects(objs []rpsl.Object, keep func(o *rpsl.Object) bool) []rpsl.Object {
+	var kept []rpsl.Object
+	for i := range objs {
+		if keep(&objs[i]) {
+			kept = append(kept, objs[i])
+		}
+	}
+
+	return kept
+}
+
+// containsFold returns true if values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
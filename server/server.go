@@ -0,0 +1,380 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package server implements an embeddable whois server (RFC 3912) answering the queries of the RIPE database query
+// service, and the "!" commands of IRRd used by tools such as bgpq4, over a Database of objects.
+//
+// Example:
+//
+//	db := server.NewMemoryDatabase(objs)
+//	srv := server.NewServerWithOptions(db, server.Options{Sources: []string{"EXAMPLE"}, RateLimit: 10})
+//	go func() {
+//	    if err := srv.ListenAndServe(":4343"); !errors.Is(err, server.ErrServerClosed) {
+//	        log.Fatalf("Failed to serve: %v", err)
+//	    }
+//	}()
+//
+//	...
+//	srv.Shutdown(ctx)
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go/policy"
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after a call to Shutdown.
+var ErrServerClosed = errors.New("server: server closed")
+
+// maxLineLength is the maximum length of a query line, excluding the line ending.
+const maxLineLength = 1024
+
+// Options configures a Server.
+type Options struct {
+	// MaxConns is the maximum number of concurrent connections. Connections above the limit are refused with an
+	// error. Zero means no limit.
+	MaxConns int
+	// RateLimit is the number of queries per second allowed to every client address. Clients exceeding it receive an
+	// error and are disconnected. Zero means no limit.
+	RateLimit float64
+	// RateBurst is the number of queries a client address can send at once before RateLimit applies. Defaults to 1,
+	// or to RateLimit rounded up if it is larger.
+	RateBurst int
+	// IdleTimeout is the time a connection may wait for a query before it is closed. Defaults to one minute.
+	IdleTimeout time.Duration
+	// Sources are the names of the sources of the Database, such as "RIPE". Queries restricted to other sources are
+	// rejected. When empty, every source is accepted.
+	Sources []string
+	// Version is the name of the server, returned by the "-q version" query and the "!v" command. Defaults to
+	// "rpsl-go".
+	Version string
+	// Resolver expands the sets of the IRRd "!i<set>,1" and "!a" commands, such as a policy.SetResolver over the
+	// objects of the Database. When nil, these commands are answered with an error.
+	Resolver policy.Resolver
+}
+
+// Server answers whois queries over a Database. It must be created with NewServer or NewServerWithOptions.
+type Server struct {
+	db   Database
+	opts Options
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*conn]struct{}
+	limiters  map[netip.Addr]*limiter
+	closed    bool
+}
+
+// NewServer returns a Server answering queries over db with the default options.
+func NewServer(db Database) *Server {
+	return NewServerWithOptions(db, Options{})
+}
+
+// NewServerWithOptions returns a Server answering queries over db with the given options.
+func NewServerWithOptions(db Database, opts Options) *Server {
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = time.Minute
+	}
+
+	if opts.RateBurst <= 0 {
+		opts.RateBurst = max(1, int(opts.RateLimit+0.999))
+	}
+
+	if opts.Version == "" {
+		opts.Version = "rpsl-go"
+	}
+
+	return &Server{
+		db:        db,
+		opts:      opts,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[*conn]struct{}),
+		limiters:  make(map[netip.Addr]*limiter),
+	}
+}
+
+// ListenAndServe listens on the TCP address addr, in the "host:port" form, and serves the connections. It always
+// returns a non-nil error, ErrServerClosed after a call to Shutdown.
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ln)
+}
+
+// Serve accepts the connections of ln and serves each of them in a new goroutine. The listener is closed when Serve
+// returns. It always returns a non-nil error, ErrServerClosed after a call to Shutdown.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+
+	s.listeners[ln] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, ln)
+		s.mu.Unlock()
+		ln.Close()
+	}()
+
+	for {
+		nc, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+
+			return err
+		}
+
+		c, err := s.track(nc)
+		if err != nil {
+			go refuse(nc, err)
+			continue
+		}
+
+		go s.serve(c)
+	}
+}
+
+// Shutdown stops the listeners, closes the idle connections and waits for the pending queries to be answered before
+// closing their connections. If ctx is done first, the remaining connections are closed and the error of ctx is
+// returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for ln := range s.listeners {
+		ln.Close()
+	}
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if s.closeIdle() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			for c := range s.conns {
+				c.nc.Close()
+			}
+			s.mu.Unlock()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// closeIdle closes the idle connections and returns true if no connection remains.
+func (s *Server) closeIdle() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		if !c.busy.Load() {
+			c.nc.Close()
+		}
+	}
+
+	return len(s.conns) == 0
+}
+
+// track registers a new connection, or returns an error if the server is closed or has too many connections.
+func (s *Server) track(nc net.Conn) (*conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.New("server is shutting down")
+	}
+
+	if s.opts.MaxConns > 0 && len(s.conns) >= s.opts.MaxConns {
+		return nil, errors.New("too many connections")
+	}
+
+	c := &conn{nc: nc}
+	c.busy.Store(true)
+	s.conns[c] = struct{}{}
+	return c, nil
+}
+
+// refuse answers a connection with a "connection refused" error and closes it. The query of the client is read
+// before closing, so that the error is not lost in a connection reset.
+func refuse(nc net.Conn, err error) {
+	defer nc.Close()
+	var buf bytes.Buffer
+	writeError(&buf, 206, "connection refused", err.Error())
+	_ = nc.SetDeadline(time.Now().Add(time.Second))
+	if _, err := nc.Write(buf.Bytes()); err != nil {
+		return
+	}
+
+	if tcp, ok := nc.(*net.TCPConn); ok {
+		_ = tcp.CloseWrite()
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(nc, maxLineLength))
+}
+
+// untrack unregisters a connection and closes it.
+func (s *Server) untrack(c *conn) {
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+	c.nc.Close()
+}
+
+// conn is a connection served by a Server.
+type conn struct {
+	nc net.Conn
+	// busy is true while a query is read or answered, so that Shutdown does not interrupt it.
+	busy atomic.Bool
+}
+
+// serve answers the queries of a connection, until the client closes it or leaves the persistent mode. Lines starting
+// with "!" are IRRd commands, the others are RIPE queries.
+func (s *Server) serve(c *conn) {
+	defer s.untrack(c)
+
+	r := bufio.NewReaderSize(c.nc, maxLineLength+2)
+	persistent := false
+	var irr irrState
+	for {
+		c.busy.Store(false)
+		if s.isClosed() {
+			return
+		}
+
+		_ = c.nc.SetReadDeadline(time.Now().Add(s.opts.IdleTimeout))
+		line, err := r.ReadSlice('\n')
+		c.busy.Store(true)
+
+		var buf bytes.Buffer
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			writeError(&buf, 107, "input line too long")
+			_, _ = c.nc.Write(buf.Bytes())
+			return
+		case err != nil && len(line) == 0:
+			return
+		}
+
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) > maxLineLength {
+			writeError(&buf, 107, "input line too long")
+			_, _ = c.nc.Write(buf.Bytes())
+			return
+		}
+
+		if !s.allow(c.nc.RemoteAddr()) {
+			writeError(&buf, 202, "access control limit reached")
+			_, _ = c.nc.Write(buf.Bytes())
+			return
+		}
+
+		keep := true
+		if command, ok := bytes.CutPrefix(line, []byte("!")); ok {
+			keep = s.answerIRR(&buf, string(command), &irr)
+		} else {
+			text, toggle := stripKeepalive(string(line))
+			if toggle {
+				persistent = !persistent
+			}
+
+			if text != "" || !toggle {
+				s.answer(&buf, text)
+				if persistent {
+					buf.WriteByte('\n')
+				}
+			}
+		}
+
+		_ = c.nc.SetWriteDeadline(time.Now().Add(s.opts.IdleTimeout))
+		if _, err := c.nc.Write(buf.Bytes()); err != nil || !keep || !persistent && !irr.persistent {
+			return
+		}
+
+		_ = c.nc.SetWriteDeadline(time.Time{})
+	}
+}
+
+// isClosed returns true after a call to Shutdown.
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// allow consumes a token from the limiter of the address, and returns false if none is left.
+func (s *Server) allow(addr net.Addr) bool {
+	if s.opts.RateLimit <= 0 {
+		return true
+	}
+
+	var ip netip.Addr
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		ip = tcp.AddrPort().Addr().Unmap()
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.limiters[ip]
+	if !ok {
+		// Forget the clients whose buckets are full again.
+		for addr, l := range s.limiters {
+			if l.refill(now, s.opts.RateLimit, s.opts.RateBurst) >= float64(s.opts.RateBurst) {
+				delete(s.limiters, addr)
+			}
+		}
+
+		l = &limiter{tokens: float64(s.opts.RateBurst), last: now}
+		s.limiters[ip] = l
+	}
+
+	if l.refill(now, s.opts.RateLimit, s.opts.RateBurst) < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
+
+// limiter is the token bucket of a client address.
+type limiter struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the last refill and returns the number of tokens available.
+func (l *limiter) refill(now time.Time, rate float64, burst int) float64 {
+	l.tokens = min(float64(burst), l.tokens+now.Sub(l.last).Seconds()*rate)
+	l.last = now
+	return l.tokens
+}
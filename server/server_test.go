@@ -0,0 +1,306 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/whois"
+)
+
+const testDatabase = `inetnum:        193.0.0.0 - 193.0.23.255
+netname:        RIPE-NCC
+admin-c:        BRD-RIPE
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+inetnum:        193.0.0.0 - 193.0.7.255
+netname:        RIPE-NCC
+admin-c:        BRD-RIPE
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+inetnum:        193.0.0.0 - 193.0.0.255
+netname:        RIPE-NCC-SMALL
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+route:          193.0.0.0/21
+origin:         AS3333
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+role:           RIPE NCC Operations
+nic-hdl:        BRD-RIPE
+e-mail:         ops@example.net
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+mntner:         RIPE-NCC-MNT
+auth:           MD5-PW $1$abcdefgh$0123456789
+upd-to:         ops@example.net
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+as-set:         AS-TEST
+members:        AS3333, AS-NESTED
+mbrs-by-ref:    RIPE-NCC-MNT
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+as-set:         AS-NESTED
+members:        AS64496
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+aut-num:        AS64497
+member-of:      AS-TEST
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+route:          192.0.2.0/24
+origin:         AS64497
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+
+route6:         2001:db8::/32
+origin:         AS64496
+mnt-by:         RIPE-NCC-MNT
+source:         TEST
+`
+
+// newTestServer starts a Server over testDatabase, shut down at the end of the test, and returns its address.
+func newTestServer(t *testing.T, opts Options) (*Server, string) {
+	objs, err := rpsl.ParseMany(testDatabase)
+	if err != nil {
+		t.Fatalf(`ParseMany => %v`, err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf(`net.Listen => %v`, err)
+	}
+
+	srv := NewServerWithOptions(NewMemoryDatabase(objs), opts)
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+	return srv, ln.Addr().String()
+}
+
+// keys returns the primary keys of the objects.
+func keys(objs []rpsl.Object) []string {
+	keys := make([]string, len(objs))
+	for i := range objs {
+		keys[i] = objs[i].PrimaryKey()
+	}
+
+	return keys
+}
+
+func TestRange(t *testing.T) {
+	objs, err := rpsl.ParseMany(testDatabase)
+	if err != nil {
+		t.Fatalf(`ParseMany => %v`, err)
+	}
+
+	db := NewMemoryDatabase(objs)
+	start, end := netip.MustParseAddr("193.0.0.0"), netip.MustParseAddr("193.0.7.255")
+	tests := []struct {
+		match Match
+		want  []string
+	}{
+		{MatchExact, []string{"193.0.0.0 - 193.0.7.255", "193.0.0.0/21AS3333"}},
+		{MatchOneLess, []string{"193.0.0.0 - 193.0.23.255"}},
+		{MatchAllLess, []string{"193.0.0.0 - 193.0.23.255", "193.0.0.0 - 193.0.7.255", "193.0.0.0/21AS3333"}},
+		{MatchOneMore, []string{"193.0.0.0 - 193.0.0.255"}},
+		{MatchAllMore, []string{"193.0.0.0 - 193.0.0.255"}},
+	}
+
+	for _, tt := range tests {
+		if got := keys(db.Range(start, end, tt.match)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(`Range(%d) => %v, want %v`, tt.match, got, tt.want)
+		}
+	}
+}
+
+func TestServerQuery(t *testing.T) {
+	_, addr := newTestServer(t, Options{Sources: []string{"TEST"}})
+	c := whois.NewClientWithOptions(addr, whois.ClientOptions{Timeout: 5 * time.Second})
+
+	tests := []struct {
+		query *whois.Query
+		want  []string
+	}{
+		{&whois.Query{Text: "193.0.0.0/21"}, []string{"193.0.0.0 - 193.0.7.255", "BRD-RIPE", "193.0.0.0/21AS3333"}},
+		{&whois.Query{Text: "193.0.1.1", NoRecursion: true}, []string{"193.0.0.0 - 193.0.7.255", "193.0.0.0/21AS3333"}},
+		{&whois.Query{Text: "193.0.0.0/21", OneLessSpecific: true, NoRecursion: true}, []string{"193.0.0.0 - 193.0.23.255"}},
+		{&whois.Query{Text: "193.0.0.0/21", Types: []string{"route"}}, []string{"193.0.0.0/21AS3333"}},
+		{&whois.Query{Text: "brd-ripe", NoRecursion: true}, []string{"BRD-RIPE"}},
+		{&whois.Query{Text: "AS3333", Inverse: []string{"origin"}}, []string{"193.0.0.0/21AS3333"}},
+		{&whois.Query{Text: "RIPE-NCC-MNT", Inverse: []string{"mnt-by"}, Types: []string{"role"}}, []string{"BRD-RIPE"}},
+	}
+
+	for _, tt := range tests {
+		resp, err := c.Query(context.Background(), tt.query)
+		if err != nil {
+			t.Errorf(`Query(%s) => %v`, tt.query, err)
+			continue
+		}
+
+		if got := keys(resp.Objects); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(`Query(%s) => %v, want %v`, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestServerFiltering(t *testing.T) {
+	_, addr := newTestServer(t, Options{})
+	c := whois.NewClient(addr)
+
+	resp, err := c.Query(context.Background(), &whois.Query{Text: "RIPE-NCC-MNT", NoRecursion: true})
+	if err != nil {
+		t.Fatalf(`Query => %v`, err)
+	}
+
+	o := resp.Objects[0]
+	if got := o.GetAll("auth"); !reflect.DeepEqual(got, []string{"MD5-PW"}) {
+		t.Errorf(`auth => %v, want [MD5-PW]`, got)
+	}
+
+	if o.Exists("upd-to") {
+		t.Errorf(`upd-to => %v, want filtered`, o.GetAll("upd-to"))
+	}
+
+	resp, err = c.Query(context.Background(), &whois.Query{Text: "BRD-RIPE", NoFiltering: true})
+	if err != nil {
+		t.Fatalf(`Query => %v`, err)
+	}
+
+	if got := resp.Objects[0].GetAll("e-mail"); !reflect.DeepEqual(got, []string{"ops@example.net"}) {
+		t.Errorf(`e-mail => %v, want [ops@example.net]`, got)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	_, addr := newTestServer(t, Options{Sources: []string{"TEST"}})
+	c := whois.NewClient(addr)
+
+	tests := []struct {
+		query *whois.Query
+		want  error
+	}{
+		{&whois.Query{Text: "AS64496"}, whois.ErrNoEntries},
+		{&whois.Query{Text: "AS3333", Types: []string{"unknown"}}, whois.ErrUnknownType},
+		{&whois.Query{Text: "AS3333", Inverse: []string{"netname"}}, whois.ErrUnknownAttribute},
+		{&whois.Query{Text: "AS3333", Sources: []string{"OTHER"}}, whois.ErrInvalidSource},
+	}
+
+	for _, tt := range tests {
+		if _, err := c.Query(context.Background(), tt.query); !errors.Is(err, tt.want) {
+			t.Errorf(`Query(%s) => %v, want %v`, tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	_, addr := newTestServer(t, Options{})
+	s, err := whois.NewClient(addr).Open(context.Background())
+	if err != nil {
+		t.Fatalf(`Open => %v`, err)
+	}
+
+	defer s.Close()
+	for _, text := range []string{"BRD-RIPE", "RIPE-NCC-MNT"} {
+		resp, err := s.Query(context.Background(), &whois.Query{Text: text, NoRecursion: true})
+		if err != nil {
+			t.Fatalf(`Session.Query(%s) => %v`, text, err)
+		}
+
+		if got := keys(resp.Objects); !reflect.DeepEqual(got, []string{text}) {
+			t.Errorf(`Session.Query(%s) => %v, want [%s]`, text, got, text)
+		}
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	_, addr := newTestServer(t, Options{RateLimit: 0.01, RateBurst: 1})
+	c := whois.NewClient(addr)
+
+	if _, err := c.Query(context.Background(), &whois.Query{Text: "BRD-RIPE"}); err != nil {
+		t.Fatalf(`Query => %v`, err)
+	}
+
+	if _, err := c.Query(context.Background(), &whois.Query{Text: "BRD-RIPE"}); !errors.Is(err, whois.ErrAccessLimit) {
+		t.Errorf(`Query => %v, want %v`, err, whois.ErrAccessLimit)
+	}
+
+	_, addr = newTestServer(t, Options{MaxConns: 1})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf(`net.Dial => %v`, err)
+	}
+
+	defer conn.Close()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := whois.NewClient(addr).Query(context.Background(), &whois.Query{Text: "BRD-RIPE"}); !errors.Is(err, whois.ErrConnectionRefused) {
+		t.Errorf(`Query => %v, want %v`, err, whois.ErrConnectionRefused)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	srv, addr := newTestServer(t, Options{})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf(`net.Dial => %v`, err)
+	}
+
+	defer conn.Close()
+	if _, err := conn.Write([]byte("-k BRD-RIPE\r\n")); err != nil {
+		t.Fatalf(`Write => %v`, err)
+	}
+
+	// Wait for the response, so that the connection is idle.
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf(`ReadString => %v`, err)
+		}
+
+		if strings.HasPrefix(line, "source:") {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Errorf(`Shutdown => %v`, err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, err := r.ReadString('\n'); err != nil {
+			break
+		}
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`net.Listen => %v`, err)
+	}
+
+	if err := srv.Serve(ln); !errors.Is(err, ErrServerClosed) {
+		t.Errorf(`Serve => %v, want %v`, err, ErrServerClosed)
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"cmp"
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// Match selects the objects returned by a range lookup, relative to the queried range.
+type Match uint8
+
+const (
+	// MatchExact selects the objects with the same range.
+	MatchExact Match = iota
+	// MatchOneLess selects the smallest objects strictly containing the range.
+	MatchOneLess
+	// MatchAllLess selects the objects containing the range, including the exact match.
+	MatchAllLess
+	// MatchOneMore selects the largest objects strictly contained in the range.
+	MatchOneMore
+	// MatchAllMore selects the objects strictly contained in the range.
+	MatchAllMore
+	// MatchOverlap selects the objects sharing at least one address with the range.
+	MatchOverlap
+)
+
+// rangeClasses are the classes indexed by address range in a Store, in the order of the results of Store.Range.
+var rangeClasses = []string{"inetnum", "inet6num", "route", "route6"}
+
+// IPIndex indexes objects by the address range of their class attribute, such as inetnum, inet6num, route and route6
+// objects, in a radix tree. Prefixes and arbitrary ranges such as "192.0.2.0 - 192.0.2.77" are both supported: a range
+// is stored at the smallest prefix covering it, so that every lookup walks a single path of the tree, and the subtree
+// below it for more specific matches.
+//
+// The one-level matches consider every object of the index, which usually holds a single class. Lookups may run
+// concurrently, but not with changes.
+type IPIndex struct {
+	roots [2]*ipNode // IPv4 and IPv6 trees.
+	spans map[objectKey]span
+}
+
+// ipNode is a node of the radix tree, holding the ranges whose smallest covering prefix is its prefix.
+type ipNode struct {
+	prefix   netip.Prefix
+	children [2]*ipNode
+	entries  []ipEntry
+}
+
+// ipEntry is an object indexed by range.
+type ipEntry struct {
+	span span
+	key  objectKey
+	obj  rpsl.Object
+}
+
+// span is an address range.
+type span struct {
+	start, end netip.Addr
+}
+
+// NewIPIndex returns an empty IPIndex.
+func NewIPIndex() *IPIndex {
+	return &IPIndex{spans: make(map[objectKey]span)}
+}
+
+// Len returns the number of objects.
+func (x *IPIndex) Len() int {
+	return len(x.spans)
+}
+
+// Add adds an object, replacing the object of the same class with the same primary key. The value of its class
+// attribute must be a prefix or a range.
+func (x *IPIndex) Add(obj rpsl.Object) error {
+	k, ok := keyOf(&obj)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNoPrimaryKey, obj.String())
+	}
+
+	s, err := objectSpan(&obj)
+	if err != nil {
+		return err
+	}
+
+	x.remove(k)
+	x.add(k, s, obj)
+	return nil
+}
+
+// Delete deletes the object of the class with the primary key, ignoring case, and returns false if there is none.
+func (x *IPIndex) Delete(class string, key string) bool {
+	return x.remove(objectKey{strings.ToLower(class), normalize(key)})
+}
+
+// add inserts an object with its range.
+func (x *IPIndex) add(k objectKey, s span, obj rpsl.Object) {
+	n := insert(&x.roots[family(s.start)], cover(s))
+	n.entries = append(n.entries, ipEntry{span: s, key: k, obj: obj})
+	x.spans[k] = s
+}
+
+// remove deletes an object, and returns false if there is none.
+func (x *IPIndex) remove(k objectKey) bool {
+	s, ok := x.spans[k]
+	if !ok {
+		return false
+	}
+
+	delete(x.spans, k)
+	root := &x.roots[family(s.start)]
+	*root = removeEntry(*root, cover(s), k)
+	return true
+}
+
+// Lookup returns the objects related to the range from start to end, ordered from the least to the most specific.
+// A single address is the range from the address to itself.
+func (x *IPIndex) Lookup(start netip.Addr, end netip.Addr, match Match) []rpsl.Object {
+	q := span{start.Unmap(), end.Unmap()}
+	if !q.start.IsValid() || q.start.Is4() != q.end.Is4() || q.end.Less(q.start) {
+		return nil
+	}
+
+	// The ranges containing the query are stored on the path to its covering prefix, and the ranges contained in the
+	// query at or below it.
+	p := cover(q)
+	var found []ipEntry
+	n := x.roots[family(q.start)]
+	for n != nil && n.prefix.Bits() < p.Bits() && n.prefix.Contains(p.Addr()) {
+		found = n.collect(found, q, match)
+		n = n.children[bit(p.Addr(), n.prefix.Bits())]
+	}
+
+	if n != nil && p.Bits() <= n.prefix.Bits() && p.Contains(n.prefix.Addr()) {
+		switch match {
+		case MatchExact, MatchOneLess, MatchAllLess:
+			if n.prefix == p {
+				found = n.collect(found, q, match)
+			}
+		default:
+			found = n.walk(found, q, match)
+		}
+	}
+
+	switch match {
+	case MatchOneLess:
+		found = smallest(found)
+	case MatchOneMore:
+		found = largest(found)
+	}
+
+	slices.SortFunc(found, compareEntries)
+	objs := make([]rpsl.Object, len(found))
+	for i := range found {
+		objs[i] = found[i].obj
+	}
+
+	return objs
+}
+
+// collect appends the entries of the node selected by the match.
+func (n *ipNode) collect(found []ipEntry, q span, match Match) []ipEntry {
+	for _, e := range n.entries {
+		if selects(e.span, q, match) {
+			found = append(found, e)
+		}
+	}
+
+	return found
+}
+
+// walk appends the entries of the subtree selected by the match, skipping the nodes whose prefix does not overlap the
+// query.
+func (n *ipNode) walk(found []ipEntry, q span, match Match) []ipEntry {
+	if n == nil || !(span{n.prefix.Addr(), rpsl.LastAddr(n.prefix)}).overlaps(q) {
+		return found
+	}
+
+	found = n.collect(found, q, match)
+	found = n.children[0].walk(found, q, match)
+	return n.children[1].walk(found, q, match)
+}
+
+// selects returns true if the match selects the range s for the query q.
+func selects(s span, q span, match Match) bool {
+	switch match {
+	case MatchExact:
+		return s == q
+	case MatchOneLess:
+		return s.contains(q) && s != q
+	case MatchAllLess:
+		return s.contains(q)
+	case MatchOneMore, MatchAllMore:
+		return q.contains(s) && s != q
+	case MatchOverlap:
+		return s.overlaps(q)
+	default:
+		return false
+	}
+}
+
+// insert returns the node of the prefix, creating it if needed.
+func insert(root **ipNode, p netip.Prefix) *ipNode {
+	cur := root
+	for {
+		n := *cur
+		switch {
+		case n == nil:
+			*cur = &ipNode{prefix: p}
+			return *cur
+		case n.prefix == p:
+			return n
+		case n.prefix.Bits() < p.Bits() && n.prefix.Contains(p.Addr()):
+			cur = &n.children[bit(p.Addr(), n.prefix.Bits())]
+			continue
+		}
+
+		leaf := &ipNode{prefix: p}
+		c := commonPrefix(p, n.prefix)
+		if c == p {
+			// The new prefix contains the node.
+			leaf.children[bit(n.prefix.Addr(), p.Bits())] = n
+			*cur = leaf
+			return leaf
+		}
+
+		glue := &ipNode{prefix: c}
+		glue.children[bit(n.prefix.Addr(), c.Bits())] = n
+		glue.children[bit(p.Addr(), c.Bits())] = leaf
+		*cur = glue
+		return leaf
+	}
+}
+
+// removeEntry removes the entry of an object from the node of the prefix, and returns the subtree without the nodes
+// left empty.
+func removeEntry(n *ipNode, p netip.Prefix, k objectKey) *ipNode {
+	switch {
+	case n == nil:
+		return nil
+	case n.prefix == p:
+		n.entries = slices.DeleteFunc(n.entries, func(e ipEntry) bool { return e.key == k })
+	case n.prefix.Bits() < p.Bits() && n.prefix.Contains(p.Addr()):
+		i := bit(p.Addr(), n.prefix.Bits())
+		n.children[i] = removeEntry(n.children[i], p, k)
+	default:
+		return n
+	}
+
+	if len(n.entries) == 0 {
+		if n.children[0] == nil {
+			return n.children[1]
+		}
+
+		if n.children[1] == nil {
+			return n.children[0]
+		}
+	}
+
+	return n
+}
+
+// smallest returns the entries strictly containing no other range of the entries.
+func smallest(entries []ipEntry) []ipEntry {
+	// Sorted by decreasing start and increasing end, a range contains one of the previous ranges if it ends after the
+	// smallest of their ends.
+	slices.SortFunc(entries, func(a, b ipEntry) int {
+		return cmp.Or(b.span.start.Compare(a.span.start), a.span.end.Compare(b.span.end))
+	})
+
+	var out []ipEntry
+	var minEnd netip.Addr
+	var excluded bool
+	for i, e := range entries {
+		if i > 0 && e.span != entries[i-1].span {
+			if prev := entries[i-1].span; !minEnd.IsValid() || prev.end.Less(minEnd) {
+				minEnd = prev.end
+			}
+
+			excluded = minEnd.Compare(e.span.end) <= 0
+		}
+
+		if !excluded {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// largest returns the entries strictly contained in no other range of the entries.
+func largest(entries []ipEntry) []ipEntry {
+	// Sorted by increasing start and decreasing end, a range is contained in one of the previous ranges if it ends
+	// before the largest of their ends.
+	slices.SortFunc(entries, compareEntries)
+
+	var out []ipEntry
+	var maxEnd netip.Addr
+	var excluded bool
+	for i, e := range entries {
+		if i > 0 && e.span != entries[i-1].span {
+			if prev := entries[i-1].span; !maxEnd.IsValid() || maxEnd.Less(prev.end) {
+				maxEnd = prev.end
+			}
+
+			excluded = e.span.end.Compare(maxEnd) <= 0
+		}
+
+		if !excluded {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// compareEntries orders entries from the least to the most specific, then by class and primary key.
+func compareEntries(a, b ipEntry) int {
+	return cmp.Or(
+		a.span.start.Compare(b.span.start),
+		b.span.end.Compare(a.span.end),
+		cmp.Compare(a.key.class, b.key.class),
+		cmp.Compare(a.key.key, b.key.key),
+	)
+}
+
+// objectSpan returns the address range of the class attribute of an object.
+func objectSpan(o *rpsl.Object) (span, error) {
+	value := o.GetFirst(o.Class())
+	if value == nil {
+		return span{}, fmt.Errorf("store: %s object without range", o.Class())
+	}
+
+	start, end, err := rpsl.ParseRange(*value)
+	if err != nil {
+		return span{}, fmt.Errorf("store: %w", err)
+	}
+
+	return span{start.Unmap(), end.Unmap()}, nil
+}
+
+// contains returns true if s contains o.
+func (s span) contains(o span) bool {
+	return s.start.Compare(o.start) <= 0 && o.end.Compare(s.end) <= 0
+}
+
+// overlaps returns true if s and o share at least one address.
+func (s span) overlaps(o span) bool {
+	return s.start.Compare(o.end) <= 0 && o.start.Compare(s.end) <= 0
+}
+
+// family returns the index of the tree of an address: 0 for IPv4 and 1 for IPv6.
+func family(a netip.Addr) int {
+	if a.Is4() {
+		return 0
+	}
+
+	return 1
+}
+
+// cover returns the smallest prefix containing a range.
+func cover(s span) netip.Prefix {
+	p, _ := s.start.Prefix(commonBits(s.start, s.end))
+	return p
+}
+
+// commonPrefix returns the longest prefix containing both prefixes.
+func commonPrefix(a netip.Prefix, b netip.Prefix) netip.Prefix {
+	p, _ := a.Addr().Prefix(min(commonBits(a.Addr(), b.Addr()), a.Bits(), b.Bits()))
+	return p
+}
+
+// commonBits returns the number of leading bits shared by two addresses of the same family.
+func commonBits(a netip.Addr, b netip.Addr) int {
+	x, y := addrBytes(a), addrBytes(b)
+	for i := range x {
+		if d := x[i] ^ y[i]; d != 0 {
+			return i*8 + bits.LeadingZeros8(d)
+		}
+	}
+
+	return len(x) * 8
+}
+
+// bit returns the bit of an address at the zero-based position i.
+func bit(a netip.Addr, i int) int {
+	b := addrBytes(a)
+	return int(b[i/8]>>(7-i%8)) & 1
+}
+
+// addrBytes returns the 4 or 16 bytes of an address.
+func addrBytes(a netip.Addr) []byte {
+	if a.Is4() {
+		b := a.As4()
+		return b[:]
+	}
+
+	b := a.As16()
+	return b[:]
+}
@@ -0,0 +1,201 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// testIndex returns an IPIndex holding inetnum and inet6num objects with the ranges.
+func testIndex(t *testing.T, ranges ...string) *IPIndex {
+	x := NewIPIndex()
+	for _, r := range ranges {
+		class := "inetnum"
+		if strings.Contains(r, ":") {
+			class = "inet6num"
+		}
+
+		if err := x.Add(rpsl.Object{Attributes: []rpsl.Attribute{{Name: class, Value: r}}}); err != nil {
+			t.Fatalf(`Add(%s) => %v`, r, err)
+		}
+	}
+
+	return x
+}
+
+func TestIPIndex(t *testing.T) {
+	x := testIndex(t,
+		"10.0.0.0 - 10.255.255.255",
+		"192.0.2.0 - 192.0.2.255",
+		"192.0.2.0 - 192.0.2.77",
+		"192.0.2.64 - 192.0.2.127",
+		"192.0.2.128/25",
+		"192.0.2.128 - 192.0.2.191",
+		"192.0.2.192/26",
+		"2001:db8::/32",
+		"2001:db8:1::/48",
+	)
+
+	tests := []struct {
+		query string
+		match Match
+		want  []string
+	}{
+		{"192.0.2.0/24", MatchExact, []string{"192.0.2.0 - 192.0.2.255"}},
+		{"192.0.2.0/25", MatchExact, []string{}},
+		{"192.0.2.128 - 192.0.2.255", MatchExact, []string{"192.0.2.128/25"}},
+		{"192.0.2.70", MatchOneLess, []string{"192.0.2.0 - 192.0.2.77", "192.0.2.64 - 192.0.2.127"}},
+		{"192.0.2.70", MatchAllLess, []string{"192.0.2.0 - 192.0.2.255", "192.0.2.0 - 192.0.2.77", "192.0.2.64 - 192.0.2.127"}},
+		{"192.0.2.128/25", MatchOneLess, []string{"192.0.2.0 - 192.0.2.255"}},
+		{"192.0.2.128/25", MatchAllLess, []string{"192.0.2.0 - 192.0.2.255", "192.0.2.128/25"}},
+		{"192.0.2.200", MatchOneLess, []string{"192.0.2.192/26"}},
+		{"198.51.100.1", MatchAllLess, []string{}},
+		{"192.0.2.0/24", MatchOneMore, []string{"192.0.2.0 - 192.0.2.77", "192.0.2.64 - 192.0.2.127", "192.0.2.128/25"}},
+		{"192.0.2.0/24", MatchAllMore, []string{"192.0.2.0 - 192.0.2.77", "192.0.2.64 - 192.0.2.127", "192.0.2.128/25", "192.0.2.128 - 192.0.2.191", "192.0.2.192/26"}},
+		{"192.0.0.0/16", MatchOneMore, []string{"192.0.2.0 - 192.0.2.255"}},
+		{"192.0.2.100 - 192.0.2.130", MatchOverlap, []string{"192.0.2.0 - 192.0.2.255", "192.0.2.64 - 192.0.2.127", "192.0.2.128/25", "192.0.2.128 - 192.0.2.191"}},
+		{"0.0.0.0/0", MatchOneMore, []string{"10.0.0.0 - 10.255.255.255", "192.0.2.0 - 192.0.2.255"}},
+		{"2001:db8:1::1", MatchAllLess, []string{"2001:db8::/32", "2001:db8:1::/48"}},
+		{"2001:db8::/32", MatchAllMore, []string{"2001:db8:1::/48"}},
+		{"::ffff:192.0.2.1", MatchOneLess, []string{"192.0.2.0 - 192.0.2.77"}},
+	}
+
+	for _, tt := range tests {
+		start, end, err := rpsl.ParseRange(tt.query)
+		if err != nil {
+			start, _ = netip.ParseAddr(tt.query)
+			end = start
+		}
+
+		objs := x.Lookup(start, end, tt.match)
+		got := make([]string, len(objs))
+		for i := range objs {
+			got[i] = *objs[i].GetFirst(objs[i].Class())
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(`Lookup(%s, %d) => %v, want %v`, tt.query, tt.match, got, tt.want)
+		}
+	}
+
+	if !x.Delete("inetnum", "192.0.2.0 - 192.0.2.255") || x.Len() != 8 {
+		t.Fatalf(`Delete => the object was not deleted`)
+	}
+
+	want := []string{"192.0.2.0 - 192.0.2.77", "192.0.2.64 - 192.0.2.127", "192.0.2.128/25"}
+	start, end, _ := rpsl.ParseRange("192.0.0.0/16")
+	var got []string
+	for _, o := range x.Lookup(start, end, MatchOneMore) {
+		got = append(got, *o.GetFirst("inetnum"))
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(`Lookup(192.0.0.0/16, MatchOneMore) => %v, want %v`, got, want)
+	}
+
+	if err := x.Add(rpsl.Object{Attributes: []rpsl.Attribute{{Name: "inetnum", Value: "192.0.2.9 - 192.0.2.1"}}}); err == nil {
+		t.Errorf(`Add(192.0.2.9 - 192.0.2.1) => nil, want an error`)
+	}
+}
+
+// scan returns the ranges selected by the match among the ranges, as found by a linear scan.
+func scan(ranges []span, q span, match Match) []span {
+	var candidates []span
+	for _, s := range ranges {
+		if s.start.Is4() == q.start.Is4() && selects(s, q, match) {
+			candidates = append(candidates, s)
+		}
+	}
+
+	var out []span
+	for _, s := range candidates {
+		keep := true
+		for _, c := range candidates {
+			if match == MatchOneLess && c != s && s.contains(c) || match == MatchOneMore && c != s && c.contains(s) {
+				keep = false
+			}
+		}
+
+		if keep {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
+
+func TestIPIndexRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	addr := func() netip.Addr {
+		return netip.AddrFrom4([4]byte{10, byte(r.IntN(4)), byte(r.IntN(256)), byte(r.IntN(256))})
+	}
+
+	randomSpan := func() span {
+		a, b := addr(), addr()
+		if r.IntN(2) == 0 {
+			p, _ := a.Prefix(8 + r.IntN(25))
+			return span{p.Addr(), rpsl.LastAddr(p)}
+		}
+
+		if b.Less(a) {
+			a, b = b, a
+		}
+
+		return span{a, b}
+	}
+
+	x := NewIPIndex()
+	var ranges []span
+	for i := range 1000 {
+		s := randomSpan()
+		value := fmt.Sprintf("%s - %s", s.start, s.end)
+		if err := x.Add(rpsl.Object{Attributes: []rpsl.Attribute{{Name: "inetnum", Value: value}}}); err != nil {
+			t.Fatalf(`Add(%s) => %v`, value, err)
+		}
+
+		if !slices.Contains(ranges, s) {
+			ranges = append(ranges, s)
+		}
+
+		// Delete some of the ranges, to check that the tree is kept consistent.
+		if i%5 == 0 {
+			d := ranges[r.IntN(len(ranges))]
+			x.Delete("inetnum", fmt.Sprintf("%s - %s", d.start, d.end))
+			ranges = slices.DeleteFunc(ranges, func(s span) bool { return s == d })
+		}
+	}
+
+	if x.Len() != len(ranges) {
+		t.Fatalf(`Len => %d, want %d`, x.Len(), len(ranges))
+	}
+
+	for range 200 {
+		q := randomSpan()
+		if r.IntN(4) == 0 {
+			q = ranges[r.IntN(len(ranges))]
+		}
+
+		for match := MatchExact; match <= MatchOverlap; match++ {
+			var got []span
+			for _, o := range x.Lookup(q.start, q.end, match) {
+				s, _ := objectSpan(&o)
+				got = append(got, s)
+			}
+
+			want := scan(ranges, q, match)
+			slices.SortFunc(want, func(a, b span) int { return compareEntries(ipEntry{span: a}, ipEntry{span: b}) })
+			if len(got) != len(want) || len(got) > 0 && !reflect.DeepEqual(got, want) {
+				t.Fatalf(`Lookup(%s - %s, %d) => %v, want %v`, q.start, q.end, match, got, want)
+			}
+		}
+	}
+}
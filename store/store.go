@@ -0,0 +1,374 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package store implements an in-memory database of RPSL objects, indexed by class and primary key and by the
+// attributes referencing other objects. A Store can be loaded from dumps and kept current by the nrtm and nrtm4
+// mirrors.
+//
+// Example:
+//
+//	s := store.New()
+//	if _, err := s.Load(dump); err != nil {
+//	    log.Fatalf("Failed to load the dump: %v", err)
+//	}
+//
+//	routes := s.Inverse("origin", "AS64496")
+package store
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/nrtm"
+)
+
+// DefaultInverse are the attributes indexed for inverse lookups when Options.Inverse is nil.
+var DefaultInverse = []string{"mnt-by", "admin-c", "tech-c", "origin", "member-of", "org"}
+
+// ErrNoPrimaryKey is returned when an object without class or primary key is added to a Store.
+var ErrNoPrimaryKey = errors.New("store: object without primary key")
+
+// Options configures a Store.
+type Options struct {
+	// Inverse are the attributes indexed for inverse lookups. When nil, DefaultInverse is used. Inverse lookups on
+	// other attributes scan every object.
+	Inverse []string
+}
+
+// Store is an in-memory database of RPSL objects, holding one object per class and primary key. It is safe for
+// concurrent use: lookups run in parallel, and every change is atomic.
+//
+// The objects added to a Store, and those returned by its lookups, must not be modified.
+type Store struct {
+	inverse map[string]bool
+
+	mu      sync.RWMutex
+	objs    map[string]map[string]rpsl.Object        // Objects by class and normalized primary key.
+	indexes map[string]map[string]map[objectKey]bool // Objects by inverse attribute and normalized referenced key.
+	ranges  map[string]*IPIndex                      // Objects by class and address range.
+	count   int
+}
+
+// objectKey identifies an object in a Store.
+type objectKey struct {
+	class, key string
+}
+
+// New returns an empty Store indexing the DefaultInverse attributes.
+func New() *Store {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions returns an empty Store with the given options.
+func NewWithOptions(opts Options) *Store {
+	if opts.Inverse == nil {
+		opts.Inverse = DefaultInverse
+	}
+
+	s := &Store{inverse: make(map[string]bool, len(opts.Inverse))}
+	for _, name := range opts.Inverse {
+		s.inverse[strings.ToLower(name)] = true
+	}
+
+	s.clear()
+	return s
+}
+
+// clear removes every object and index entry.
+func (s *Store) clear() {
+	s.objs = make(map[string]map[string]rpsl.Object)
+	s.indexes = make(map[string]map[string]map[objectKey]bool, len(s.inverse))
+	s.ranges = make(map[string]*IPIndex, len(rangeClasses))
+	for _, class := range rangeClasses {
+		s.ranges[class] = NewIPIndex()
+	}
+
+	s.count = 0
+}
+
+// Load adds the objects read from r, such as a database dump, and returns the number of objects added. Loading stops
+// at the first invalid object.
+func (s *Store) Load(r io.Reader) (int, error) {
+	n := 0
+	for obj, err := range rpsl.Objects(r) {
+		if err != nil {
+			return n, err
+		}
+
+		if err := s.Put(obj); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// Put adds an object, replacing the object of the same class with the same primary key. The inetnum, inet6num, route
+// and route6 objects must have a valid address range.
+func (s *Store) Put(obj rpsl.Object) error {
+	k, ok := keyOf(&obj)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNoPrimaryKey, obj.String())
+	}
+
+	var r span
+	if slices.Contains(rangeClasses, k.class) {
+		var err error
+		if r, err = objectSpan(&obj); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(k)
+	if index := s.ranges[k.class]; index != nil {
+		index.add(k, r, obj)
+	}
+
+	if s.objs[k.class] == nil {
+		s.objs[k.class] = make(map[string]rpsl.Object)
+	}
+
+	s.objs[k.class][k.key] = obj
+	s.count++
+	for _, attr := range obj.Attributes {
+		if !s.inverse[attr.Name] {
+			continue
+		}
+
+		for _, ref := range references(attr.Value) {
+			index := s.indexes[attr.Name]
+			if index == nil {
+				index = make(map[string]map[objectKey]bool)
+				s.indexes[attr.Name] = index
+			}
+
+			if index[ref] == nil {
+				index[ref] = make(map[objectKey]bool)
+			}
+
+			index[ref][k] = true
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the object of the class with the primary key, ignoring case, and returns false if there is none.
+func (s *Store) Delete(class string, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.remove(objectKey{strings.ToLower(class), normalize(key)})
+}
+
+// remove deletes an object and its index entries. The lock must be held for writing.
+func (s *Store) remove(k objectKey) bool {
+	obj, ok := s.objs[k.class][k.key]
+	if !ok {
+		return false
+	}
+
+	delete(s.objs[k.class], k.key)
+	if len(s.objs[k.class]) == 0 {
+		delete(s.objs, k.class)
+	}
+
+	s.count--
+	if index := s.ranges[k.class]; index != nil {
+		index.remove(k)
+	}
+
+	for _, attr := range obj.Attributes {
+		if !s.inverse[attr.Name] {
+			continue
+		}
+
+		for _, ref := range references(attr.Value) {
+			delete(s.indexes[attr.Name][ref], k)
+			if len(s.indexes[attr.Name][ref]) == 0 {
+				delete(s.indexes[attr.Name], ref)
+			}
+		}
+	}
+
+	return true
+}
+
+// Apply adds, replaces or deletes the object of a mirror operation, so that a Store can be kept current by an
+// nrtm.Client or an nrtm4.Client. Deleting an object that is not in the Store is not an error.
+func (s *Store) Apply(op *nrtm.Operation) error {
+	switch op.Kind {
+	case nrtm.Add:
+		return s.Put(op.Object)
+	case nrtm.Delete:
+		k, ok := keyOf(&op.Object)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrNoPrimaryKey, op.Object.String())
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.remove(k)
+		return nil
+	default:
+		return fmt.Errorf("store: unsupported operation %s", op.Kind)
+	}
+}
+
+// Reset deletes every object, before a mirror loads a new snapshot.
+func (s *Store) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+	return nil
+}
+
+// Len returns the number of objects.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.count
+}
+
+// Get returns the object of the class with the primary key, ignoring case. Composite keys are the concatenation of
+// their values, such as "192.0.2.0/24AS64496" for a route.
+func (s *Store) Get(class string, key string) (rpsl.Object, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objs[strings.ToLower(class)][normalize(key)]
+	return obj, ok
+}
+
+// Lookup returns the objects of every class with the primary key, ignoring case, ordered by class.
+func (s *Store) Lookup(key string) []rpsl.Object {
+	key = normalize(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []objectKey
+	for class, objs := range s.objs {
+		if _, ok := objs[key]; ok {
+			keys = append(keys, objectKey{class, key})
+		}
+	}
+
+	return s.collect(keys)
+}
+
+// Objects returns the objects of the class ordered by primary key, or every object ordered by class and primary key
+// if the class is empty.
+func (s *Store) Objects(class string) []rpsl.Object {
+	class = strings.ToLower(class)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []objectKey
+	for c, objs := range s.objs {
+		if class != "" && c != class {
+			continue
+		}
+
+		for key := range objs {
+			keys = append(keys, objectKey{c, key})
+		}
+	}
+
+	return s.collect(keys)
+}
+
+// Range returns the inetnum, inet6num, route and route6 objects related to the range from start to end, in this order
+// of classes, and from the least to the most specific. Every class is matched independently.
+func (s *Store) Range(start netip.Addr, end netip.Addr, match Match) []rpsl.Object {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var objs []rpsl.Object
+	for _, class := range rangeClasses {
+		objs = append(objs, s.ranges[class].Lookup(start, end, match)...)
+	}
+
+	return objs
+}
+
+// Inverse returns the objects having an attribute with the given name referencing key, ignoring case, ordered by
+// class and primary key. The values of the attribute may be comma-separated lists.
+func (s *Store) Inverse(attribute string, key string) []rpsl.Object {
+	attribute = strings.ToLower(attribute)
+	key = normalize(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []objectKey
+	if s.inverse[attribute] {
+		for k := range s.indexes[attribute][key] {
+			keys = append(keys, k)
+		}
+
+		return s.collect(keys)
+	}
+
+	for class, objs := range s.objs {
+		for pk, obj := range objs {
+			for _, value := range obj.GetAll(attribute) {
+				if slices.Contains(references(value), key) {
+					keys = append(keys, objectKey{class, pk})
+					break
+				}
+			}
+		}
+	}
+
+	return s.collect(keys)
+}
+
+// collect returns the objects with the keys, ordered by class and primary key. The lock must be held.
+func (s *Store) collect(keys []objectKey) []rpsl.Object {
+	slices.SortFunc(keys, func(a, b objectKey) int {
+		return cmp.Or(cmp.Compare(a.class, b.class), cmp.Compare(a.key, b.key))
+	})
+
+	objs := make([]rpsl.Object, len(keys))
+	for i, k := range keys {
+		objs[i] = s.objs[k.class][k.key]
+	}
+
+	return objs
+}
+
+// keyOf returns the key of an object in a Store.
+func keyOf(o *rpsl.Object) (objectKey, bool) {
+	k := objectKey{o.Class(), normalize(o.PrimaryKey())}
+	return k, k.key != ""
+}
+
+// normalize returns the form of a key in the indexes: upper case, without spaces.
+func normalize(key string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(key), ""))
+}
+
+// references returns the normalized keys referenced by the value of an attribute, possibly a comma-separated list. Only
+// the first word of every item is a key, as in "mnt-routes: EXAMPLE-MNT {192.0.2.0/24^+}".
+func references(value string) []string {
+	var refs []string
+	for _, item := range strings.Split(value, ",") {
+		if fields := strings.Fields(item); len(fields) > 0 {
+			refs = append(refs, strings.ToUpper(fields[0]))
+		}
+	}
+
+	return refs
+}
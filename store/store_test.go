@@ -0,0 +1,201 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/nrtm"
+)
+
+const testDump = `mntner:         EXAMPLE-MNT
+admin-c:        JD1-TEST
+mnt-by:         EXAMPLE-MNT
+source:         TEST
+
+person:         John Doe
+nic-hdl:        JD1-TEST
+mnt-by:         EXAMPLE-MNT
+source:         TEST
+
+route:          192.0.2.0/24
+origin:         AS64496
+member-of:      RS-EXAMPLE, RS-OTHER
+mnt-by:         EXAMPLE-MNT
+source:         TEST
+
+route:          192.0.2.0/24
+origin:         AS64497
+mnt-by:         OTHER-MNT
+mnt-routes:     EXAMPLE-MNT {192.0.2.0/24^+}
+source:         TEST
+
+aut-num:        AS64496
+as-name:        EXAMPLE
+tech-c:         JD1-TEST
+mnt-by:         EXAMPLE-MNT
+source:         TEST
+`
+
+// keys returns the classes and primary keys of the objects.
+func keys(objs []rpsl.Object) []string {
+	keys := make([]string, len(objs))
+	for i := range objs {
+		keys[i] = objs[i].Class() + ":" + objs[i].PrimaryKey()
+	}
+
+	return keys
+}
+
+// mustLoad returns a Store holding the objects of the dump.
+func mustLoad(t *testing.T, dump string) *Store {
+	s := New()
+	if _, err := s.Load(strings.NewReader(dump)); err != nil {
+		t.Fatalf(`Load => %v`, err)
+	}
+
+	return s
+}
+
+func TestStoreLookup(t *testing.T) {
+	s := New()
+	n, err := s.Load(strings.NewReader(testDump))
+	if err != nil || n != 5 || s.Len() != 5 {
+		t.Fatalf(`Load => %d, %v, Len %d, want 5`, n, err, s.Len())
+	}
+
+	tests := []struct {
+		class, key string
+		want       bool
+	}{
+		{"route", "192.0.2.0/24AS64496", true},
+		{"ROUTE", "192.0.2.0/24as64497", true},
+		{"route", "192.0.2.0/24AS64498", false},
+		{"person", "jd1-test", true},
+		{"person", "John Doe", false},
+		{"mntner", "EXAMPLE-MNT", true},
+	}
+
+	for _, tt := range tests {
+		if _, ok := s.Get(tt.class, tt.key); ok != tt.want {
+			t.Errorf(`Get(%s, %s) => %v, want %v`, tt.class, tt.key, ok, tt.want)
+		}
+	}
+
+	if got := keys(s.Lookup("as64496")); !reflect.DeepEqual(got, []string{"aut-num:AS64496"}) {
+		t.Errorf(`Lookup(as64496) => %v`, got)
+	}
+
+	want := []string{"route:192.0.2.0/24AS64496", "route:192.0.2.0/24AS64497"}
+	if got := keys(s.Objects("route")); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Objects(route) => %v, want %v`, got, want)
+	}
+
+	if got := s.Objects(""); len(got) != 5 {
+		t.Errorf(`Objects("") => %d objects, want 5`, len(got))
+	}
+
+	start, end, _ := rpsl.ParseRange("192.0.2.0/25")
+	if got := keys(s.Range(start, end, MatchOneLess)); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Range(192.0.2.0/25, MatchOneLess) => %v, want %v`, got, want)
+	}
+}
+
+func TestStoreInverse(t *testing.T) {
+	s := mustLoad(t, testDump)
+
+	tests := []struct {
+		attribute, key string
+		want           []string
+	}{
+		{"mnt-by", "example-mnt", []string{"aut-num:AS64496", "mntner:EXAMPLE-MNT", "person:JD1-TEST", "route:192.0.2.0/24AS64496"}},
+		{"admin-c", "JD1-TEST", []string{"mntner:EXAMPLE-MNT"}},
+		{"tech-c", "JD1-TEST", []string{"aut-num:AS64496"}},
+		{"origin", "AS64497", []string{"route:192.0.2.0/24AS64497"}},
+		{"member-of", "RS-OTHER", []string{"route:192.0.2.0/24AS64496"}},
+		{"org", "ORG-EXAMPLE", []string{}},
+		// The attributes without index are scanned.
+		{"mnt-routes", "EXAMPLE-MNT", []string{"route:192.0.2.0/24AS64497"}},
+	}
+
+	for _, tt := range tests {
+		if got := keys(s.Inverse(tt.attribute, tt.key)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(`Inverse(%s, %s) => %v, want %v`, tt.attribute, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestStoreChanges(t *testing.T) {
+	s := mustLoad(t, testDump)
+
+	// Replacing an object updates the indexes.
+	route, _ := rpsl.Parse("route: 192.0.2.0/24\norigin: AS64496\nmnt-by: OTHER-MNT\nsource: TEST\n")
+	if err := s.Apply(&nrtm.Operation{Kind: nrtm.Add, Object: *route}); err != nil {
+		t.Fatalf(`Apply => %v`, err)
+	}
+
+	if got := keys(s.Inverse("member-of", "RS-EXAMPLE")); len(got) != 0 {
+		t.Errorf(`Inverse(member-of, RS-EXAMPLE) => %v, want none`, got)
+	}
+
+	want := []string{"route:192.0.2.0/24AS64496", "route:192.0.2.0/24AS64497"}
+	if got := keys(s.Inverse("mnt-by", "OTHER-MNT")); !reflect.DeepEqual(got, want) {
+		t.Errorf(`Inverse(mnt-by, OTHER-MNT) => %v, want %v`, got, want)
+	}
+
+	// Deletions only need the primary key attributes.
+	del := rpsl.Object{Attributes: []rpsl.Attribute{{Name: "person"}, {Name: "nic-hdl", Value: "JD1-TEST"}}}
+	if err := s.Apply(&nrtm.Operation{Kind: nrtm.Delete, Object: del}); err != nil {
+		t.Fatalf(`Apply => %v`, err)
+	}
+
+	if _, ok := s.Get("person", "JD1-TEST"); ok || s.Len() != 4 {
+		t.Errorf(`Apply(DEL person) => found %v, Len %d, want 4`, ok, s.Len())
+	}
+
+	if s.Delete("person", "JD1-TEST") {
+		t.Errorf(`Delete(person, JD1-TEST) => true, want false`)
+	}
+
+	if !s.Delete("route", "192.0.2.0/24 AS64497") || len(s.Inverse("mnt-by", "OTHER-MNT")) != 1 {
+		t.Errorf(`Delete(route, 192.0.2.0/24 AS64497) => the index was not updated`)
+	}
+
+	if err := s.Put(rpsl.Object{Attributes: []rpsl.Attribute{{Name: "person", Value: "Jane Doe"}}}); !errors.Is(err, ErrNoPrimaryKey) {
+		t.Errorf(`Put(person without nic-hdl) => %v, want %v`, err, ErrNoPrimaryKey)
+	}
+
+	if err := s.Reset(); err != nil || s.Len() != 0 || len(s.Inverse("mnt-by", "EXAMPLE-MNT")) != 0 {
+		t.Errorf(`Reset => %v, Len %d, want an empty store`, err, s.Len())
+	}
+}
+
+func TestStoreConcurrency(t *testing.T) {
+	s := mustLoad(t, testDump)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				obj, _ := rpsl.Parse(fmt.Sprintf("route: 198.51.100.0/24\norigin: AS%d\nmnt-by: EXAMPLE-MNT\nsource: TEST\n", i*100+j))
+				_ = s.Put(*obj)
+				s.Inverse("mnt-by", "EXAMPLE-MNT")
+				s.Delete("route", obj.PrimaryKey())
+			}
+		}()
+	}
+
+	wg.Wait()
+	if s.Len() != 5 || len(s.Inverse("mnt-by", "EXAMPLE-MNT")) != 4 {
+		t.Errorf(`Len => %d, want 5`, s.Len())
+	}
+}
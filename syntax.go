@@ -0,0 +1,506 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Syntax checks the value of an attribute and returns an error describing why it is invalid.
+type Syntax func(value string) error
+
+// ListOf returns a Syntax checking a comma-separated list of values, each checked with syntax.
+func ListOf(syntax Syntax) Syntax {
+	return func(value string) error {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				return errors.New("empty list item")
+			}
+
+			if err := syntax(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// AnyOf returns a Syntax accepting the values accepted by at least one of the syntaxes. The error of the first syntax
+// is returned if no syntax accepts the value.
+func AnyOf(syntaxes ...Syntax) Syntax {
+	return func(value string) error {
+		var first error
+		for _, syntax := range syntaxes {
+			err := syntax(value)
+			if err == nil {
+				return nil
+			}
+
+			if first == nil {
+				first = err
+			}
+		}
+
+		return first
+	}
+}
+
+// ParseASN parses an AS number such as "AS64496" and returns its numeric value.
+func ParseASN(value string) (uint32, error) {
+	if len(value) < 3 || !strings.EqualFold(value[:2], "AS") {
+		return 0, fmt.Errorf("invalid AS number '%s'", value)
+	}
+
+	// Reject signs, which strconv accepts.
+	digits := value[2:]
+	if digits[0] < '0' || digits[0] > '9' {
+		return 0, fmt.Errorf("invalid AS number '%s'", value)
+	}
+
+	asn, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid AS number '%s'", value)
+	}
+
+	return uint32(asn), nil
+}
+
+// ParseRange parses an address range such as "192.0.2.0 - 192.0.2.255", or a prefix such as "2001:db8::/32", and
+// returns its first and last addresses.
+func ParseRange(value string) (netip.Addr, netip.Addr, error) {
+	first, last, found := strings.Cut(value, "-")
+	if !found {
+		prefix, err := parsePrefix(strings.TrimSpace(value))
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, err
+		}
+
+		return prefix.Addr(), LastAddr(prefix), nil
+	}
+
+	start, err := netip.ParseAddr(strings.TrimSpace(first))
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid address range '%s': invalid first address", value)
+	}
+
+	end, err := netip.ParseAddr(strings.TrimSpace(last))
+	if err != nil || end.Is4() != start.Is4() {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid address range '%s': invalid last address", value)
+	}
+
+	if end.Less(start) {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid address range '%s': last address before first address", value)
+	}
+
+	return start, end, nil
+}
+
+// CheckASNumber checks an AS number such as "AS64496".
+func CheckASNumber(value string) error {
+	_, err := ParseASN(value)
+	return err
+}
+
+// CheckASSetName checks an as-set name, such as "AS-FOO" or the hierarchical "AS64496:AS-FOO".
+func CheckASSetName(value string) error {
+	return checkSetName(value, "AS-")
+}
+
+// CheckRouteSetName checks a route-set name, such as "RS-FOO" or the hierarchical "AS64496:RS-FOO".
+func CheckRouteSetName(value string) error {
+	return checkSetName(value, "RS-")
+}
+
+// CheckFilterSetName checks a filter-set name, such as "FLTR-FOO" or the hierarchical "AS64496:FLTR-FOO".
+func CheckFilterSetName(value string) error {
+	return checkSetName(value, "FLTR-")
+}
+
+// CheckRtrSetName checks an rtr-set name, such as "RTRS-FOO" or the hierarchical "AS64496:RTRS-FOO".
+func CheckRtrSetName(value string) error {
+	return checkSetName(value, "RTRS-")
+}
+
+// CheckPeeringSetName checks a peering-set name, such as "PRNG-FOO" or the hierarchical "AS64496:PRNG-FOO".
+func CheckPeeringSetName(value string) error {
+	return checkSetName(value, "PRNG-")
+}
+
+// checkSetName checks a set name made of colon-separated AS numbers and set names. Per RFC 2622 section 5, at least
+// one component must be a set name of the class, identified by its prefix.
+func checkSetName(value string, prefix string) error {
+	found := false
+	for _, component := range strings.Split(value, ":") {
+		if CheckASNumber(component) == nil {
+			continue
+		}
+
+		if len(component) <= len(prefix) || !strings.EqualFold(component[:len(prefix)], prefix) {
+			return fmt.Errorf("invalid set name '%s': component '%s' is neither an AS number nor a name starting with '%s'", value, component, prefix)
+		}
+
+		if !isValidName(component) {
+			return fmt.Errorf("invalid set name '%s': illegal characters in '%s'", value, component)
+		}
+
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("invalid set name '%s': no component starting with '%s'", value, prefix)
+	}
+
+	return nil
+}
+
+// isValidName returns true if value is an RPSL object name: letters, digits, '_' and '-', starting with a letter and
+// not ending with '_' or '-'.
+func isValidName(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	first, last := value[0], value[len(value)-1]
+	if !(first >= 'a' && first <= 'z' || first >= 'A' && first <= 'Z') || last == '-' || last == '_' {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if !isValidKeyChar(c) && c != '_' || c == '*' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckObjectName checks an object name, such as a mntner name.
+func CheckObjectName(value string) error {
+	if !isValidName(value) {
+		return fmt.Errorf("invalid object name '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckOrganisationID checks an organisation identifier, such as "ORG-EXA1-RIPE".
+func CheckOrganisationID(value string) error {
+	if len(value) <= 4 || !strings.EqualFold(value[:4], "ORG-") || !isValidName(value) {
+		return fmt.Errorf("invalid organisation identifier '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckNicHandle checks a nic-handle, such as "JD1234-RIPE": two to four letters, an optional number of up to six
+// digits not starting with zero, and an optional suffix of up to ten letters. The value "AUTO-1" is also accepted to
+// let the database assign a nic-handle.
+func CheckNicHandle(value string) error {
+	upper := strings.ToUpper(value)
+	if len(upper) > 5 && strings.HasPrefix(upper, "AUTO-") && isDigits(upper[5:]) {
+		return nil
+	}
+
+	handle, suffix, hasSuffix := strings.Cut(upper, "-")
+	if hasSuffix && (len(suffix) < 2 || len(suffix) > 10 || !isLetters(suffix)) {
+		return fmt.Errorf("invalid nic-handle '%s'", value)
+	}
+
+	letters := strings.IndexFunc(handle, func(r rune) bool { return r < 'A' || r > 'Z' })
+	if letters < 0 {
+		letters = len(handle)
+	}
+
+	number := handle[letters:]
+	if letters < 2 || letters > 4 || len(number) > 6 || !isDigits(number) || strings.HasPrefix(number, "0") {
+		return fmt.Errorf("invalid nic-handle '%s'", value)
+	}
+
+	return nil
+}
+
+// isLetters returns true if value only contains ASCII uppercase letters.
+func isLetters(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < 'A' || value[i] > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDigits returns true if value only contains ASCII digits.
+func isDigits(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckEmail checks an e-mail address.
+func CheckEmail(value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || !strings.Contains(addr.Address[strings.LastIndexByte(addr.Address, '@')+1:], ".") {
+		return fmt.Errorf("invalid e-mail address '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckCountryCode checks an ISO 3166 two-letter country code.
+func CheckCountryCode(value string) error {
+	if len(value) != 2 || !isLetters(strings.ToUpper(value)) {
+		return fmt.Errorf("invalid country code '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckDate checks a date in the YYYYMMDD format.
+func CheckDate(value string) error {
+	if _, err := time.Parse("20060102", value); err != nil {
+		return fmt.Errorf("invalid date '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckTimestamp checks a timestamp in the RFC 3339 format used by the created and last-modified attributes.
+func CheckTimestamp(value string) error {
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return fmt.Errorf("invalid timestamp '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckIPv4Prefix checks an IPv4 prefix such as "192.0.2.0/24", without host bits set.
+func CheckIPv4Prefix(value string) error {
+	return checkPrefix(value, true)
+}
+
+// CheckIPv6Prefix checks an IPv6 prefix such as "2001:db8::/32", without host bits set.
+func CheckIPv6Prefix(value string) error {
+	return checkPrefix(value, false)
+}
+
+// checkPrefix checks a prefix of the given address family.
+func checkPrefix(value string, is4 bool) error {
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		// Drop the function name and input from the netip error.
+		msg := err.Error()
+		if idx := strings.LastIndex(msg, "): "); idx >= 0 {
+			msg = msg[idx+3:]
+		}
+
+		return fmt.Errorf("invalid prefix '%s': %s", value, msg)
+	}
+
+	if prefix.Addr().Is4() != is4 {
+		if is4 {
+			return fmt.Errorf("invalid prefix '%s': not an IPv4 prefix", value)
+		}
+
+		return fmt.Errorf("invalid prefix '%s': not an IPv6 prefix", value)
+	}
+
+	if prefix.Masked() != prefix {
+		return fmt.Errorf("invalid prefix '%s': host bits set, expected %s", value, prefix.Masked())
+	}
+
+	return nil
+}
+
+// CheckIPv4Range checks an IPv4 address range such as "192.0.2.0 - 192.0.2.255", or a prefix.
+func CheckIPv4Range(value string) error {
+	first, last, found := strings.Cut(value, "-")
+	if !found {
+		return CheckIPv4Prefix(strings.TrimSpace(value))
+	}
+
+	start, err := netip.ParseAddr(strings.TrimSpace(first))
+	if err != nil || !start.Is4() {
+		return fmt.Errorf("invalid address range '%s': invalid first address", value)
+	}
+
+	end, err := netip.ParseAddr(strings.TrimSpace(last))
+	if err != nil || !end.Is4() {
+		return fmt.Errorf("invalid address range '%s': invalid last address", value)
+	}
+
+	if end.Less(start) {
+		return fmt.Errorf("invalid address range '%s': last address before first address", value)
+	}
+
+	return nil
+}
+
+// CheckRoutePrefix checks a prefix followed by an optional range operator, such as "192.0.2.0/24^+" or
+// "2001:db8::/32^48-64".
+func CheckRoutePrefix(value string) error {
+	prefix, operator, found := strings.Cut(value, "^")
+	if err := AnyOf(CheckIPv4Prefix, CheckIPv6Prefix)(prefix); err != nil {
+		return err
+	}
+
+	if found {
+		return checkRangeOperator(value, operator)
+	}
+
+	return nil
+}
+
+// checkRangeOperator checks a range operator, without its leading '^'.
+func checkRangeOperator(value string, operator string) error {
+	if operator == "-" || operator == "+" {
+		return nil
+	}
+
+	low, high, found := strings.Cut(operator, "-")
+	if low == "" || !isDigits(low) || found && (high == "" || !isDigits(high)) {
+		return fmt.Errorf("invalid range operator in '%s'", value)
+	}
+
+	return nil
+}
+
+// CheckASRange checks a range of AS numbers such as "AS64496 - AS64511".
+func CheckASRange(value string) error {
+	first, last, found := strings.Cut(value, "-")
+	if !found {
+		return fmt.Errorf("invalid AS range '%s'", value)
+	}
+
+	start, err := ParseASN(strings.TrimSpace(first))
+	if err != nil {
+		return fmt.Errorf("invalid AS range '%s': %w", value, err)
+	}
+
+	end, err := ParseASN(strings.TrimSpace(last))
+	if err != nil {
+		return fmt.Errorf("invalid AS range '%s': %w", value, err)
+	}
+
+	if end < start {
+		return fmt.Errorf("invalid AS range '%s': last AS number before first AS number", value)
+	}
+
+	return nil
+}
+
+// withRangeOperator returns a Syntax accepting the values accepted by syntax, followed by an optional range operator.
+func withRangeOperator(syntax Syntax) Syntax {
+	return func(value string) error {
+		name, operator, found := strings.Cut(value, "^")
+		if err := syntax(name); err != nil {
+			return err
+		}
+
+		if found {
+			return checkRangeOperator(value, operator)
+		}
+
+		return nil
+	}
+}
+
+// syntaxes holds the syntax of the attributes of the built-in templates, by attribute name.
+var syntaxes = map[string]Syntax{
+	"abuse-c":        CheckNicHandle,
+	"abuse-mailbox":  CheckEmail,
+	"admin-c":        CheckNicHandle,
+	"as-block":       CheckASRange,
+	"as-set":         CheckASSetName,
+	"author":         CheckNicHandle,
+	"aut-num":        CheckASNumber,
+	"country":        CheckCountryCode,
+	"created":        CheckTimestamp,
+	"e-mail":         CheckEmail,
+	"filter-set":     CheckFilterSetName,
+	"inet6num":       CheckIPv6Prefix,
+	"inetnum":        CheckIPv4Range,
+	"irt-nfy":        CheckEmail,
+	"last-modified":  CheckTimestamp,
+	"local-as":       CheckASNumber,
+	"mbrs-by-ref":    ListOf(CheckObjectName),
+	"mnt-by":         ListOf(CheckObjectName),
+	"mnt-domains":    ListOf(CheckObjectName),
+	"mnt-irt":        ListOf(CheckObjectName),
+	"mnt-lower":      ListOf(CheckObjectName),
+	"mnt-nfy":        CheckEmail,
+	"mnt-ref":        ListOf(CheckObjectName),
+	"mntner":         CheckObjectName,
+	"nic-hdl":        CheckNicHandle,
+	"notify":         CheckEmail,
+	"org":            CheckOrganisationID,
+	"organisation":   CheckOrganisationID,
+	"origin":         CheckASNumber,
+	"peering-set":    CheckPeeringSetName,
+	"ping-hdl":       CheckNicHandle,
+	"ref-nfy":        CheckEmail,
+	"route":          CheckIPv4Prefix,
+	"route-set":      CheckRouteSetName,
+	"route6":         CheckIPv6Prefix,
+	"rtr-set":        CheckRtrSetName,
+	"sponsoring-org": CheckOrganisationID,
+	"tech-c":         CheckNicHandle,
+	"upd-to":         CheckEmail,
+	"zone-c":         CheckNicHandle,
+}
+
+// classSyntaxes holds the syntax of the attributes whose syntax depends on the class, by class and attribute name.
+var classSyntaxes = map[string]map[string]Syntax{
+	"as-set": {
+		"members": ListOf(AnyOf(CheckASNumber, CheckASSetName)),
+	},
+	"aut-num": {
+		"member-of": ListOf(CheckASSetName),
+	},
+	"inet-rtr": {
+		"member-of": ListOf(CheckRtrSetName),
+	},
+	"route": {
+		"member-of": ListOf(CheckRouteSetName),
+	},
+	"route6": {
+		"member-of": ListOf(CheckRouteSetName),
+	},
+	"route-set": {
+		"members": ListOf(AnyOf(
+			withRangeOperator(CheckIPv4Prefix),
+			withRangeOperator(CheckRouteSetName),
+			withRangeOperator(CheckASSetName),
+			withRangeOperator(CheckASNumber),
+		)),
+		"mp-members": ListOf(AnyOf(
+			CheckRoutePrefix,
+			withRangeOperator(CheckRouteSetName),
+			withRangeOperator(CheckASSetName),
+			withRangeOperator(CheckASNumber),
+		)),
+	},
+}
+
+// lookupSyntax returns the syntax of an attribute of a class, or nil if the value is free-form.
+func lookupSyntax(class string, name string) Syntax {
+	if syntax, ok := classSyntaxes[class][name]; ok {
+		return syntax
+	}
+
+	return syntaxes[name]
+}
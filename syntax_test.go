@@ -0,0 +1,165 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSyntax(t *testing.T) {
+	tests := []struct {
+		name    string
+		syntax  Syntax
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:    "ASNumber",
+			syntax:  CheckASNumber,
+			valid:   []string{"AS0", "AS64496", "as4294967295"},
+			invalid: []string{"AS", "ASfoo", "AS-1", "AS+1", "AS4294967296", "64496"},
+		},
+		{
+			name:    "ASSetName",
+			syntax:  CheckASSetName,
+			valid:   []string{"AS-FOO", "as-foo_bar", "AS64496:AS-FOO", "AS-FOO:AS-BAR:AS64496"},
+			invalid: []string{"AS-", "AS-FOO-", "RS-FOO", "AS64496", "AS64496:RS-FOO", "AS-FO.O"},
+		},
+		{
+			name:    "RouteSetName",
+			syntax:  CheckRouteSetName,
+			valid:   []string{"RS-FOO", "AS64496:RS-FOO"},
+			invalid: []string{"AS-FOO", "AS64496:AS-FOO"},
+		},
+		{
+			name:    "IPv4Prefix",
+			syntax:  CheckIPv4Prefix,
+			valid:   []string{"192.0.2.0/24", "0.0.0.0/0"},
+			invalid: []string{"10.0.0.0/33", "192.0.2.1/24", "2001:db8::/32", "192.0.2.0"},
+		},
+		{
+			name:    "IPv6Prefix",
+			syntax:  CheckIPv6Prefix,
+			valid:   []string{"2001:db8::/32", "::/0"},
+			invalid: []string{"2001:db8::/129", "2001:db8::1/32", "192.0.2.0/24"},
+		},
+		{
+			name:    "IPv4Range",
+			syntax:  CheckIPv4Range,
+			valid:   []string{"192.0.2.0 - 192.0.2.255", "192.0.2.0-192.0.2.0", "192.0.2.0/24"},
+			invalid: []string{"192.0.2.255 - 192.0.2.0", "192.0.2.0 - 2001:db8::", "192.0.2.0 -"},
+		},
+		{
+			name:    "RoutePrefix",
+			syntax:  CheckRoutePrefix,
+			valid:   []string{"192.0.2.0/24^+", "192.0.2.0/24^-", "192.0.2.0/24^24", "2001:db8::/32^48-64"},
+			invalid: []string{"192.0.2.0/24^", "192.0.2.0/24^a", "192.0.2.0/24^24-"},
+		},
+		{
+			name:    "NicHandle",
+			syntax:  CheckNicHandle,
+			valid:   []string{"JD1234-RIPE", "ab1-dev", "ABC", "AUTO-1", "VM1-DEV"},
+			invalid: []string{"J1", "ABCDE1", "JD01-RIPE", "JD1234567", "JD1-R", "AUTO-"},
+		},
+		{
+			name:    "Email",
+			syntax:  CheckEmail,
+			valid:   []string{"noc@example.net", "John Doe <jd@example.net>"},
+			invalid: []string{"noc", "noc@localhost", "@example.net"},
+		},
+		{
+			name:    "CountryCode",
+			syntax:  CheckCountryCode,
+			valid:   []string{"CH", "nl", "EU"},
+			invalid: []string{"CHE", "C", "1A"},
+		},
+		{
+			name:    "Timestamp",
+			syntax:  CheckTimestamp,
+			valid:   []string{"2024-01-31T12:00:00Z"},
+			invalid: []string{"20240131", "2024-01-31"},
+		},
+		{
+			name:    "Date",
+			syntax:  CheckDate,
+			valid:   []string{"20240131"},
+			invalid: []string{"20240132", "2024-01-31"},
+		},
+		{
+			name:    "ListOfObjectNames",
+			syntax:  ListOf(CheckObjectName),
+			valid:   []string{"FOO-MNT", "FOO-MNT, BAR-MNT"},
+			invalid: []string{"FOO-MNT,", "FOO MNT", "1-MNT"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, value := range tc.valid {
+				if err := tc.syntax(value); err != nil {
+					t.Errorf("%q => %v, want nil", value, err)
+				}
+			}
+
+			for _, value := range tc.invalid {
+				if err := tc.syntax(value); err == nil {
+					t.Errorf("%q => nil, want error", value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseASN(t *testing.T) {
+	asn, err := ParseASN("AS4200000000")
+	if err != nil {
+		t.Fatalf("ParseASN() error: %v", err)
+	}
+
+	if asn != 4200000000 {
+		t.Fatalf("ParseASN() => %v, want %v", asn, 4200000000)
+	}
+}
+
+func TestValidateSyntax(t *testing.T) {
+	obj, err := Parse("route:          10.0.0.0/33\n" +
+		"origin:         ASfoo\n" +
+		"member-of:      RS-FOO, AS-BAR\n" +
+		"mnt-by:         EXAMPLE-MNT\n" +
+		"source:         TEST")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	var violations ValidationErrors
+	if !errors.As(Validate(obj), &violations) {
+		t.Fatalf("Validate() error: want ValidationErrors")
+	}
+
+	expected := []struct {
+		attribute string
+		line      int
+		message   string
+	}{
+		{"route", 1, "invalid prefix '10.0.0.0/33': prefix length out of range"},
+		{"origin", 2, "invalid AS number 'ASfoo'"},
+		{"member-of", 3, "invalid set name 'AS-BAR'"},
+	}
+
+	if len(violations) != len(expected) {
+		t.Fatalf("Validate() violations: got %v, want %v", violations, len(expected))
+	}
+
+	for i, v := range violations {
+		if v.Attribute != expected[i].attribute || v.Line != expected[i].line {
+			t.Errorf("violation %d: got %s at line %d, want %s at line %d", i, v.Attribute, v.Line, expected[i].attribute, expected[i].line)
+		}
+
+		if !strings.Contains(v.Error(), expected[i].message) {
+			t.Errorf("violation %d: got %q, want %q", i, v.Error(), expected[i].message)
+		}
+	}
+}
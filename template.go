@@ -0,0 +1,330 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Requirement tells whether an attribute must be present in an object.
+type Requirement uint8
+
+const (
+	// Mandatory attributes must be present in the object.
+	Mandatory Requirement = iota
+	// Optional attributes may be omitted from the object.
+	Optional
+	// Generated attributes are set by the database, they may be omitted from the object.
+	Generated
+)
+
+// String returns a string representation of the Requirement.
+func (r Requirement) String() string {
+	switch r {
+	case Mandatory:
+		return "mandatory"
+	case Optional:
+		return "optional"
+	case Generated:
+		return "generated"
+	default:
+		return fmt.Sprintf("Requirement(%d)", uint8(r))
+	}
+}
+
+// Cardinality tells how many times an attribute may appear in an object.
+type Cardinality uint8
+
+const (
+	// Single attributes appear at most once in the object.
+	Single Cardinality = iota
+	// Multiple attributes may appear any number of times in the object.
+	Multiple
+)
+
+// String returns a string representation of the Cardinality.
+func (c Cardinality) String() string {
+	switch c {
+	case Single:
+		return "single"
+	case Multiple:
+		return "multiple"
+	default:
+		return fmt.Sprintf("Cardinality(%d)", uint8(c))
+	}
+}
+
+// Key tells how an attribute is indexed. Keys can be combined.
+type Key uint8
+
+const (
+	// PrimaryKey attributes identify the object. Composite primary keys are made of several attributes.
+	PrimaryKey Key = 1 << iota
+	// LookupKey attributes can be used to query the object.
+	LookupKey
+	// InverseKey attributes reference other objects and can be used for inverse queries.
+	InverseKey
+)
+
+// AttributeTemplate describes an attribute of an object class.
+type AttributeTemplate struct {
+	Name        string
+	Requirement Requirement
+	Cardinality Cardinality
+	Keys        Key
+
+	// Syntax checks the values of the attribute. A nil Syntax accepts any value.
+	Syntax Syntax
+}
+
+// Template describes an object class: the attributes it allows, in their conventional order, and their cardinality.
+// The first attribute gives its name to the class.
+type Template struct {
+	Class      string
+	Attributes []AttributeTemplate
+}
+
+// Attribute returns the template of the named attribute, or nil if the attribute is not allowed in the class.
+func (t *Template) Attribute(name string) *AttributeTemplate {
+	name = strings.ToLower(name)
+	for i := range t.Attributes {
+		if t.Attributes[i].Name == name {
+			return &t.Attributes[i]
+		}
+	}
+
+	return nil
+}
+
+// Keys returns the names of the attributes indexed with the given key, in template order.
+func (t *Template) Keys(key Key) []string {
+	names := make([]string, 0, 2)
+	for _, attr := range t.Attributes {
+		if attr.Keys&key != 0 {
+			names = append(names, attr.Name)
+		}
+	}
+
+	return names
+}
+
+// Validate checks the Object against the template and returns every violation found as ValidationErrors, or nil if
+// the Object is valid.
+func (t *Template) Validate(o *Object) error {
+	var errs ValidationErrors
+
+	if err := o.EnsureClass(t.Class); err != nil {
+		errs = append(errs, &ValidationError{Class: t.Class, Err: err})
+	}
+
+	// Count the attributes and report the unknown ones.
+	counts := make(map[string]int, len(t.Attributes))
+	lines := make(map[string]int, len(t.Attributes))
+	for _, attr := range o.Attributes {
+		template := t.Attribute(attr.Name)
+		if template == nil {
+			errs = append(errs, &ValidationError{
+				Class:     t.Class,
+				Attribute: attr.Name,
+				Line:      attr.Line,
+				Err:       fmt.Errorf("attribute '%s' is not allowed in class '%s'", attr.Name, t.Class),
+			})
+
+			continue
+		}
+
+		if template.Syntax != nil {
+			if err := template.Syntax(attr.Value); err != nil {
+				errs = append(errs, &ValidationError{
+					Class:     t.Class,
+					Attribute: attr.Name,
+					Line:      attr.Line,
+					Err:       fmt.Errorf("attribute '%s' has an invalid value: %w", attr.Name, err),
+				})
+			}
+		}
+
+		counts[attr.Name]++
+		if counts[attr.Name] == 2 {
+			lines[attr.Name] = attr.Line
+		}
+	}
+
+	for _, attr := range t.Attributes {
+		count := counts[attr.Name]
+		if count == 0 && attr.Requirement == Mandatory {
+			errs = append(errs, &ValidationError{
+				Class:     t.Class,
+				Attribute: attr.Name,
+				Err:       fmt.Errorf("attribute '%s' is (%s, %s) but found none", attr.Name, attr.Requirement, attr.Cardinality),
+			})
+		}
+
+		if count > 1 && attr.Cardinality == Single {
+			errs = append(errs, &ValidationError{
+				Class:     t.Class,
+				Attribute: attr.Name,
+				Line:      lines[attr.Name],
+				Err:       fmt.Errorf("attribute '%s' is (%s, %s) but found multiple", attr.Name, attr.Requirement, attr.Cardinality),
+			})
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// ValidationError describes an Object violating the template of its class.
+type ValidationError struct {
+	// Class is the class of the object.
+	Class string
+	// Attribute is the name of the offending attribute, if any.
+	Attribute string
+	// Line is the one-based line number of the offending attribute in the input. It is zero when unknown.
+	Line int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns a string representation of the ValidationError.
+func (e *ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is a report of all the violations found in an Object. Each ValidationError can be retrieved with
+// errors.As.
+type ValidationErrors []*ValidationError
+
+// Error returns a string representation of the ValidationErrors.
+func (e ValidationErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	var str strings.Builder
+	fmt.Fprintf(&str, "%d violations found:", len(e))
+	for _, err := range e {
+		str.WriteString("\n\t")
+		str.WriteString(err.Error())
+	}
+
+	return str.String()
+}
+
+// Unwrap returns the violations.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// Registry is a set of templates indexed by class. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]*Template
+}
+
+// NewRegistry returns a Registry holding the given templates.
+func NewRegistry(templates ...*Template) *Registry {
+	r := &Registry{templates: make(map[string]*Template, len(templates))}
+	for _, t := range templates {
+		r.Register(t)
+	}
+
+	return r
+}
+
+// Register adds a template to the Registry, replacing any template of the same class.
+func (r *Registry) Register(t *Template) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[strings.ToLower(t.Class)] = t
+}
+
+// Lookup returns the template of a class, or nil if the class is unknown.
+func (r *Registry) Lookup(class string) *Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.templates[strings.ToLower(class)]
+}
+
+// Classes returns the classes of the templates in the Registry.
+func (r *Registry) Classes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	classes := make([]string, 0, len(r.templates))
+	for class := range r.templates {
+		classes = append(classes, class)
+	}
+
+	return classes
+}
+
+// Validate checks the Object against the template of its class and returns every violation found as
+// ValidationErrors, or nil if the Object is valid.
+func (r *Registry) Validate(o *Object) error {
+	class := o.Class()
+	if class == "" {
+		return ValidationErrors{{Err: errors.New("object has no attributes")}}
+	}
+
+	t := r.Lookup(class)
+	if t == nil {
+		return ValidationErrors{{Class: class, Err: fmt.Errorf("unknown object class '%s'", class)}}
+	}
+
+	return t.Validate(o)
+}
+
+// DefaultRegistry holds the templates of the RIPE database.
+var DefaultRegistry = NewRegistry(ripeTemplates()...)
+
+// RegisterTemplate adds a template to the DefaultRegistry, replacing any template of the same class.
+func RegisterTemplate(t *Template) {
+	DefaultRegistry.Register(t)
+}
+
+// LookupTemplate returns the template of a class from the DefaultRegistry, or nil if the class is unknown.
+func LookupTemplate(class string) *Template {
+	return DefaultRegistry.Lookup(class)
+}
+
+// Validate checks the Object against the template of its class in the DefaultRegistry and returns every violation
+// found as ValidationErrors, or nil if the Object is valid.
+//
+// Example:
+//
+//	err := Validate(obj)
+//
+//	var violations ValidationErrors
+//	if errors.As(err, &violations) {
+//	    for _, v := range violations {
+//	        fmt.Printf("%s: %v\n", v.Attribute, v.Err)
+//	    }
+//	}
+func Validate(o *Object) error {
+	return DefaultRegistry.Validate(o)
+}
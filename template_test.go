@@ -0,0 +1,138 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		violations []string
+	}{
+		{
+			name: "Valid",
+			input: "route:          192.0.2.0/24\n" +
+				"origin:         AS64496\n" +
+				"mnt-by:         EXAMPLE-MNT\n" +
+				"source:         TEST",
+		},
+		{
+			name: "MissingMandatory",
+			input: "route:          192.0.2.0/24\n" +
+				"mnt-by:         EXAMPLE-MNT\n" +
+				"source:         TEST",
+			violations: []string{
+				"attribute 'origin' is (mandatory, single) but found none",
+			},
+		},
+		{
+			name: "AllViolations",
+			input: "route:          192.0.2.0/24\n" +
+				"origin:         AS64496\n" +
+				"origin:         AS64497\n" +
+				"foo:            bar\n" +
+				"source:         TEST",
+			violations: []string{
+				"line 4: attribute 'foo' is not allowed in class 'route'",
+				"line 3: attribute 'origin' is (mandatory, single) but found multiple",
+				"attribute 'mnt-by' is (mandatory, multiple) but found none",
+			},
+		},
+		{
+			name:  "UnknownClass",
+			input: "foo: bar",
+			violations: []string{
+				"unknown object class 'foo'",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, err := Parse(tc.input)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+
+			err = Validate(obj)
+			if len(tc.violations) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error: %v", err)
+				}
+
+				return
+			}
+
+			var violations ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatalf("Validate() error: got %v, want ValidationErrors", err)
+			}
+
+			if len(violations) != len(tc.violations) {
+				t.Fatalf("Validate() violations: got %v, want %v", violations, tc.violations)
+			}
+
+			for i, v := range violations {
+				if v.Error() != tc.violations[i] {
+					t.Errorf("Validate() violation %d: got %q, want %q", i, v.Error(), tc.violations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateKeys(t *testing.T) {
+	route := LookupTemplate("ROUTE")
+	if route == nil {
+		t.Fatalf("LookupTemplate(route) => nil")
+	}
+
+	primary := route.Keys(PrimaryKey)
+	if strings.Join(primary, ",") != "route,origin" {
+		t.Errorf("Keys(PrimaryKey) => %v, want %v", primary, []string{"route", "origin"})
+	}
+
+	inverse := LookupTemplate("person").Keys(InverseKey)
+	if strings.Join(inverse, ",") != "org,notify,mnt-by" {
+		t.Errorf("Keys(InverseKey) => %v, want %v", inverse, []string{"org", "notify", "mnt-by"})
+	}
+
+	if a := route.Attribute("MNT-BY"); a == nil || a.Requirement != Mandatory || a.Cardinality != Multiple {
+		t.Errorf("Attribute(mnt-by) => %+v", a)
+	}
+}
+
+func TestCustomRegistry(t *testing.T) {
+	registry := NewRegistry(&Template{
+		Class: "route",
+		Attributes: []AttributeTemplate{
+			{Name: "route", Requirement: Mandatory, Cardinality: Single, Keys: PrimaryKey},
+			{Name: "origin", Requirement: Mandatory, Cardinality: Single, Keys: PrimaryKey},
+			{Name: "source", Requirement: Mandatory, Cardinality: Single},
+		},
+	})
+
+	obj, err := Parse("route: 192.0.2.0/24\norigin: AS64496\nsource: RADB")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if err := registry.Validate(obj); err != nil {
+		t.Fatalf("Registry.Validate() error: %v", err)
+	}
+
+	// The default registry requires mnt-by.
+	if err := Validate(obj); err == nil {
+		t.Fatalf("Validate() error: got nil, want error")
+	}
+
+	if registry.Lookup("aut-num") != nil {
+		t.Fatalf("Registry.Lookup(aut-num) => not nil, want nil")
+	}
+}
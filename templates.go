@@ -0,0 +1,344 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpsl
+
+// attr is a shorthand to describe the attributes of the built-in templates.
+func attr(name string, requirement Requirement, cardinality Cardinality, keys Key) AttributeTemplate {
+	return AttributeTemplate{Name: name, Requirement: requirement, Cardinality: cardinality, Keys: keys}
+}
+
+// newTemplate creates a template for a class, appending the attributes common to every class of the RIPE database and
+// setting the syntax of the attributes.
+func newTemplate(class string, attributes ...AttributeTemplate) *Template {
+	attributes = append(attributes,
+		attr("created", Generated, Single, 0),
+		attr("last-modified", Generated, Single, 0),
+		attr("source", Mandatory, Single, 0),
+	)
+
+	for i := range attributes {
+		attributes[i].Syntax = lookupSyntax(class, attributes[i].Name)
+	}
+
+	return &Template{Class: class, Attributes: attributes}
+}
+
+// ripeTemplates returns the templates of the object classes of the RIPE database.
+func ripeTemplates() []*Template {
+	return []*Template{
+		newTemplate("as-block",
+			attr("as-block", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("as-set",
+			attr("as-set", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("members", Optional, Multiple, 0),
+			attr("mbrs-by-ref", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+		),
+		newTemplate("aut-num",
+			attr("aut-num", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("as-name", Mandatory, Single, 0),
+			attr("descr", Optional, Multiple, 0),
+			attr("member-of", Optional, Multiple, InverseKey),
+			attr("import-via", Optional, Multiple, 0),
+			attr("import", Optional, Multiple, 0),
+			attr("mp-import", Optional, Multiple, 0),
+			attr("export-via", Optional, Multiple, 0),
+			attr("export", Optional, Multiple, 0),
+			attr("mp-export", Optional, Multiple, 0),
+			attr("default", Optional, Multiple, 0),
+			attr("mp-default", Optional, Multiple, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Single, InverseKey),
+			attr("sponsoring-org", Optional, Single, 0),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("abuse-c", Optional, Single, InverseKey),
+			attr("status", Generated, Single, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("domain",
+			attr("domain", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("zone-c", Mandatory, Multiple, InverseKey),
+			attr("nserver", Mandatory, Multiple, InverseKey),
+			attr("ds-rdata", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("filter-set",
+			attr("filter-set", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("filter", Optional, Single, 0),
+			attr("mp-filter", Optional, Single, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+		),
+		newTemplate("inet6num",
+			attr("inet6num", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("netname", Mandatory, Single, LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("country", Mandatory, Multiple, 0),
+			attr("geofeed", Optional, Single, 0),
+			attr("geoloc", Optional, Single, 0),
+			attr("language", Optional, Multiple, 0),
+			attr("org", Optional, Single, InverseKey),
+			attr("sponsoring-org", Optional, Single, 0),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("abuse-c", Optional, Single, InverseKey),
+			attr("status", Mandatory, Single, 0),
+			attr("assignment-size", Optional, Single, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+			attr("mnt-routes", Optional, Multiple, InverseKey),
+			attr("mnt-domains", Optional, Multiple, InverseKey),
+			attr("mnt-irt", Optional, Multiple, InverseKey),
+		),
+		newTemplate("inetnum",
+			attr("inetnum", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("netname", Mandatory, Single, LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("country", Mandatory, Multiple, 0),
+			attr("geofeed", Optional, Single, 0),
+			attr("geoloc", Optional, Single, 0),
+			attr("language", Optional, Multiple, 0),
+			attr("org", Optional, Single, InverseKey),
+			attr("sponsoring-org", Optional, Single, 0),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("abuse-c", Optional, Single, InverseKey),
+			attr("status", Mandatory, Single, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+			attr("mnt-domains", Optional, Multiple, InverseKey),
+			attr("mnt-routes", Optional, Multiple, InverseKey),
+			attr("mnt-irt", Optional, Multiple, InverseKey),
+		),
+		newTemplate("inet-rtr",
+			attr("inet-rtr", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("alias", Optional, Multiple, 0),
+			attr("local-as", Mandatory, Single, InverseKey),
+			attr("ifaddr", Mandatory, Multiple, LookupKey),
+			attr("interface", Optional, Multiple, LookupKey),
+			attr("peer", Optional, Multiple, 0),
+			attr("mp-peer", Optional, Multiple, 0),
+			attr("member-of", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("irt",
+			attr("irt", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("address", Mandatory, Multiple, 0),
+			attr("phone", Optional, Multiple, 0),
+			attr("fax-no", Optional, Multiple, 0),
+			attr("e-mail", Mandatory, Multiple, LookupKey),
+			attr("abuse-mailbox", Mandatory, Single, InverseKey),
+			attr("signature", Optional, Multiple, 0),
+			attr("encryption", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("auth", Mandatory, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("irt-nfy", Optional, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("key-cert",
+			attr("key-cert", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("method", Generated, Single, 0),
+			attr("owner", Generated, Multiple, 0),
+			attr("fingerpr", Generated, Single, InverseKey),
+			attr("certif", Mandatory, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("admin-c", Optional, Multiple, InverseKey),
+			attr("tech-c", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("mntner",
+			attr("mntner", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("tech-c", Optional, Multiple, InverseKey),
+			attr("upd-to", Mandatory, Multiple, InverseKey),
+			attr("mnt-nfy", Optional, Multiple, InverseKey),
+			attr("auth", Mandatory, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("organisation",
+			attr("organisation", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("org-name", Mandatory, Single, LookupKey),
+			attr("org-type", Mandatory, Single, 0),
+			attr("descr", Optional, Multiple, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("address", Mandatory, Multiple, 0),
+			attr("country", Optional, Single, 0),
+			attr("phone", Optional, Multiple, 0),
+			attr("fax-no", Optional, Multiple, 0),
+			attr("e-mail", Mandatory, Multiple, LookupKey),
+			attr("geoloc", Optional, Single, 0),
+			attr("language", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("admin-c", Optional, Multiple, InverseKey),
+			attr("tech-c", Optional, Multiple, InverseKey),
+			attr("abuse-c", Optional, Single, InverseKey),
+			attr("ref-nfy", Optional, Multiple, InverseKey),
+			attr("mnt-ref", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("peering-set",
+			attr("peering-set", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("peering", Optional, Multiple, 0),
+			attr("mp-peering", Optional, Multiple, 0),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+		),
+		newTemplate("person",
+			attr("person", Mandatory, Single, LookupKey),
+			attr("address", Mandatory, Multiple, 0),
+			attr("phone", Mandatory, Multiple, 0),
+			attr("fax-no", Optional, Multiple, 0),
+			attr("e-mail", Optional, Multiple, LookupKey),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("nic-hdl", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("poem",
+			attr("poem", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("form", Mandatory, Single, InverseKey),
+			attr("text", Mandatory, Multiple, 0),
+			attr("author", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Single, InverseKey),
+		),
+		newTemplate("poetic-form",
+			attr("poetic-form", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("admin-c", Optional, Multiple, InverseKey),
+			attr("tech-c", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("role",
+			attr("role", Mandatory, Single, LookupKey),
+			attr("address", Mandatory, Multiple, 0),
+			attr("phone", Optional, Multiple, 0),
+			attr("fax-no", Optional, Multiple, 0),
+			attr("e-mail", Mandatory, Multiple, LookupKey),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("admin-c", Optional, Multiple, InverseKey),
+			attr("tech-c", Optional, Multiple, InverseKey),
+			attr("nic-hdl", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("abuse-mailbox", Optional, Single, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+		),
+		newTemplate("route", routeAttributes("route")...),
+		newTemplate("route6", routeAttributes("route6")...),
+		newTemplate("route-set",
+			attr("route-set", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("members", Optional, Multiple, 0),
+			attr("mp-members", Optional, Multiple, 0),
+			attr("mbrs-by-ref", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+		),
+		newTemplate("rtr-set",
+			attr("rtr-set", Mandatory, Single, PrimaryKey|LookupKey),
+			attr("descr", Optional, Multiple, 0),
+			attr("members", Optional, Multiple, 0),
+			attr("mp-members", Optional, Multiple, 0),
+			attr("mbrs-by-ref", Optional, Multiple, InverseKey),
+			attr("remarks", Optional, Multiple, 0),
+			attr("org", Optional, Multiple, InverseKey),
+			attr("tech-c", Mandatory, Multiple, InverseKey),
+			attr("admin-c", Mandatory, Multiple, InverseKey),
+			attr("notify", Optional, Multiple, InverseKey),
+			attr("mnt-by", Mandatory, Multiple, InverseKey),
+			attr("mnt-lower", Optional, Multiple, InverseKey),
+		),
+	}
+}
+
+// routeAttributes returns the attributes of the route and route6 classes, whose primary key is composed of the
+// prefix and the origin.
+func routeAttributes(class string) []AttributeTemplate {
+	return []AttributeTemplate{
+		attr(class, Mandatory, Single, PrimaryKey|LookupKey),
+		attr("descr", Optional, Multiple, 0),
+		attr("origin", Mandatory, Single, PrimaryKey|InverseKey),
+		attr("pingable", Optional, Multiple, 0),
+		attr("ping-hdl", Optional, Multiple, InverseKey),
+		attr("holes", Optional, Multiple, 0),
+		attr("org", Optional, Multiple, InverseKey),
+		attr("member-of", Optional, Multiple, InverseKey),
+		attr("inject", Optional, Multiple, 0),
+		attr("aggr-mtd", Optional, Single, 0),
+		attr("aggr-bndry", Optional, Single, 0),
+		attr("export-comps", Optional, Single, 0),
+		attr("components", Optional, Single, 0),
+		attr("remarks", Optional, Multiple, 0),
+		attr("notify", Optional, Multiple, InverseKey),
+		attr("mnt-lower", Optional, Multiple, InverseKey),
+		attr("mnt-routes", Optional, Multiple, InverseKey),
+		attr("mnt-by", Mandatory, Multiple, InverseKey),
+	}
+}
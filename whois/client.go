@@ -0,0 +1,232 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package whois implements a client for the whois protocol (RFC 3912), returning the objects of the responses of the
+// RIPE database and compatible servers.
+//
+// Example:
+//
+//	c := whois.NewClient(whois.DefaultAddr)
+//	resp, err := c.Query(ctx, &whois.Query{Text: "AS3333", NoRecursion: true})
+//	if err != nil {
+//	    log.Fatalf("Failed to query: %v", err)
+//	}
+//
+//	for _, obj := range resp.Objects {
+//	    fmt.Println(obj.String())
+//	}
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/frederic-arr/rpsl-go/internal/netctx"
+)
+
+// DefaultAddr is the address of the whois server of the RIPE database.
+const DefaultAddr = "whois.ripe.net:43"
+
+// MaxResponse is the largest response accepted by Client.Query and Session.Query, in bytes.
+const MaxResponse = 256 << 20
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// Dial opens the connections to the server. When nil, a net.Dialer is used.
+	Dial func(ctx context.Context, network string, addr string) (net.Conn, error)
+	// Timeout bounds every query whose context has no deadline. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Client sends queries to a whois server. It is safe for concurrent use.
+type Client struct {
+	addr string
+	opts ClientOptions
+}
+
+// NewClient returns a Client for the server at addr, in the "host:port" form.
+func NewClient(addr string) *Client {
+	return NewClientWithOptions(addr, ClientOptions{})
+}
+
+// NewClientWithOptions returns a Client for the server at addr, in the "host:port" form, with the given options.
+func NewClientWithOptions(addr string, opts ClientOptions) *Client {
+	if opts.Dial == nil {
+		var d net.Dialer
+		opts.Dial = d.DialContext
+	}
+
+	return &Client{addr: addr, opts: opts}
+}
+
+// Query sends a query on a new connection and returns the response, read until the server closes the connection. If
+// the server reported an error, the Response is returned along with its first *Error.
+func (c *Client) Query(ctx context.Context, q *Query) (*Response, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	conn, err := c.opts.Dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+	defer netctx.Watch(ctx, conn)()
+
+	if _, err := io.WriteString(conn, q.String()+"\r\n"); err != nil {
+		return nil, netctx.Err(ctx, err)
+	}
+
+	data, err := readAll(conn, MaxResponse)
+	if err != nil {
+		return nil, netctx.Err(ctx, err)
+	}
+
+	return ParseResponse(data)
+}
+
+// Open opens a persistent connection to the server, on which several queries can be sent with the -k flag.
+func (c *Client) Open(ctx context.Context) (*Session, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	conn, err := c.opts.Dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{client: c, conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+// context returns the context of a query, bounded by the timeout of the Client if it has no deadline.
+func (c *Client) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); !ok && c.opts.Timeout > 0 {
+		return context.WithTimeout(ctx, c.opts.Timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// Session is a persistent connection to a whois server. Queries are sent one at a time, and the end of every response
+// is marked by two empty lines. A Session is safe for concurrent use.
+type Session struct {
+	client  *Client
+	mu      sync.Mutex
+	conn    net.Conn
+	r       *bufio.Reader
+	started bool
+	err     error
+}
+
+// Query sends a query and returns its response. If the server reported an error, the Response is returned along with
+// its first *Error. After any other error, the Session is unusable and must be closed.
+func (s *Session) Query(ctx context.Context, q *Query) (*Response, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	ctx, cancel := s.client.context(ctx)
+	defer cancel()
+	defer netctx.Watch(ctx, s.conn)()
+
+	line := q.String()
+	if !s.started {
+		line = "-k " + line
+	}
+
+	data, err := s.query(line)
+	if err != nil {
+		s.err = netctx.Err(ctx, err)
+		return nil, s.err
+	}
+
+	s.started = true
+	return ParseResponse(data)
+}
+
+// query sends a query line and reads the response, up to two consecutive empty lines.
+func (s *Session) query(line string) ([]byte, error) {
+	if _, err := io.WriteString(s.conn, line+"\r\n"); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	empty := 0
+	start := 0
+	for empty < 2 {
+		line, err := s.r.ReadSlice('\n')
+		data = append(data, line...)
+		if len(data) > MaxResponse {
+			return nil, errTooLarge(MaxResponse)
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// The line continues in the next slice.
+			continue
+		} else if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return nil, err
+		}
+
+		if line := data[start:]; len(line) == 1 || (len(line) == 2 && line[0] == '\r') {
+			empty++
+		} else {
+			empty = 0
+		}
+
+		start = len(data)
+	}
+
+	return data, nil
+}
+
+// readAll reads r until EOF, or returns an error once more than limit bytes were read.
+func readAll(r io.Reader, limit int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > limit {
+		return nil, errTooLarge(limit)
+	}
+
+	return data, nil
+}
+
+// errTooLarge returns the error of a response exceeding limit bytes.
+func errTooLarge(limit int) error {
+	return fmt.Errorf("whois: response exceeds %d bytes", limit)
+}
+
+// Close ends the persistent connection and closes it.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started && s.err == nil {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		_, _ = io.WriteString(s.conn, "-k\r\n")
+	}
+
+	s.err = net.ErrClosed
+	return s.conn.Close()
+}
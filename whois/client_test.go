@@ -0,0 +1,202 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testResponse = "% This is the RIPE Database query service.\n" +
+	"% The objects are in RPSL format.\n" +
+	"\n" +
+	"% Information related to 'AS3333'\n" +
+	"\n" +
+	"aut-num:        AS3333\n" +
+	"as-name:        RIPE-NCC-AS\n" +
+	"source:         RIPE\n" +
+	"\n" +
+	"mntner:         RIPE-NCC-MNT\n" +
+	"source:         RIPE # Filtered\n" +
+	"\n"
+
+// fakeServer serves testResponse to every query, recording the query lines. Queries containing "hang" are never
+// answered.
+type fakeServer struct {
+	ln      net.Listener
+	queries chan string
+}
+
+// newFakeServer starts a fakeServer, stopped at the end of the test.
+func newFakeServer(t *testing.T) *fakeServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf(`net.Listen => %v`, err)
+	}
+
+	s := &fakeServer{ln: ln, queries: make(chan string, 16)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+// serve answers the queries of a connection, keeping it open while in persistent mode.
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	persistent := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		s.queries <- line
+		if strings.Contains(line, "hang") {
+			time.Sleep(time.Second)
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "-k" {
+			persistent = !persistent
+			if len(fields) == 1 {
+				return
+			}
+		}
+
+		if !persistent {
+			_, _ = conn.Write([]byte(testResponse))
+			return
+		}
+
+		_, _ = conn.Write([]byte(testResponse + "\n\n"))
+	}
+}
+
+func TestQuery(t *testing.T) {
+	s := newFakeServer(t)
+	c := NewClient(s.ln.Addr().String())
+	resp, err := c.Query(context.Background(), &Query{
+		Text:        "AS3333",
+		NoRecursion: true,
+		NoFiltering: true,
+		Types:       []string{"aut-num", "mntner"},
+		Sources:     []string{"RIPE"},
+	})
+	if err != nil {
+		t.Fatalf(`Query => %v`, err)
+	}
+
+	if got := <-s.queries; got != "-r -B -T aut-num,mntner -s RIPE AS3333" {
+		t.Errorf(`Query sent %q`, got)
+	}
+
+	if len(resp.Objects) != 2 || resp.Objects[1].Class() != "mntner" {
+		t.Errorf(`Query => %v, want 2 objects`, resp.Objects)
+	}
+
+	want := []string{
+		"This is the RIPE Database query service.",
+		"The objects are in RPSL format.",
+		"Information related to 'AS3333'",
+	}
+
+	if !reflect.DeepEqual(resp.Messages, want) {
+		t.Errorf(`Query => messages %q, want %q`, resp.Messages, want)
+	}
+}
+
+func TestSession(t *testing.T) {
+	s := newFakeServer(t)
+	c := NewClient(s.ln.Addr().String())
+	session, err := c.Open(context.Background())
+	if err != nil {
+		t.Fatalf(`Open => %v`, err)
+	}
+
+	for i, want := range []string{"-k -x 193.0.0.0/21", "-i mnt-by RIPE-NCC-MNT"} {
+		q := &Query{Text: "193.0.0.0/21", Exact: true}
+		if i == 1 {
+			q = &Query{Text: "RIPE-NCC-MNT", Inverse: []string{"mnt-by"}}
+		}
+
+		resp, err := session.Query(context.Background(), q)
+		if err != nil {
+			t.Fatalf(`Session.Query => %v`, err)
+		}
+
+		if got := <-s.queries; got != want {
+			t.Errorf(`Session.Query sent %q, want %q`, got, want)
+		}
+
+		if len(resp.Objects) != 2 {
+			t.Errorf(`Session.Query => %d objects, want 2`, len(resp.Objects))
+		}
+	}
+
+	if err := session.Close(); err != nil {
+		t.Errorf(`Session.Close => %v`, err)
+	}
+
+	if got := <-s.queries; got != "-k" {
+		t.Errorf(`Session.Close sent %q, want "-k"`, got)
+	}
+}
+
+func TestQueryDeadline(t *testing.T) {
+	s := newFakeServer(t)
+	c := NewClientWithOptions(s.ln.Addr().String(), ClientOptions{Timeout: 50 * time.Millisecond})
+	_, err := c.Query(context.Background(), &Query{Text: "hang"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(`Query => %v, want %v`, err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := c.Query(ctx, &Query{Text: "hang"}); !errors.Is(err, context.Canceled) {
+		t.Errorf(`Query => %v, want %v`, err, context.Canceled)
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	if data, err := readAll(strings.NewReader("12345"), 5); err != nil || string(data) != "12345" {
+		t.Errorf(`readAll(5) => %q, %v, want "12345"`, data, err)
+	}
+
+	if _, err := readAll(strings.NewReader("123456"), 5); err == nil {
+		t.Errorf(`readAll(5) => nil, want an error`)
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	c := NewClient("127.0.0.1:0")
+	for _, q := range []*Query{
+		{Text: ""},
+		{Text: "AS3333\r\n-k"},
+		{Text: "AS3333", Types: []string{"aut-num mntner"}},
+		{Text: "AS3333", AllMoreSpecific: true, AllLessSpecific: true},
+	} {
+		if _, err := c.Query(context.Background(), q); err == nil {
+			t.Errorf(`Query(%q) => nil, want an error`, q.String())
+		}
+	}
+}
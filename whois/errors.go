@@ -0,0 +1,63 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is an error reported by a whois server in a "%ERROR:<code>: <message>" comment. Errors with the same code
+// match with errors.Is, so that the sentinel errors below can be used to check the cause of an error:
+//
+//	if errors.Is(err, whois.ErrNoEntries) {
+//	    ...
+//	}
+type Error struct {
+	// Code is the numeric code of the error, such as 101.
+	Code int
+	// Message is the text following the code, such as "no entries found".
+	Message string
+	// Details are the comment lines following the error, such as "No entries found in source RIPE.".
+	Details []string
+}
+
+// Errors reported by the RIPE database query service.
+var (
+	ErrNoEntries         = &Error{Code: 101, Message: "no entries found"}
+	ErrInvalidSource     = &Error{Code: 102, Message: "unknown source"}
+	ErrUnknownType       = &Error{Code: 103, Message: "unknown object type"}
+	ErrUnknownAttribute  = &Error{Code: 104, Message: "unknown attribute"}
+	ErrNotSearchable     = &Error{Code: 105, Message: "attribute is not searchable"}
+	ErrNoSearchKey       = &Error{Code: 106, Message: "no search key specified"}
+	ErrLineTooLong       = &Error{Code: 107, Message: "input line too long"}
+	ErrBadCharacter      = &Error{Code: 108, Message: "bad character in input"}
+	ErrInvalidFlags      = &Error{Code: 109, Message: "invalid combination of flags passed"}
+	ErrDuplicateFlag     = &Error{Code: 110, Message: "multiple use of flag"}
+	ErrInvalidOption     = &Error{Code: 111, Message: "invalid option supplied"}
+	ErrUnsupportedQuery  = &Error{Code: 112, Message: "unsupported query"}
+	ErrInvalidSearchKey  = &Error{Code: 115, Message: "invalid search key"}
+	ErrAccessDenied      = &Error{Code: 201, Message: "access denied"}
+	ErrAccessLimit       = &Error{Code: 202, Message: "access control limit reached"}
+	ErrAddressPassing    = &Error{Code: 203, Message: "address passing not allowed"}
+	ErrReferralLimit     = &Error{Code: 204, Message: "maximum referral lines exceeded"}
+	ErrConnectionClosed  = &Error{Code: 205, Message: "connection has been closed"}
+	ErrConnectionRefused = &Error{Code: 206, Message: "connection refused"}
+)
+
+// Error returns a string representation of the Error.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("whois: error %d: %s", e.Code, e.Message)
+	if len(e.Details) > 0 {
+		msg += " (" + strings.Join(e.Details, " ") + ")"
+	}
+
+	return msg
+}
+
+// Is returns true if target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
@@ -0,0 +1,350 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+	"github.com/frederic-arr/rpsl-go/internal/netctx"
+)
+
+// ErrKeyNotFound is returned for the "D" answers of IRRd servers, sent when the queried key does not exist.
+var ErrKeyNotFound = errors.New("whois: key not found")
+
+// MaxIRRResponse is the largest length of an "A" answer accepted by ReadIRRResponse, in bytes.
+const MaxIRRResponse = 256 << 20
+
+// IRRError is an error reported by an IRRd server in an "F" answer.
+type IRRError struct {
+	// Message is the text following the F, such as "Invalid AS number".
+	Message string
+}
+
+// Error returns a string representation of the IRRError.
+func (e *IRRError) Error() string {
+	return "whois: irrd error: " + e.Message
+}
+
+// ReadIRRResponse reads the answer of an IRRd server to a "!" command:
+//
+//   - "A<length>", followed by length bytes of data and a "C" line, returns the data without its final newline;
+//   - "C" returns empty data;
+//   - "D" returns ErrKeyNotFound;
+//   - "F <message>" returns an *IRRError.
+//
+// An "A" answer longer than MaxIRRResponse returns an error.
+func ReadIRRResponse(r *bufio.Reader) ([]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case line == "C":
+		return []byte{}, nil
+	case line == "D":
+		return nil, ErrKeyNotFound
+	case strings.HasPrefix(line, "F"):
+		return nil, &IRRError{Message: strings.TrimSpace(line[1:])}
+	case !strings.HasPrefix(line, "A"):
+		return nil, fmt.Errorf("whois: invalid irrd answer '%s'", line)
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("whois: invalid irrd answer length '%s'", line)
+	}
+
+	if n > MaxIRRResponse {
+		return nil, fmt.Errorf("whois: irrd answer length %d exceeds %d bytes", n, MaxIRRResponse)
+	}
+
+	// The buffer grows as the data is read, instead of trusting the announced length.
+	var data bytes.Buffer
+	if _, err := io.CopyN(&data, r, int64(n)); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	if end, err := readLine(r); err != nil {
+		return nil, err
+	} else if end != "C" {
+		return nil, fmt.Errorf("whois: invalid irrd answer end '%s'", end)
+	}
+
+	return bytes.TrimSuffix(data.Bytes(), []byte("\n")), nil
+}
+
+// WriteIRRResponse writes the answer to a "!" command, framed as read by ReadIRRResponse. Non-empty data is sent in an
+// "A" answer, and empty data in a "C" answer. An error matching ErrKeyNotFound is sent in a "D" answer, and any other
+// error in an "F" answer.
+func WriteIRRResponse(w io.Writer, data []byte, err error) error {
+	var answer string
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		answer = "D\n"
+	case err != nil:
+		var irrErr *IRRError
+		msg := err.Error()
+		if errors.As(err, &irrErr) {
+			msg = irrErr.Message
+		}
+
+		answer = "F " + strings.ReplaceAll(msg, "\n", " ") + "\n"
+	case len(data) == 0:
+		answer = "C\n"
+	default:
+		text := strings.TrimSuffix(string(data), "\n") + "\n"
+		answer = fmt.Sprintf("A%d\n%sC\n", len(text), text)
+	}
+
+	_, err = io.WriteString(w, answer)
+	return err
+}
+
+// readLine reads a line without its line ending.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", unexpectedEOF(err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, for answers cut short.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// checkIRRCommand returns an error if the command is not a single "!" command.
+func checkIRRCommand(command string) error {
+	if !strings.HasPrefix(command, "!") || strings.ContainsAny(command, "\r\n") {
+		return fmt.Errorf("whois: invalid irrd command '%s'", command)
+	}
+
+	return nil
+}
+
+// IRRQuery sends a single IRRd "!" command, such as "!gAS3333", on a new connection and returns the data of the
+// answer. Errors reported by the server are returned as ErrKeyNotFound or *IRRError.
+func (c *Client) IRRQuery(ctx context.Context, command string) ([]byte, error) {
+	if err := checkIRRCommand(command); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	conn, err := c.opts.Dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+	defer netctx.Watch(ctx, conn)()
+
+	if _, err := io.WriteString(conn, command+"\n"); err != nil {
+		return nil, netctx.Err(ctx, err)
+	}
+
+	data, err := ReadIRRResponse(bufio.NewReader(conn))
+	if err != nil {
+		return nil, irrError(ctx, err)
+	}
+
+	return data, nil
+}
+
+// OpenIRR opens a persistent connection to an IRRd server, in the "!!" mode used by tools such as bgpq4.
+func (c *Client) OpenIRR(ctx context.Context) (*IRRSession, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	conn, err := c.opts.Dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer netctx.Watch(ctx, conn)()
+	if _, err := io.WriteString(conn, "!!\n"); err != nil {
+		conn.Close()
+		return nil, netctx.Err(ctx, err)
+	}
+
+	return &IRRSession{sess: Session{client: c, conn: conn, r: bufio.NewReader(conn), started: true}}, nil
+}
+
+// irrError returns the error of the context if it is done, leaving the errors reported by the server untouched.
+func irrError(ctx context.Context, err error) error {
+	var irrErr *IRRError
+	if errors.Is(err, ErrKeyNotFound) || errors.As(err, &irrErr) {
+		return err
+	}
+
+	return netctx.Err(ctx, err)
+}
+
+// IRRSession is a persistent connection to an IRRd server. Commands are sent one at a time. An IRRSession is safe for
+// concurrent use.
+type IRRSession struct {
+	sess Session
+}
+
+// Query sends a "!" command, such as "!gAS3333", and returns the data of the answer. Errors reported by the server are
+// returned as ErrKeyNotFound or *IRRError. After any other error, the IRRSession is unusable and must be closed.
+func (s *IRRSession) Query(ctx context.Context, command string) ([]byte, error) {
+	if err := checkIRRCommand(command); err != nil {
+		return nil, err
+	}
+
+	s.sess.mu.Lock()
+	defer s.sess.mu.Unlock()
+	if s.sess.err != nil {
+		return nil, s.sess.err
+	}
+
+	ctx, cancel := s.sess.client.context(ctx)
+	defer cancel()
+	defer netctx.Watch(ctx, s.sess.conn)()
+
+	if _, err := io.WriteString(s.sess.conn, command+"\n"); err != nil {
+		s.sess.err = netctx.Err(ctx, err)
+		return nil, s.sess.err
+	}
+
+	data, err := ReadIRRResponse(s.sess.r)
+	if err != nil {
+		err = irrError(ctx, err)
+		var irrErr *IRRError
+		if !errors.Is(err, ErrKeyNotFound) && !errors.As(err, &irrErr) {
+			s.sess.err = err
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Close ends the persistent connection with the "!q" command and closes it.
+func (s *IRRSession) Close() error {
+	s.sess.mu.Lock()
+	defer s.sess.mu.Unlock()
+	if s.sess.err == nil {
+		_, _ = io.WriteString(s.sess.conn, "!q\n")
+	}
+
+	s.sess.err = net.ErrClosed
+	return s.sess.conn.Close()
+}
+
+// Prefixes returns the prefixes of the route objects originated by an AS number with the "!g" command, or of the
+// route6 objects with the "!6" command.
+func (s *IRRSession) Prefixes(ctx context.Context, asn uint32, ipv6 bool) ([]netip.Prefix, error) {
+	command := "!g"
+	if ipv6 {
+		command = "!6"
+	}
+
+	data, err := s.Query(ctx, fmt.Sprintf("%sAS%d", command, asn))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrefixes(data)
+}
+
+// SetPrefixes returns the prefixes of the routes of an as-set or route-set with the "!a" command. Family is 4 or 6 to
+// restrict the prefixes to IPv4 or IPv6, or 0 for both.
+func (s *IRRSession) SetPrefixes(ctx context.Context, name string, family int) ([]netip.Prefix, error) {
+	command := "!a"
+	if family != 0 {
+		command += strconv.Itoa(family)
+	}
+
+	data, err := s.Query(ctx, command+name)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrefixes(data)
+}
+
+// Members returns the members of an as-set or route-set with the "!i" command. When recursive is true, the nested
+// sets are expanded.
+func (s *IRRSession) Members(ctx context.Context, name string, recursive bool) ([]string, error) {
+	command := "!i" + name
+	if recursive {
+		command += ",1"
+	}
+
+	data, err := s.Query(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(data)), nil
+}
+
+// Routes returns the route and route6 objects of a prefix with the "!r" command. Option is empty for an exact match,
+// or one of "l" (one level less specific), "L" (all less specific) and "M" (all more specific).
+func (s *IRRSession) Routes(ctx context.Context, prefix netip.Prefix, option string) ([]rpsl.Object, error) {
+	command := "!r" + prefix.String()
+	if option != "" {
+		command += "," + option
+	}
+
+	data, err := s.Query(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpsl.ParseMany(string(data))
+}
+
+// Object returns the objects of a class with a primary key with the "!m" command.
+func (s *IRRSession) Object(ctx context.Context, class string, key string) ([]rpsl.Object, error) {
+	data, err := s.Query(ctx, fmt.Sprintf("!m%s,%s", class, key))
+	if err != nil {
+		return nil, err
+	}
+
+	return rpsl.ParseMany(string(data))
+}
+
+// SetSources restricts the following commands to the given sources with the "!s" command.
+func (s *IRRSession) SetSources(ctx context.Context, sources ...string) error {
+	_, err := s.Query(ctx, "!s"+strings.Join(sources, ","))
+	return err
+}
+
+// parsePrefixes parses a list of prefixes separated by spaces.
+func parsePrefixes(data []byte) ([]netip.Prefix, error) {
+	fields := strings.Fields(string(data))
+	prefixes := make([]netip.Prefix, 0, len(fields))
+	for _, field := range fields {
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("whois: invalid prefix '%s'", field)
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
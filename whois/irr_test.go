@@ -0,0 +1,55 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIRRResponse(t *testing.T) {
+	tests := []struct {
+		data   string
+		err    error
+		framed string
+	}{
+		{"192.0.2.0/24 198.51.100.0/24", nil, "A29\n192.0.2.0/24 198.51.100.0/24\nC\n"},
+		{"", nil, "C\n"},
+		{"", ErrKeyNotFound, "D\n"},
+		{"", &IRRError{Message: "Invalid AS number"}, "F Invalid AS number\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteIRRResponse(&buf, []byte(tt.data), tt.err); err != nil {
+			t.Fatalf(`WriteIRRResponse => %v`, err)
+		}
+
+		if buf.String() != tt.framed {
+			t.Errorf(`WriteIRRResponse(%q, %v) => %q, want %q`, tt.data, tt.err, buf.String(), tt.framed)
+		}
+
+		data, err := ReadIRRResponse(bufio.NewReader(&buf))
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf(`ReadIRRResponse(%q) => %v, want %v`, tt.framed, err, tt.err)
+			}
+
+			continue
+		}
+
+		if err != nil || string(data) != tt.data {
+			t.Errorf(`ReadIRRResponse(%q) => %q, %v, want %q`, tt.framed, data, err, tt.data)
+		}
+	}
+
+	for _, framed := range []string{"A10\nshort\n", "X\n", "A5\nAS1 \nE\n", "A99999999999999\n"} {
+		if _, err := ReadIRRResponse(bufio.NewReader(strings.NewReader(framed))); err == nil || errors.Is(err, ErrKeyNotFound) {
+			t.Errorf(`ReadIRRResponse(%q) => %v, want error`, framed, err)
+		}
+	}
+}
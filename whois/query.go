@@ -0,0 +1,191 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Query is a whois query with the flags of the RIPE database query service.
+type Query struct {
+	// Text is the search key, such as "AS3333", "193.0.0.0/21" or "RIPE-NCC-MNT".
+	Text string
+	// NoRecursion disables the lookup of the contacts referenced by the objects found (-r).
+	NoRecursion bool
+	// NoFiltering returns the objects unfiltered, including e-mail addresses (-B).
+	NoFiltering bool
+	// Types restricts the objects returned to the given classes (-T).
+	Types []string
+	// Inverse looks up the objects referencing Text in the given attributes (-i).
+	Inverse []string
+	// AllMoreSpecific returns all the more specific objects of an address range (-M).
+	AllMoreSpecific bool
+	// OneMoreSpecific returns the objects one level more specific than an address range (-m).
+	OneMoreSpecific bool
+	// AllLessSpecific returns all the less specific objects of an address range, including the exact match (-L).
+	AllLessSpecific bool
+	// OneLessSpecific returns the object one level less specific than an address range (-l).
+	OneLessSpecific bool
+	// Exact returns only the objects matching Text exactly (-x).
+	Exact bool
+	// PrimaryKeys returns only the primary keys of the objects (-K).
+	PrimaryKeys bool
+	// Sources restricts the objects returned to the given sources (-s, or --sources).
+	Sources []string
+}
+
+// ParseQuery parses a query line, such as "-r -T route 193.0.0.0/21". The client identification flag (-V) is
+// ignored, and flags that cannot be represented by a Query, such as -k, are reported as errors.
+func ParseQuery(line string) (*Query, error) {
+	q := &Query{}
+	fields := strings.Fields(line)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if !strings.HasPrefix(field, "-") || len(field) < 2 {
+			q.Text = strings.Join(fields[i:], " ")
+			break
+		}
+
+		// Flags taking an argument.
+		switch field {
+		case "-T", "--select-types", "-i", "--inverse", "-s", "--sources", "-V", "--client":
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("whois: flag %s requires an argument", field)
+			}
+
+			i++
+			values := strings.Split(fields[i], ",")
+			switch field {
+			case "-T", "--select-types":
+				q.Types = append(q.Types, values...)
+			case "-i", "--inverse":
+				q.Inverse = append(q.Inverse, values...)
+			case "-s", "--sources":
+				q.Sources = append(q.Sources, values...)
+			}
+
+			continue
+		}
+
+		// Grouped single-letter flags, such as -rBG.
+		if field[1] == '-' {
+			return nil, fmt.Errorf("whois: unknown flag %s", field)
+		}
+
+		for _, flag := range field[1:] {
+			switch flag {
+			case 'r':
+				q.NoRecursion = true
+			case 'B':
+				q.NoFiltering = true
+			case 'M':
+				q.AllMoreSpecific = true
+			case 'm':
+				q.OneMoreSpecific = true
+			case 'L':
+				q.AllLessSpecific = true
+			case 'l':
+				q.OneLessSpecific = true
+			case 'x':
+				q.Exact = true
+			case 'K':
+				q.PrimaryKeys = true
+			case 'G':
+				// Grouping is not supported, so disabling it has no effect.
+			default:
+				return nil, fmt.Errorf("whois: unknown flag -%c", flag)
+			}
+		}
+	}
+
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// String returns the query line sent to the server, without line ending.
+func (q *Query) String() string {
+	var flags []string
+	if q.NoRecursion {
+		flags = append(flags, "-r")
+	}
+
+	if q.NoFiltering {
+		flags = append(flags, "-B")
+	}
+
+	if len(q.Types) > 0 {
+		flags = append(flags, "-T", strings.Join(q.Types, ","))
+	}
+
+	if len(q.Inverse) > 0 {
+		flags = append(flags, "-i", strings.Join(q.Inverse, ","))
+	}
+
+	if q.AllMoreSpecific {
+		flags = append(flags, "-M")
+	}
+
+	if q.OneMoreSpecific {
+		flags = append(flags, "-m")
+	}
+
+	if q.AllLessSpecific {
+		flags = append(flags, "-L")
+	}
+
+	if q.OneLessSpecific {
+		flags = append(flags, "-l")
+	}
+
+	if q.Exact {
+		flags = append(flags, "-x")
+	}
+
+	if q.PrimaryKeys {
+		flags = append(flags, "-K")
+	}
+
+	if len(q.Sources) > 0 {
+		flags = append(flags, "-s", strings.Join(q.Sources, ","))
+	}
+
+	return strings.Join(append(flags, q.Text), " ")
+}
+
+// validate returns an error if the query cannot be sent as a single line.
+func (q *Query) validate() error {
+	if strings.TrimSpace(q.Text) == "" {
+		return errors.New("whois: empty query")
+	}
+
+	if strings.ContainsAny(q.Text, "\r\n") {
+		return fmt.Errorf("whois: invalid query '%s'", q.Text)
+	}
+
+	matches := 0
+	for _, set := range []bool{q.AllMoreSpecific, q.OneMoreSpecific, q.AllLessSpecific, q.OneLessSpecific, q.Exact} {
+		if set {
+			matches++
+		}
+	}
+
+	if matches > 1 {
+		return errors.New("whois: only one of -x, -l, -L, -m and -M can be used")
+	}
+
+	for _, values := range [][]string{q.Types, q.Inverse, q.Sources} {
+		for _, value := range values {
+			if value == "" || strings.ContainsAny(value, " \t\r\n,") {
+				return fmt.Errorf("whois: invalid flag value '%s'", value)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/frederic-arr/rpsl-go"
+)
+
+// Response is the response of a whois server to a query.
+type Response struct {
+	// Objects are the objects of the response.
+	Objects []rpsl.Object
+	// Messages are the non-empty comment lines of the response, without the leading '%' and space.
+	Messages []string
+	// Sections group the objects by the "Information related to" comments preceding them. Objects preceding the
+	// first of these comments are in a section with an empty Key.
+	Sections []Section
+	// Errors are the errors reported by the server in "%ERROR" comments.
+	Errors []*Error
+}
+
+// Section is a group of objects related to the same primary object, such as an inetnum and the contacts it
+// references.
+type Section struct {
+	// Key is the quoted text of the "Information related to" comment, such as "193.0.0.0 - 193.0.7.255".
+	Key string
+	// AbuseContact is the e-mail address of the "Abuse contact for" comment of the section, if any.
+	AbuseContact string
+	// Objects are the objects of the section.
+	Objects []rpsl.Object
+}
+
+// AbuseContact returns the first abuse contact of the response, or an empty string if there is none.
+func (r *Response) AbuseContact() string {
+	for _, s := range r.Sections {
+		if s.AbuseContact != "" {
+			return s.AbuseContact
+		}
+	}
+
+	return ""
+}
+
+// Err returns the first error reported by the server, or nil if there is none.
+func (r *Response) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	return r.Errors[0]
+}
+
+// ParseResponse parses the objects, the messages and the errors of a whois response. If the server reported an error,
+// the Response is returned along with its first *Error.
+func ParseResponse(data []byte) (*Response, error) {
+	resp := &Response{}
+	current := -1 // Index of the current section.
+	var chunk bytes.Buffer
+	var last *Error
+
+	// flush parses the objects of the current section.
+	flush := func() error {
+		objs, err := rpsl.ParseMany(chunk.String())
+		chunk.Reset()
+		if err != nil || len(objs) == 0 {
+			return err
+		}
+
+		if current < 0 {
+			resp.Sections = append(resp.Sections, Section{})
+			current = len(resp.Sections) - 1
+		}
+
+		resp.Sections[current].Objects = append(resp.Sections[current].Objects, objs...)
+		resp.Objects = append(resp.Objects, objs...)
+		return nil
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 || line[0] != '%' {
+			chunk.Write(line)
+			chunk.WriteByte('\n')
+			if len(line) == 0 {
+				last = nil
+			}
+
+			continue
+		}
+
+		msg := strings.TrimSpace(string(line[1:]))
+		if msg == "" {
+			continue
+		}
+
+		resp.Messages = append(resp.Messages, msg)
+		if e := parseError(msg); e != nil {
+			resp.Errors = append(resp.Errors, e)
+			last = e
+			continue
+		}
+
+		if last != nil {
+			last.Details = append(last.Details, msg)
+			continue
+		}
+
+		if key, ok := quoted(msg, "Information related to "); ok {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			resp.Sections = append(resp.Sections, Section{Key: key})
+			current = len(resp.Sections) - 1
+		} else if rest, ok := strings.CutPrefix(msg, "Abuse contact for "); ok {
+			key, email, _ := strings.Cut(rest, " is ")
+			key = strings.Trim(key, "'")
+			if current < 0 || resp.Sections[current].Key != key {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+
+				resp.Sections = append(resp.Sections, Section{Key: key})
+				current = len(resp.Sections) - 1
+			}
+
+			resp.Sections[current].AbuseContact = strings.Trim(email, "'")
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return resp, resp.Err()
+}
+
+// quoted returns the text between single quotes following prefix in msg.
+func quoted(msg string, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(msg, prefix)
+	if !ok || len(rest) < 2 || rest[0] != '\'' || rest[len(rest)-1] != '\'' {
+		return "", false
+	}
+
+	return rest[1 : len(rest)-1], true
+}
+
+// parseError parses a message of the form "ERROR:101: no entries found", or returns nil.
+func parseError(msg string) *Error {
+	rest, ok := strings.CutPrefix(msg, "ERROR:")
+	if !ok {
+		return nil
+	}
+
+	code, text, _ := strings.Cut(rest, ":")
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return nil
+	}
+
+	return &Error{Code: n, Message: strings.TrimSpace(text)}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) The RPSL Go Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseResponseSections(t *testing.T) {
+	data := "% This is the RIPE Database query service.\n" +
+		"\n" +
+		"% Information related to '193.0.0.0 - 193.0.7.255'\n" +
+		"\n" +
+		"% Abuse contact for '193.0.0.0 - 193.0.7.255' is 'abuse@ripe.net'\n" +
+		"\n" +
+		"inetnum:        193.0.0.0 - 193.0.7.255\n" +
+		"netname:        RIPE-NCC\n" +
+		"\n" +
+		"role:           RIPE NCC Operations\n" +
+		"nic-hdl:        OPS4-RIPE\n" +
+		"\n" +
+		"% Information related to '193.0.0.0/21AS3333'\n" +
+		"\n" +
+		"route:          193.0.0.0/21\n" +
+		"origin:         AS3333\n" +
+		"\n" +
+		"% This query was served by the RIPE Database Query Service version 1.112 (SHETLAND)\n" +
+		"\n"
+
+	resp, err := ParseResponse([]byte(data))
+	if err != nil {
+		t.Fatalf(`ParseResponse => %v`, err)
+	}
+
+	if len(resp.Objects) != 3 || len(resp.Sections) != 2 {
+		t.Fatalf(`ParseResponse => %d objects in %d sections, want 3 in 2`, len(resp.Objects), len(resp.Sections))
+	}
+
+	got := []string{resp.Sections[0].Key, resp.Sections[0].AbuseContact, resp.Sections[1].Key}
+	want := []string{"193.0.0.0 - 193.0.7.255", "abuse@ripe.net", "193.0.0.0/21AS3333"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(`ParseResponse => sections %q, want %q`, got, want)
+	}
+
+	if len(resp.Sections[0].Objects) != 2 || resp.Sections[1].Objects[0].Class() != "route" {
+		t.Errorf(`ParseResponse => sections %+v`, resp.Sections)
+	}
+
+	if resp.AbuseContact() != "abuse@ripe.net" {
+		t.Errorf(`Response.AbuseContact => %q, want "abuse@ripe.net"`, resp.AbuseContact())
+	}
+}
+
+func TestParseResponseErrors(t *testing.T) {
+	tests := []struct {
+		data   string
+		target error
+	}{
+		{"%ERROR:101: no entries found\n%\n% No entries found in source RIPE.\n\n", ErrNoEntries},
+		{"%ERROR:102: unknown source\n%\n% \"FOO\" is not a known RIPE source.\n\n", ErrInvalidSource},
+		{"%ERROR:201: access denied for 192.0.2.1\n%\n% Sorry, access from your host has been permanently\n\n", ErrAccessDenied},
+	}
+
+	for _, tt := range tests {
+		resp, err := ParseResponse([]byte(tt.data))
+		if !errors.Is(err, tt.target) {
+			t.Errorf(`ParseResponse(%q) => %v, want %v`, tt.data, err, tt.target)
+		}
+
+		var e *Error
+		if !errors.As(err, &e) || len(e.Details) != 1 || resp == nil || len(resp.Errors) != 1 {
+			t.Errorf(`ParseResponse(%q) => %#v, want details`, tt.data, err)
+		}
+	}
+
+	if errors.Is(ErrNoEntries, ErrAccessDenied) {
+		t.Errorf(`errors.Is(ErrNoEntries, ErrAccessDenied) => true, want false`)
+	}
+}